@@ -2,73 +2,133 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"io"
 	"log"
-	"time"
+	"os"
+	"strings"
 
-	"github.com/SofNam/devsecops-ai/pkg/ai"
-	"github.com/SofNam/devsecops-ai/pkg/reporter"
-	"github.com/SofNam/devsecops-ai/pkg/scanner"
-	"github.com/SofNam/devsecops-ai/pkg/version"
+	"github.com/SofNam/devsecops-ai/pkg/config"
 )
 
+// Exit statuses of the scanner
+const (
+	exitPassed = 0 // the scan completed and the -fail-on gate passed
+	exitGated  = 1 // findings at or above the -fail-on severity were found
+	exitError  = 2 // invalid usage, or an I/O or scan error
+)
+
+// exitStatusHelp documents the exit statuses in the usage message
+const exitStatusHelp = `
+Exit status:
+  0  scan completed and no finding reached the -fail-on severity
+  1  findings at or above the -fail-on severity were found
+  2  invalid usage, or an I/O or scan error
+`
+
+// commandsHelp lists the commands in the usage messages
+const commandsHelp = `
+Commands:
+  scan            Scan a path for security issues (default)
+  rules validate  Load and validate a rules file or model directory
+  rules list      List the loaded rules and their severities
+  version         Show version information
+
+Run "<command> -h" for the options of a command.
+`
+
 func main() {
-	// Command line flags
-	targetPath := flag.String("path", ".", "Path to scan")
-	modelPath := flag.String("model", "", "Path to AI model")
-	outputFormat := flag.String("output", "json", "Output format (json/html)")
-	outputPath := flag.String("output-path", "security-report", "Output file path")
-	showVersion := flag.Bool("version", false, "Show version information")
-
-	flag.Parse()
-
-	// Show version if requested
-	if *showVersion {
-		vInfo := version.GetVersion()
-		log.Printf("\nScanner Version Information:\n%s\n", vInfo.String())
+	args := os.Args[1:]
+
+	// Without a command, arguments are scan options as before commands
+	// existed
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		runScan(args)
 		return
 	}
 
-	// Initialize scanner
-	s := scanner.New(&scanner.Config{
-		TargetPath: *targetPath,
-		ModelPath:  *modelPath,
-	})
+	switch args[0] {
+	case "scan":
+		runScan(args[1:])
+	case "rules":
+		os.Exit(runRules(args[1:]))
+	case "version":
+		runVersion(args[1:])
+	case "help":
+		usage(os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", args[0])
+		usage(os.Stderr)
+		os.Exit(exitError)
+	}
+}
 
-	// Initialize AI detector
-	detector := ai.NewDetector(*modelPath)
+// usage writes the top-level usage message to w
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s <command> [options]\n", os.Args[0])
+	fmt.Fprint(w, commandsHelp)
+}
 
-	// Run security scan
-	findings, err := s.Scan()
-	if err != nil {
-		log.Fatalf("Scan failed: %v", err)
-	}
+// fatalf logs an error and exits with the error status
+func fatalf(format string, args ...interface{}) {
+	log.Printf(format, args...)
+	os.Exit(exitError)
+}
+
+// flagSet reports whether the named flag of fs was given on the command
+// line or set from the config file
+func flagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
 
-	// Analyze with AI
-	aiResults, err := detector.Analyze(findings)
+// readLines reads the non-empty, trimmed lines of a file
+func readLines(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		log.Fatalf("AI analysis failed: %v", err)
+		return nil, err
 	}
 
-	// Get version information
-	vInfo := version.GetVersion()
-
-	// Create report configuration
-	config := reporter.Config{
-		Version:     vInfo.Version,
-		RulesUsed:   []string{"SEC-001", "SEC-002"},
-		ScanType:    "Security Scan",
-		AIEnabled:   true,
-		TimeoutSecs: 30,
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
 	}
+	return lines, nil
+}
 
-	// Record start time for report
-	startTime := time.Now()
-
-	// Initialize reporter and generate report
-	r := reporter.New(*outputFormat, *outputPath+"."+*outputFormat)
-	if err := r.Generate(aiResults, config, *targetPath, startTime); err != nil {
-		log.Fatalf("Report generation failed: %v", err)
+// applyConfig sets flags from config file values unless they were given on
+// the command line, returning warnings for unknown or invalid options
+func applyConfig(fs *flag.FlagSet, values map[string]string) []string {
+	var warnings []string
+	for _, name := range config.Keys(values) {
+		if name == "config" || fs.Lookup(name) == nil {
+			warnings = append(warnings, fmt.Sprintf("Ignoring unknown config option: %s", name))
+			continue
+		}
+		if flagSet(fs, name) {
+			continue
+		}
+		if err := fs.Set(name, values[name]); err != nil {
+			warnings = append(warnings, fmt.Sprintf("Ignoring config option %s: %v", name, err))
+		}
 	}
+	return warnings
+}
 
-	log.Printf("Report generated successfully at: %s.%s", *outputPath, *outputFormat)
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
@@ -0,0 +1,794 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/reporter"
+)
+
+// scannerBin is the scanner binary built for the tests
+var scannerBin string
+
+// fakeAnalyzerEnv makes the test binary print the findings of an external
+// analyzer instead of running the tests
+const fakeAnalyzerEnv = "SCANNER_TEST_FAKE_ANALYZER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeAnalyzerEnv) != "" {
+		fmt.Print(`[
+			{"id": "EXT-DEFAULT", "title": "Confidence omitted", "severity": "high", "line": 1},
+			{"id": "EXT-LOW", "title": "Low confidence", "severity": "high", "line": 1, "confidence": 0.3}
+		]`)
+		os.Exit(0)
+	}
+
+	dir, err := os.MkdirTemp("", "scanner-test")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	scannerBin = filepath.Join(dir, "scanner")
+	build := exec.Command("go", "build", "-o", scannerBin, ".")
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		fmt.Fprintln(os.Stderr, "building scanner:", err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// testModel is a model whose rules flag passwords (critical) and eval
+// calls (medium)
+const testModel = `[
+	{"id": "PASSWORD", "name": "Hardcoded password", "pattern": "password\\s*=", "severity": "critical", "category": "Secrets", "description": "d"},
+	{"id": "EVAL", "name": "Eval", "pattern": "eval\\(", "severity": "medium", "category": "Injection", "description": "d"}
+]`
+
+// writeTree creates files, keyed by slash-separated relative path, in a
+// temporary directory and returns the directory
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// result is the outcome of a scanner run
+type result struct {
+	stdout, stderr string
+	code           int
+}
+
+// run runs the scanner binary with args in dir
+func run(t *testing.T, dir string, args ...string) result {
+	t.Helper()
+	cmd := exec.Command(scannerBin, args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		t.Fatalf("running scanner: %v", err)
+	}
+	return result{stdout: stdout.String(), stderr: stderr.String(), code: cmd.ProcessState.ExitCode()}
+}
+
+// scanProject creates a project of files with the test model beside it in
+// a temporary directory, returning the directory. The project is in "src"
+// and the model in "model".
+func scanProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	tree := map[string]string{"model/rules.json": testModel}
+	for name, content := range files {
+		tree["src/"+name] = content
+	}
+	return writeTree(t, tree)
+}
+
+// readReport reads a JSON report
+func readReport(t *testing.T, path string) reporter.Report {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report reporter.Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+func TestFailOnUsesReportedFindings(t *testing.T) {
+	dir := scanProject(t, map[string]string{"app.py": "eval(data)\n"})
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"finding at threshold", []string{"-fail-on", "medium"}, exitGated},
+		{"finding below threshold", []string{"-fail-on", "high"}, exitPassed},
+		{"finding filtered from report", []string{"-fail-on", "medium", "-min-severity", "high"}, exitPassed},
+		{"invalid threshold", []string{"-fail-on", "severe"}, exitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"-model", "model", "-path", "src", "-output", "json", "-output-path", "report"}, tt.args...)
+			if got := run(t, dir, args...); got.code != tt.want {
+				t.Errorf("exit status %d, want %d\n%s", got.code, tt.want, got.stderr)
+			}
+		})
+	}
+}
+
+func TestClassifierLabelsFindings(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"model/rules.json":  testModel,
+		"model/config.json": `{"confidence": 0.5, "maxFindings": 100, "modelSettings": {"threshold": 0.8, "enableCache": true}}`,
+		"src/app.py":        "eval(data)\n",
+	})
+	if got := run(t, dir, "-model", "model", "-path", "src", "-output", "json", "-output-path", "report"); got.code != exitPassed {
+		t.Fatalf("exit status %d\n%s", got.code, got.stderr)
+	}
+
+	report := readReport(t, filepath.Join(dir, "report.json"))
+	labelled := 0
+	for _, finding := range report.Findings {
+		if len(finding.Labels) > 0 {
+			labelled++
+			if finding.Labels[0].Category != finding.Category {
+				t.Errorf("finding %s labelled %s but categorized %s", finding.ID, finding.Labels[0].Category, finding.Category)
+			}
+		}
+	}
+	if labelled == 0 {
+		t.Error("no findings were classified")
+	}
+}
+
+func TestAllowlistCountReported(t *testing.T) {
+	dir := scanProject(t, map[string]string{
+		"app.py":        "eval(data)\n",
+		"vendor/lib.py": "eval(data)\npassword = 'x'\n",
+	})
+	allowlist := filepath.Join(dir, "allowlist.json")
+	if err := os.WriteFile(allowlist, []byte(`{"entries": [{"location": "vendor/**"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := run(t, dir, "-model", "model", "-path", "src", "-output", "json", "-output-path", "report", "-allowlist", allowlist)
+	if got.code != exitPassed {
+		t.Fatalf("exit status %d\n%s", got.code, got.stderr)
+	}
+
+	report := readReport(t, filepath.Join(dir, "report.json"))
+	if report.Allowlisted != 2 {
+		t.Errorf("report counts %d allowlisted findings, want 2", report.Allowlisted)
+	}
+	for _, finding := range report.Findings {
+		if filepath.Base(filepath.Dir(finding.Location)) == "vendor" {
+			t.Errorf("allowlisted finding %s at %s was reported", finding.ID, finding.Location)
+		}
+	}
+	if len(report.Findings) == 0 {
+		t.Error("finding outside the allowlist was dropped")
+	}
+}
+
+func TestApplyConfigPrecedence(t *testing.T) {
+	fs := flag.NewFlagSet("scan", flag.ContinueOnError)
+	path := fs.String("path", ".", "")
+	failOn := fs.String("fail-on", "", "")
+	workers := fs.Int("workers", 0, "")
+	fs.String("config", "", "")
+	if err := fs.Parse([]string{"-path", "cli"}); err != nil {
+		t.Fatal(err)
+	}
+
+	warnings := applyConfig(fs, map[string]string{
+		"path":    "from-config",
+		"fail-on": "high",
+		"workers": "many",
+		"bogus":   "1",
+		"config":  "other.yaml",
+	})
+
+	if *path != "cli" {
+		t.Errorf("path %q, want the command line value cli", *path)
+	}
+	if *failOn != "high" {
+		t.Errorf("fail-on %q, want the config value high", *failOn)
+	}
+	if *workers != 0 {
+		t.Errorf("workers %d, want the default after an invalid config value", *workers)
+	}
+	want := []string{
+		"Ignoring unknown config option: bogus",
+		"Ignoring unknown config option: config",
+		`Ignoring config option workers: parse error`,
+	}
+	if len(warnings) != len(want) {
+		t.Fatalf("warnings %q, want %d", warnings, len(want))
+	}
+	for i := range want {
+		if !strings.HasPrefix(warnings[i], want[i]) {
+			t.Errorf("warning %d %q, want prefix %q", i, warnings[i], want[i])
+		}
+	}
+}
+
+func TestMalformedConfigFile(t *testing.T) {
+	dir := scanProject(t, map[string]string{"app.py": "eval(data)\n"})
+	if err := os.WriteFile(filepath.Join(dir, "scanner.yaml"), []byte("fail-on: [high\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := run(t, dir, "-model", "model", "-path", "src", "-config", "scanner.yaml")
+	if got.code != exitError {
+		t.Errorf("exit status %d, want %d", got.code, exitError)
+	}
+	if !strings.Contains(got.stderr, "parsing config file") {
+		t.Errorf("stderr %q does not report the parse error", got.stderr)
+	}
+}
+
+func TestConfigFileSetsGate(t *testing.T) {
+	dir := scanProject(t, map[string]string{"app.py": "eval(data)\n"})
+	if err := os.WriteFile(filepath.Join(dir, "scanner.yaml"), []byte("fail-on: medium\noutput: json\noutput-path: report\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := run(t, dir, "-model", "model", "-path", "src", "-config", "scanner.yaml"); got.code != exitGated {
+		t.Errorf("config fail-on: exit status %d, want %d\n%s", got.code, exitGated, got.stderr)
+	}
+	if got := run(t, dir, "-model", "model", "-path", "src", "-config", "scanner.yaml", "-fail-on", "high"); got.code != exitPassed {
+		t.Errorf("command line fail-on: exit status %d, want %d\n%s", got.code, exitPassed, got.stderr)
+	}
+}
+
+func TestDryRunListsFilteredFiles(t *testing.T) {
+	dir := scanProject(t, map[string]string{
+		"app.py":        "eval(data)\n",
+		"lib/util.py":   "eval(data)\n",
+		"vendor/dep.py": "eval(data)\n",
+		"README.md":     "# App\n",
+	})
+
+	got := run(t, dir, "-model", "model", "-path", "src", "-dry-run",
+		"-include", "**/*.py", "-exclude", "vendor/**", "-output", "json", "-output-path", "report")
+	if got.code != exitPassed {
+		t.Fatalf("exit status %d\n%s", got.code, got.stderr)
+	}
+
+	want := filepath.Join("src", "app.py") + "\n" + filepath.Join("src", "lib", "util.py") + "\n2 file(s) would be scanned\n"
+	if got.stdout != want {
+		t.Errorf("dry run printed %q, want %q", got.stdout, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "report.json")); !os.IsNotExist(err) {
+		t.Errorf("dry run wrote a report: %v", err)
+	}
+}
+
+func TestRelativeLocationsMatchEarlierReports(t *testing.T) {
+	dir := scanProject(t, map[string]string{
+		"app.py":                 "eval(data)\n",
+		"a/b/c/d/e/deep.py":      "password = 'x'\n",
+		"a/b/c/d/e/also_deep.py": "eval(data)\n",
+	})
+	src := filepath.Join(dir, "src")
+	model := filepath.Join(dir, "model")
+
+	// The first report scans "." from inside the project
+	first := run(t, src, "-model", model, "-path", ".", "-relative", "-output", "json", "-output-path", filepath.Join(dir, "first"))
+	if first.code != exitPassed {
+		t.Fatalf("first scan: exit status %d\n%s", first.code, first.stderr)
+	}
+	report := readReport(t, filepath.Join(dir, "first.json"))
+	locations := make(map[string]bool)
+	for _, finding := range report.Findings {
+		locations[finding.Location] = true
+	}
+	for _, want := range []string{"app.py", "a/b/c/d/e/deep.py", "a/b/c/d/e/also_deep.py"} {
+		if !locations[want] {
+			t.Errorf("no finding at %s in %v", want, locations)
+		}
+	}
+	total := len(report.Findings)
+
+	added := filepath.Join(src, "a", "b", "c", "d", "e", "added.py")
+	if err := os.WriteFile(added, []byte("eval(more)\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Later scans name the target differently but match every earlier
+	// finding, reporting only the added one
+	tests := []struct {
+		name     string
+		args     []string
+		location string
+	}{
+		{"absolute relative", []string{"-path", src, "-relative"}, "a/b/c/d/e/added.py"},
+		{"nested relative", []string{"-path", "src", "-relative"}, "a/b/c/d/e/added.py"},
+		{"nested as scanned", []string{"-path", "src"}, filepath.Join("src", "a", "b", "c", "d", "e", "added.py")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"-model", "model", "-output", "json", "-output-path", "next",
+				"-baseline", "first.json", "-compare", "first.json"}, tt.args...)
+			if got := run(t, dir, args...); got.code != exitPassed {
+				t.Fatalf("exit status %d\n%s", got.code, got.stderr)
+			}
+
+			next := readReport(t, filepath.Join(dir, "next.json"))
+			if next.Baseline == nil || next.Baseline.NewCount != 1 || next.Baseline.UnchangedCount != total {
+				t.Errorf("baseline summary %+v, want 1 new and %d unchanged", next.Baseline, total)
+			}
+			if next.Delta == nil || next.Delta.NewCount != 1 || next.Delta.PersistingCount != total {
+				t.Fatalf("delta %+v, want 1 new and %d persisting", next.Delta, total)
+			}
+			if got := next.Delta.New[0].Location; got != tt.location {
+				t.Errorf("delta lists the new finding at %s, want %s", got, tt.location)
+			}
+			if len(next.Findings) != 1 || next.Findings[0].Location != tt.location {
+				t.Errorf("findings %+v, want only the new one at %s", next.Findings, tt.location)
+			}
+		})
+	}
+}
+
+func TestBlameWithRelativeLocations(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := scanProject(t, map[string]string{"pkg/app.py": "eval(data)\n"})
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "src"},
+		{"-c", "user.name=Ada", "-c", "user.email=ada@example.com", "commit", "-q", "-m", "add app"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	got := run(t, dir, "-model", "model", "-path", "src", "-relative", "-blame", "-output", "json", "-output-path", "report")
+	if got.code != exitPassed {
+		t.Fatalf("exit status %d\n%s", got.code, got.stderr)
+	}
+	report := readReport(t, filepath.Join(dir, "report.json"))
+	if len(report.Findings) == 0 {
+		t.Fatal("no findings")
+	}
+	for _, finding := range report.Findings {
+		if finding.Location != "pkg/app.py" || finding.Author != "Ada <ada@example.com>" {
+			t.Errorf("finding at %s blamed on %q, want pkg/app.py by Ada", finding.Location, finding.Author)
+		}
+	}
+}
+
+func TestTagsSelectRules(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"model/rules.json": `[
+			{"id": "PASSWORD", "name": "Password", "pattern": "password\\s*=", "severity": "critical", "category": "Secrets", "description": "d", "tags": ["pci"]},
+			{"id": "EVAL", "name": "Eval", "pattern": "eval\\(", "severity": "medium", "category": "Injection", "description": "d", "tags": ["owasp-a03"]}
+		]`,
+		"src/app.py": "password = 'x'\neval(data)\n",
+	})
+	if got := run(t, dir, "-model", "model", "-path", "src", "-tags", "pci", "-output", "json", "-output-path", "report"); got.code != exitPassed {
+		t.Fatalf("exit status %d\n%s", got.code, got.stderr)
+	}
+
+	report := readReport(t, filepath.Join(dir, "report.json"))
+	if len(report.Findings) == 0 {
+		t.Fatal("no findings for the selected tag")
+	}
+	for _, finding := range report.Findings {
+		if finding.RuleID != "PASSWORD" {
+			t.Errorf("finding from unselected rule %s", finding.RuleID)
+		}
+		if len(finding.Tags) != 1 || finding.Tags[0] != "pci" {
+			t.Errorf("finding %s tags %v, want [pci]", finding.ID, finding.Tags)
+		}
+	}
+	if rules := report.ScannerConfig.RulesUsed; len(rules) != 1 || rules[0] != "PASSWORD" {
+		t.Errorf("rules used %v, want [PASSWORD]", rules)
+	}
+}
+
+func TestExternalAnalyzerFindingsReported(t *testing.T) {
+	dir := scanProject(t, map[string]string{"app.py": "import os\n"})
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	analyzers, err := json.Marshal(map[string]interface{}{
+		"analyzers": []map[string]interface{}{{"name": "fake", "command": self, "extensions": []string{".py"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "analyzers.json"), analyzers, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(fakeAnalyzerEnv, "1")
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"default threshold", nil, []string{"EXT-DEFAULT"}},
+		{"lowered threshold", []string{"-min-confidence", "0.2"}, []string{"EXT-DEFAULT", "EXT-LOW"}},
+		{"without AI", []string{"-no-ai"}, []string{"EXT-DEFAULT", "EXT-LOW"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"-model", "model", "-path", "src", "-external-analyzers", "analyzers.json",
+				"-output", "json", "-output-path", "report"}, tt.args...)
+			if got := run(t, dir, args...); got.code != exitPassed {
+				t.Fatalf("exit status %d\n%s", got.code, got.stderr)
+			}
+
+			var got []string
+			for _, finding := range readReport(t, filepath.Join(dir, "report.json")).Findings {
+				got = append(got, finding.RuleID)
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("reported %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSinksReceiveReportedFindings(t *testing.T) {
+	dir := scanProject(t, map[string]string{"app.py": "password = \"hunter2hunter2\"\neval(data)\n"})
+	if got := run(t, dir, "-model", "model", "-path", "src", "-output", "json", "-output-path", "report",
+		"-min-severity", "high", "-redact-all", "-sink-file", "findings.jsonl"); got.code != exitPassed {
+		t.Fatalf("exit status %d\n%s", got.code, got.stderr)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "findings.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := readReport(t, filepath.Join(dir, "report.json"))
+
+	var sunk []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var finding struct {
+			RuleID      string `json:"ruleId"`
+			CodeSnippet string `json:"codeSnippet"`
+		}
+		if err := json.Unmarshal([]byte(line), &finding); err != nil {
+			t.Fatal(err)
+		}
+		sunk = append(sunk, finding.RuleID)
+		if strings.Contains(finding.CodeSnippet, "hunter2hunter2") {
+			t.Errorf("sink received unredacted snippet %q", finding.CodeSnippet)
+		}
+	}
+	var reported []string
+	for _, finding := range report.Findings {
+		reported = append(reported, finding.RuleID)
+	}
+	if strings.Join(sunk, ",") != strings.Join(reported, ",") || len(sunk) == 0 {
+		t.Errorf("sink received %v, want the reported %v", sunk, reported)
+	}
+}
+
+func TestStatusFileTracksGate(t *testing.T) {
+	dir := scanProject(t, map[string]string{"app.py": "eval(data)\n"})
+
+	tests := []struct {
+		name   string
+		failOn string
+		code   int
+		passed bool
+	}{
+		{"gate tripped", "medium", exitGated, false},
+		{"gate cleared", "high", exitPassed, true},
+		{"no gate", "", exitPassed, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := []string{"-model", "model", "-path", "src", "-output", "json", "-output-path", "report", "-status-file", "status.json"}
+			if tt.failOn != "" {
+				args = append(args, "-fail-on", tt.failOn)
+			}
+			if got := run(t, dir, args...); got.code != tt.code {
+				t.Fatalf("exit status %d, want %d\n%s", got.code, tt.code, got.stderr)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, "status.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var status reporter.Status
+			if err := json.Unmarshal(data, &status); err != nil {
+				t.Fatal(err)
+			}
+			report := readReport(t, filepath.Join(dir, "report.json"))
+			if status.Passed != tt.passed {
+				t.Errorf("passed %v, want %v", status.Passed, tt.passed)
+			}
+			if status.Total != report.SummaryStats.TotalFindings || status.Medium != report.SummaryStats.MediumCount {
+				t.Errorf("status %+v does not match report stats %+v", status, report.SummaryStats)
+			}
+		})
+	}
+}
+
+func TestCompareMovedFindingPersists(t *testing.T) {
+	dir := scanProject(t, map[string]string{"app.py": "eval(data)\n"})
+	args := []string{"-model", "model", "-path", "src", "-output", "json"}
+	if got := run(t, dir, append(args, "-output-path", "before")...); got.code != exitPassed {
+		t.Fatalf("exit status %d\n%s", got.code, got.stderr)
+	}
+
+	// Lines inserted above move the finding without changing it
+	if err := os.WriteFile(filepath.Join(dir, "src", "app.py"), []byte("import os\n\nprint(1)\neval(data)\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := run(t, dir, append(args, "-output-path", "after", "-compare", "before.json")...); got.code != exitPassed {
+		t.Fatalf("exit status %d\n%s", got.code, got.stderr)
+	}
+
+	report := readReport(t, filepath.Join(dir, "after.json"))
+	if report.Delta == nil {
+		t.Fatal("report has no delta")
+	}
+	if d := report.Delta; d.PersistingCount != 1 || d.NewCount != 0 || d.FixedCount != 0 {
+		t.Errorf("delta %d new, %d fixed, %d persisting, want the moved finding persisting", d.NewCount, d.FixedCount, d.PersistingCount)
+	}
+	if line := report.Findings[0].Line; line != 4 {
+		t.Errorf("finding on line %d, want 4", line)
+	}
+}
+
+func TestNoAIReportsScannerFindings(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"model/rules.json": testModel,
+		// A classifier that labels findings during AI analysis
+		"model/config.json": `{"confidence": 0.5, "maxFindings": 100, "modelSettings": {"threshold": 0.5}}`,
+		"src/app.py":        "password = 'x'\neval(data)\n",
+	})
+	scan := func(t *testing.T, args ...string) reporter.Report {
+		t.Helper()
+		args = append([]string{"-model", "model", "-path", "src", "-output", "json", "-output-path", "report"}, args...)
+		if got := run(t, dir, args...); got.code != exitPassed {
+			t.Fatalf("exit status %d\n%s", got.code, got.stderr)
+		}
+		return readReport(t, filepath.Join(dir, "report.json"))
+	}
+
+	enhanced := scan(t)
+	if !enhanced.ScannerConfig.AIEnabled {
+		t.Error("report says AI disabled")
+	}
+	labelled := 0
+	for _, finding := range enhanced.Findings {
+		if len(finding.Labels) > 0 {
+			labelled++
+		}
+	}
+	if labelled == 0 {
+		t.Fatal("no findings classified during AI analysis")
+	}
+
+	direct := scan(t, "-no-ai")
+	if direct.ScannerConfig.AIEnabled {
+		t.Error("report says AI enabled with -no-ai")
+	}
+	var rules []string
+	for _, finding := range direct.Findings {
+		rules = append(rules, finding.RuleID)
+		if len(finding.Labels) > 0 {
+			t.Errorf("finding %s classified with -no-ai", finding.ID)
+		}
+	}
+	sort.Strings(rules)
+	if strings.Join(rules, ",") != "EVAL,PASSWORD" {
+		t.Errorf("findings %v with -no-ai, want the scanner's EVAL and PASSWORD", rules)
+	}
+}
+
+func TestCommandDispatch(t *testing.T) {
+	dir := scanProject(t, map[string]string{"app.py": "eval(data)\n"})
+	bad := `[{"id": "BROKEN", "name": "b", "pattern": "(", "severity": "high", "category": "c", "description": "d"}]`
+	if err := os.WriteFile(filepath.Join(dir, "bad.json"), []byte(bad), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	scanArgs := []string{"-model", "model", "-path", "src", "-output", "json", "-fail-on", "medium"}
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		output string // expected in stdout or stderr
+		report string // report the run must write
+	}{
+		{"scan", append([]string{"scan", "-output-path", "scan"}, scanArgs...), exitGated, "", "scan.json"},
+		{"scan by default", append([]string{"-output-path", "default"}, scanArgs...), exitGated, "", "default.json"},
+		{"rules validate", []string{"rules", "validate", "model/rules.json"}, exitPassed, "model/rules.json: 2 rule(s) valid", ""},
+		{"rules validate invalid", []string{"rules", "validate", "bad.json"}, exitError, "bad.json: ", ""},
+		{"rules validate without path", []string{"rules", "validate"}, exitError, "Usage:", ""},
+		{"rules list", []string{"rules", "list", "-model", "model"}, exitPassed, "EVAL      MEDIUM", ""},
+		{"rules list file", []string{"rules", "list", "model/rules.json"}, exitPassed, "PASSWORD  CRITICAL", ""},
+		{"rules without command", []string{"rules"}, exitError, "rules <validate|list>", ""},
+		{"unknown rules command", []string{"rules", "show"}, exitError, `unknown rules command "show"`, ""},
+		{"version", []string{"version"}, exitPassed, "Scanner Version Information", ""},
+		{"help", []string{"help"}, exitPassed, "rules validate", ""},
+		{"unknown command", []string{"scna"}, exitError, `unknown command "scna"`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := run(t, dir, tt.args...)
+			if got.code != tt.code {
+				t.Errorf("exit status %d, want %d\n%s%s", got.code, tt.code, got.stdout, got.stderr)
+			}
+			if !strings.Contains(got.stdout+got.stderr, tt.output) {
+				t.Errorf("output does not contain %q:\n%s%s", tt.output, got.stdout, got.stderr)
+			}
+			if tt.report != "" {
+				if report := readReport(t, filepath.Join(dir, tt.report)); len(report.Findings) == 0 {
+					t.Error("scan reported no findings")
+				}
+			}
+		})
+	}
+}
+
+func TestRuleMetadataInJSONReport(t *testing.T) {
+	metadata := map[string]string{
+		"reference": "https://cwe.mitre.org/data/definitions/95.html",
+		"asvs":      "V5.2.4",
+		"note":      `<b>"quoted" & escaped</b>`,
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := `[
+		{"id": "EVAL", "name": "Eval", "pattern": "eval\\(", "severity": "medium", "category": "Injection", "description": "d", "metadata": ` + string(encoded) + `},
+		{"id": "PASSWORD", "name": "Hardcoded password", "pattern": "password\\s*=", "severity": "critical", "category": "Secrets", "description": "d"}
+	]`
+	dir := writeTree(t, map[string]string{
+		"model/rules.json":  rules,
+		"model/config.json": `{"confidence": 0.5, "maxFindings": 100, "modelSettings": {"threshold": 0.8}}`,
+		"src/app.py":        "eval(data)\npassword = 'x'\n",
+	})
+
+	for _, mode := range [][]string{nil, {"-no-ai"}} {
+		args := append([]string{"-model", "model", "-path", "src", "-output", "json", "-output-path", "report"}, mode...)
+		if got := run(t, dir, args...); got.code != exitPassed {
+			t.Fatalf("%v: exit status %d\n%s", mode, got.code, got.stderr)
+		}
+
+		seen := make(map[string]bool)
+		for _, finding := range readReport(t, filepath.Join(dir, "report.json")).Findings {
+			seen[finding.RuleID] = true
+			switch finding.RuleID {
+			case "EVAL":
+				if !reflect.DeepEqual(finding.Metadata, metadata) {
+					t.Errorf("%v: %s metadata %v, want %v", mode, finding.ID, finding.Metadata, metadata)
+				}
+				// With AI the metadata passes through enhancement and
+				// classification
+				if mode == nil && len(finding.Labels) == 0 {
+					t.Errorf("%s was not classified", finding.ID)
+				}
+			default:
+				if finding.Metadata != nil {
+					t.Errorf("%v: %s has metadata %v, its rule has none", mode, finding.ID, finding.Metadata)
+				}
+			}
+		}
+		if !seen["EVAL"] || !seen["PASSWORD"] {
+			t.Errorf("%v: reported rules %v, want EVAL and PASSWORD", mode, seen)
+		}
+	}
+}
+
+func TestGitHubActionsAddsAnnotations(t *testing.T) {
+	dir := scanProject(t, map[string]string{"app.py": "eval(data)\n"})
+	t.Setenv("GITHUB_ACTIONS", "true")
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"default output", nil},
+		{"explicit json", []string{"-output", "json"}},
+		{"github already configured", []string{"-output", "json,github"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reportPath := filepath.Join(dir, "report.json")
+			os.Remove(reportPath)
+			got := run(t, dir, append([]string{"-model", "model", "-path", "src", "-output-path", "report"}, tt.args...)...)
+			if got.code != exitPassed {
+				t.Fatalf("exit status %d\n%s", got.code, got.stderr)
+			}
+
+			report := readReport(t, reportPath)
+			if annotations := strings.Count(got.stdout, "::warning file=") + strings.Count(got.stdout, "::error file="); annotations != len(report.Findings) || annotations == 0 {
+				t.Errorf("%d annotations for %d findings\n%s", annotations, len(report.Findings), got.stdout)
+			}
+		})
+	}
+}
+
+func TestPinnedTimestampReproducesReports(t *testing.T) {
+	dir := scanProject(t, map[string]string{
+		"app.py":     "password = 'x'\neval(data)\n",
+		"Dockerfile": "FROM alpine:latest\n",
+	})
+	const pinned = "2024-03-01T12:00:00Z"
+	scan := func(name string) []byte {
+		t.Helper()
+		got := run(t, dir, "-model", "model", "-path", "src", "-output", "json", "-output-path", name,
+			"-timestamp", pinned, "-scan-seed", "abc123")
+		if got.code != exitPassed {
+			t.Fatalf("exit status %d\n%s", got.code, got.stderr)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, name+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	first := scan("first")
+	time.Sleep(10 * time.Millisecond)
+	if second := scan("second"); !bytes.Equal(first, second) {
+		t.Errorf("reports differ under a pinned timestamp:\n%s\n%s", first, second)
+	}
+
+	report := readReport(t, filepath.Join(dir, "first.json"))
+	if len(report.Findings) == 0 {
+		t.Fatal("no findings reported")
+	}
+	for _, finding := range report.Findings {
+		if got := finding.Timestamp.Format(time.RFC3339); got != pinned {
+			t.Errorf("%s timestamp %s, want %s", finding.ID, got, pinned)
+		}
+		if finding.FirstSeen == nil || finding.FirstSeen.Format(time.RFC3339) != pinned {
+			t.Errorf("%s first seen %v, want %s", finding.ID, finding.FirstSeen, pinned)
+		}
+	}
+
+	if got := run(t, dir, "-model", "model", "-path", "src", "-timestamp", "yesterday"); got.code != exitError {
+		t.Errorf("exit status %d for an invalid -timestamp, want %d", got.code, exitError)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/SofNam/devsecops-ai/pkg/ai"
+)
+
+// runRules runs the rules command and returns the exit status
+func runRules(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s rules <validate|list> [options]\n", os.Args[0])
+		return exitError
+	}
+
+	switch args[0] {
+	case "validate":
+		return runRulesValidate(args[1:])
+	case "list":
+		return runRulesList(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown rules command %q\n", args[0])
+		fmt.Fprintf(os.Stderr, "Usage: %s rules <validate|list> [options]\n", os.Args[0])
+		return exitError
+	}
+}
+
+// runRulesValidate loads each rules file or model directory given and
+// prints the problems found, failing if any rule is invalid
+func runRulesValidate(args []string) int {
+	fs := flag.NewFlagSet("rules validate", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s rules validate [options] <path>...\n\n", os.Args[0])
+		fmt.Fprint(fs.Output(), "Each path is a JSON or YAML rules file, or a model directory.\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+	modelPath := fs.String("model", "", "Model whose custom severities rules files may use")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitError
+	}
+
+	status := exitPassed
+	for _, path := range fs.Args() {
+		rules, err := loadRulesAt(path, *modelPath)
+		var validationErr *ai.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			for _, ruleErr := range validationErr.Errors {
+				fmt.Printf("%s: %v\n", path, ruleErr)
+			}
+			status = exitError
+		case err != nil:
+			fmt.Printf("%s: %v\n", path, err)
+			status = exitError
+		default:
+			fmt.Printf("%s: %d rule(s) valid\n", path, len(rules))
+		}
+	}
+	return status
+}
+
+// runRulesList prints the ID, severity, category and name of each rule
+// loaded from a rules file or model directory
+func runRulesList(args []string) int {
+	fs := flag.NewFlagSet("rules list", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s rules list [options] [path]\n\n", os.Args[0])
+		fmt.Fprint(fs.Output(), "path is a JSON or YAML rules file, or a model directory, defaulting to -model.\n\nOptions:\n")
+		fs.PrintDefaults()
+	}
+	modelPath := fs.String("model", ".", "Path to AI model")
+	fs.Parse(args)
+
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return exitError
+	}
+	path := *modelPath
+	if fs.NArg() == 1 {
+		path = fs.Arg(0)
+	}
+
+	// Invalid rules are reported but do not hide the valid ones
+	rules, err := loadRulesAt(path, *modelPath)
+	var validationErr *ai.ValidationError
+	if errors.As(err, &validationErr) {
+		for _, ruleErr := range validationErr.Errors {
+			fmt.Fprintf(os.Stderr, "Ignoring invalid rule: %v\n", ruleErr)
+		}
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "Rules loading failed: %v\n", err)
+		return exitError
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSEVERITY\tCATEGORY\tNAME")
+	for _, rule := range rules {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", rule.ID, rule.Severity, rule.Category, rule.Name)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Writing rules failed: %v\n", err)
+		return exitError
+	}
+	return exitPassed
+}
+
+// loadRulesAt loads a rules file, or the rules of a model directory, after
+// registering the custom severities of the model they belong to
+func loadRulesAt(path, modelPath string) ([]ai.Rule, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		modelPath = path
+	}
+
+	if modelPath != "" {
+		if err := ai.RegisterModelSeverities(modelPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if info.IsDir() {
+		return ai.LoadModelRules(path)
+	}
+	return ai.LoadRules(path)
+}
@@ -0,0 +1,581 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/ai"
+	"github.com/SofNam/devsecops-ai/pkg/allowlist"
+	"github.com/SofNam/devsecops-ai/pkg/baseline"
+	"github.com/SofNam/devsecops-ai/pkg/blame"
+	"github.com/SofNam/devsecops-ai/pkg/config"
+	"github.com/SofNam/devsecops-ai/pkg/logging"
+	"github.com/SofNam/devsecops-ai/pkg/models"
+	"github.com/SofNam/devsecops-ai/pkg/notifier"
+	"github.com/SofNam/devsecops-ai/pkg/reporter"
+	"github.com/SofNam/devsecops-ai/pkg/sbom"
+	"github.com/SofNam/devsecops-ai/pkg/scanner"
+	"github.com/SofNam/devsecops-ai/pkg/sink"
+	"github.com/SofNam/devsecops-ai/pkg/version"
+)
+
+// runScan runs the scan command, the default when no command is given
+func runScan(args []string) {
+	fs := flag.NewFlagSet("scan", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [scan] [options]\n\nOptions:\n", os.Args[0])
+		fs.PrintDefaults()
+		fmt.Fprint(fs.Output(), exitStatusHelp)
+		fmt.Fprint(fs.Output(), commandsHelp)
+	}
+
+	// Command line flags
+	configPath := fs.String("config", "", "Path to a YAML or JSON file of option values; command line flags take precedence")
+	targetPath := fs.String("path", ".", "Path to scan")
+	modelPath := fs.String("model", "", "Path to AI model")
+	noAI := fs.Bool("no-ai", false, "Report the scanner findings directly, skipping AI enhancement and rule detection")
+	rulesURL := fs.String("rules-url", "", "HTTP(S) URL of a JSON rules file used instead of the model's rules, cached locally")
+	rulesCacheDir := fs.String("rules-cache-dir", defaultRulesCacheDir(), "Directory caching rules fetched from -rules-url")
+	rulesTimeout := fs.Duration("rules-timeout", 10*time.Second, "Timeout for fetching -rules-url")
+	outputFormat := fs.String("output", "json", "Comma-separated output formats (json/html/junit/md/gitlab/github/ndjson/text)")
+	htmlTemplate := fs.String("html-template", "", "Path to a custom html/template file for HTML reports")
+	outputPath := fs.String("output-path", "security-report", "Output file path, without extension; each format adds its own. {target}, {date}, {time} and {scanid} expand per report")
+	showVersion := fs.Bool("version", false, "Show version information")
+	printSchema := fs.Bool("print-schema", false, "Print the JSON Schema of the JSON report format and exit")
+	updateURL := fs.String("update-url", "", "URL returning the latest released version, checked with -version")
+	secretEntropy := fs.Float64("secret-entropy", 0, "Flag tokens with Shannon entropy at or above this value as secrets (0 disables)")
+	secretMinLength := fs.Int("secret-min-length", 20, "Minimum token length for entropy-based secret detection")
+	contextLines := fs.Int("context-lines", 2, "Number of source lines captured around each finding (negative disables)")
+	include := fs.String("include", "", "Comma-separated globs of files to scan, relative to -path")
+	exclude := fs.String("exclude", "", "Comma-separated globs of files and directories to skip, relative to -path")
+	readStdin := fs.Bool("stdin", false, "Scan a single file's content read from stdin instead of -path")
+	stdinFilename := fs.String("stdin-filename", "stdin", "File name reported for -stdin content, used to select analyzers")
+	scanHistory := fs.Bool("history", false, "Scan the lines added by each commit in the git history of -path instead of the working tree")
+	maxCommits := fs.Int("max-commits", 0, "Scan at most this many recent commits with -history (0 means all)")
+	branch := fs.String("branch", "", "Branch or revision whose history -history scans (default HEAD)")
+	dryRun := fs.Bool("dry-run", false, "List the files that would be scanned and exit")
+	profileNames := fs.String("profile", "", "Comma-separated language profiles (go/python/javascript) or profile file paths")
+	workers := fs.Int("workers", 0, "Number of files analyzed concurrently (0 uses the CPU count)")
+	maxFileSize := fs.Int64("max-file-size", 0, "Skip files larger than this many bytes (0 means no limit)")
+	advisoryPath := fs.String("advisories", "", "Path to a JSON advisory list for checking go.mod dependencies")
+	sbomPath := fs.String("sbom", "", "Write a CycloneDX SBOM of discovered dependencies to this path")
+	followSymlinks := fs.Bool("follow-symlinks", false, "Descend into symlinked directories")
+	logLevel := fs.String("log-level", "info", "Log level (error/warn/info/debug)")
+	showProgress := fs.Bool("progress", false, "Show scan progress on stderr")
+	failOn := fs.String("fail-on", "", "Exit with code 1 if any finding is at or above this severity (critical/high/medium/low/info)")
+	minSeverity := fs.String("min-severity", "", "Only report findings at or above this severity (critical/high/medium/low/info)")
+	categories := fs.String("categories", "", "Only report findings in these comma-separated categories")
+	blameFindings := fs.Bool("blame", false, "Attach the author and commit of each finding's line from git blame")
+	allowlistPath := fs.String("allowlist", "", "Path to a JSON allowlist of accepted-risk locations")
+	baselinePath := fs.String("baseline", "", "Path to a previous JSON report; only new findings are reported")
+	comparePath := fs.String("compare", "", "Path to a previous JSON report; summarize new, fixed and persisting findings since it in the report")
+	scanSeed := fs.String("scan-seed", "", "Derive a reproducible scan ID from the target and this seed, e.g. a git commit")
+	timestamp := fs.String("timestamp", "", "Record this RFC 3339 time in the report and its findings instead of the current time, for reproducible reports")
+	tags := fs.String("tags", "", "Only apply rules carrying one of these comma-separated tags, e.g. pci,owasp-a03")
+	webhookURL := fs.String("webhook", "", "POST the JSON report to this URL after generation")
+	webhookTimeout := fs.Duration("webhook-timeout", notifier.DefaultTimeout, "Timeout for each webhook request")
+	webhookRequired := fs.Bool("webhook-required", false, "Fail the scan when the webhook cannot be notified")
+	slackURL := fs.String("slack-webhook", "", "Slack incoming-webhook URL receiving a digest of the scan")
+	slackThreshold := fs.String("slack-threshold", "critical", "Only notify Slack when a finding is at or above this severity")
+	externalPath := fs.String("external-analyzers", "", "Path to a JSON or YAML file of external analyzer executables to run")
+	minConfidence := fs.Float64("min-confidence", 0, "Drop findings whose confidence is below this value (0-1), overriding the model configuration")
+	severityPolicy := fs.String("severity-policy", "", "How AI enhancement may change severities: freeze, augment-only or free, overriding the model configuration (default freeze)")
+	redactSecrets := fs.Bool("redact", false, "Mask secret values in the snippets of secret findings")
+	redactAll := fs.Bool("redact-all", false, "Mask secret-like values in the snippets of all findings")
+	sinkFiles := fs.String("sink-file", "", "Comma-separated files to append final findings to as JSON Lines, in addition to the report")
+	compress := fs.Bool("compress", false, "Gzip JSON and NDJSON reports, writing <output-path>.json.gz")
+	statusFile := fs.String("status-file", "", "Write a compact JSON summary of the scan outcome and -fail-on gate to this path")
+	since := fs.String("since", "", "Only scan files modified at or after this RFC3339 time, e.g. 2024-01-02T15:04:05Z")
+	scanArchives := fs.Bool("archives", false, "Scan the contents of .zip, .tar and .tar.gz files")
+	maxArchiveSize := fs.Int64("max-archive-size", 0, "Stop reading an archive after this many decompressed bytes (0 uses 100 MiB)")
+	cacheDir := fs.String("cache-dir", "", "Directory caching per-file results; unchanged files are not re-analyzed")
+	relativePaths := fs.Bool("relative", false, "Report finding locations relative to -path")
+	changedFilesPath := fs.String("changed-files", "", "Path to a newline-separated list of files to scan, e.g. from git diff --name-only")
+
+	fs.Parse(args)
+
+	// Fill options not given on the command line from the config file
+	if *configPath != "" {
+		values, err := config.Load(*configPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitError)
+		}
+		for _, warning := range applyConfig(fs, values) {
+			log.Printf("Warning: %s", warning)
+		}
+	}
+
+	// Annotate findings inline when running in GitHub Actions, alongside
+	// the configured formats
+	if os.Getenv("GITHUB_ACTIONS") == "true" && !slices.Contains(splitList(*outputFormat), "github") {
+		*outputFormat += ",github"
+	}
+
+	level, err := logging.ParseLevel(*logLevel)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		fs.Usage()
+		os.Exit(exitError)
+	}
+	logger := logging.New(os.Stderr, level)
+
+	// Print the report schema if requested
+	if *printSchema {
+		schema, err := reporter.Schema()
+		if err != nil {
+			fatalf("Schema generation failed: %v", err)
+		}
+		fmt.Println(string(schema))
+		return
+	}
+
+	// Show version if requested
+	if *showVersion {
+		printVersion(*updateURL, logger)
+		return
+	}
+
+	// Validate the failure threshold before doing any work
+	var failThreshold models.Severity
+	if *failOn != "" {
+		failThreshold, err = models.ParseSeverity(*failOn)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid -fail-on: %v\n", err)
+			fs.Usage()
+			os.Exit(exitError)
+		}
+	}
+
+	var slackSeverity models.Severity
+	if *slackURL != "" {
+		slackSeverity, err = models.ParseSeverity(*slackThreshold)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid -slack-threshold: %v\n", err)
+			fs.Usage()
+			os.Exit(exitError)
+		}
+	}
+
+	if flagSet(fs, "min-confidence") && (*minConfidence < 0 || *minConfidence > 1) {
+		fmt.Fprintf(os.Stderr, "invalid -min-confidence: must be between 0 and 1, got %v\n", *minConfidence)
+		fs.Usage()
+		os.Exit(exitError)
+	}
+	if *severityPolicy != "" {
+		if _, err := ai.ParseSeverityPolicy(*severityPolicy); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid -severity-policy: %v\n", err)
+			fs.Usage()
+			os.Exit(exitError)
+		}
+	}
+
+	var modifiedSince time.Time
+	if *since != "" {
+		modifiedSince, err = time.Parse(time.RFC3339, *since)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid -since: %v\n", err)
+			fs.Usage()
+			os.Exit(exitError)
+		}
+	}
+
+	// Every time recorded in the report and its findings comes from clock
+	clock := time.Now
+	if *timestamp != "" {
+		pinned, err := time.Parse(time.RFC3339, *timestamp)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid -timestamp: %v\n", err)
+			fs.Usage()
+			os.Exit(exitError)
+		}
+		clock = func() time.Time { return pinned }
+	}
+
+	// Build report filters
+	var filters []reporter.FilterFunc
+	if *minSeverity != "" {
+		severity, err := models.ParseSeverity(*minSeverity)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid -min-severity: %v\n", err)
+			fs.Usage()
+			os.Exit(exitError)
+		}
+		filters = append(filters, reporter.MinSeverity(severity))
+	}
+	if *categories != "" {
+		filters = append(filters, reporter.CategoryIn(strings.Split(*categories, ",")...))
+	}
+
+	// Load language profiles
+	var profiles []scanner.Profile
+	if *profileNames != "" {
+		for _, name := range strings.Split(*profileNames, ",") {
+			profile, err := scanner.LoadProfile(strings.TrimSpace(name))
+			if err != nil {
+				fatalf("Profile loading failed: %v", err)
+			}
+			profiles = append(profiles, profile)
+		}
+	}
+
+	// Limit the scan to changed files if requested
+	var changedFiles []string
+	if *changedFilesPath != "" {
+		lines, err := readLines(*changedFilesPath)
+		if err != nil {
+			fatalf("Reading changed files failed: %v", err)
+		}
+		changedFiles = lines
+	}
+
+	// Report progress on a single updating line
+	var progress func(path string, scanned, total int)
+	if *showProgress {
+		progress = func(path string, scanned, total int) {
+			fmt.Fprintf(os.Stderr, "\rScanned %d/%d files", scanned, total)
+			if scanned == total {
+				fmt.Fprintln(os.Stderr)
+			}
+		}
+	}
+
+	// Fetch remote rules, falling back to the cached copy when offline
+	var rulesPath string
+	if *rulesURL != "" {
+		if !ai.IsRemoteRules(*rulesURL) {
+			fatalf("Invalid -rules-url: %s is not an http or https URL", *rulesURL)
+		}
+		rulesPath, err = ai.FetchRules(context.Background(), *rulesURL, *rulesCacheDir, *rulesTimeout, logger)
+		if err != nil {
+			fatalf("Rules loading failed: %v", err)
+		}
+	}
+
+	// Initialize scanner
+	s := scanner.New(&scanner.Config{
+		TargetPath: *targetPath,
+		ModelPath:  *modelPath,
+		RulesPath:  rulesPath,
+
+		AdvisoryPath:           *advisoryPath,
+		SecretEntropyThreshold: *secretEntropy,
+		SecretMinLength:        *secretMinLength,
+		MaxFileSizeBytes:       *maxFileSize,
+		ScanArchives:           *scanArchives,
+		MaxArchiveBytes:        *maxArchiveSize,
+		Workers:                *workers,
+		SnippetContextLines:    *contextLines,
+		FollowSymlinks:         *followSymlinks,
+		ChangedFiles:           changedFiles,
+		ModifiedSince:          modifiedSince,
+		Include:                splitList(*include),
+		Exclude:                splitList(*exclude),
+		Profiles:               profiles,
+		Tags:                   splitList(*tags),
+		CacheDir:               *cacheDir,
+		Logger:                 logger,
+		Progress:               progress,
+		Clock:                  clock,
+	})
+
+	// Register external analyzers
+	if *externalPath != "" {
+		externals, err := scanner.LoadExternalAnalyzers(*externalPath)
+		if err != nil {
+			fatalf("External analyzer loading failed: %v", err)
+		}
+		for _, ext := range externals {
+			if err := s.RegisterExternal(ext); err != nil {
+				fatalf("External analyzer loading failed: %v", err)
+			}
+		}
+	}
+
+	// List the selected files without analyzing them
+	if *dryRun {
+		files, err := s.ListFiles()
+		if err != nil {
+			fatalf("Listing files failed: %v", err)
+		}
+		for _, file := range files {
+			fmt.Println(file)
+		}
+		fmt.Printf("%d file(s) would be scanned\n", len(files))
+		return
+	}
+
+	// Initialize AI detector
+	detector := ai.NewDetectorWithLogger(*modelPath, logger)
+	if rulesPath != "" {
+		if err := detector.SetRulesPath(rulesPath); err != nil {
+			fatalf("Rules loading failed: %v", err)
+		}
+	}
+	if flagSet(fs, "min-confidence") {
+		if err := detector.SetMinConfidence(*minConfidence); err != nil {
+			fatalf("Invalid -min-confidence: %v", err)
+		}
+	}
+	if *severityPolicy != "" {
+		if err := detector.SetSeverityPolicy(ai.SeverityPolicy(*severityPolicy)); err != nil {
+			fatalf("Invalid -severity-policy: %v", err)
+		}
+	}
+	if *tags != "" {
+		detector.SetTags(splitList(*tags))
+	}
+
+	// Record start time for report
+	startTime := clock()
+
+	// Run security scan
+	var findings []models.Finding
+	switch {
+	case *readStdin:
+		findings, err = s.ScanReader(*stdinFilename, os.Stdin)
+	case *scanHistory:
+		findings, err = s.ScanHistory(context.Background(), scanner.HistoryOptions{Branch: *branch, MaxCommits: *maxCommits})
+	default:
+		findings, err = s.Scan()
+	}
+	if err != nil {
+		fatalf("Scan failed: %v", err)
+	}
+
+	// Analyze with AI unless disabled
+	aiResults := findings
+	if !*noAI {
+		aiResults, err = detector.AnalyzeTarget(context.Background(), *targetPath, findings)
+		if err != nil {
+			fatalf("AI analysis failed: %v", err)
+		}
+	}
+
+	// Drop accepted risks listed in the allowlist
+	allowlisted := 0
+	if *allowlistPath != "" {
+		list, err := allowlist.Load(*allowlistPath)
+		if err != nil {
+			fatalf("Allowlist loading failed: %v", err)
+		}
+
+		var accepted []models.Finding
+		aiResults, accepted = list.Filter(aiResults, *targetPath)
+		allowlisted = len(accepted)
+	}
+
+	// Report locations relative to the scanned path if requested. Either
+	// way fingerprints are taken relative to it before findings are matched
+	// against earlier reports, so they agree however the target was named.
+	if *relativePaths {
+		models.RelativeLocations(aiResults, *targetPath)
+	} else {
+		models.SetFingerprints(aiResults, *targetPath)
+	}
+
+	// Compare against baseline if requested, carrying first-seen times
+	// forward from it
+	var known []models.Finding
+	if *baselinePath != "" {
+		known, err = baseline.Load(*baselinePath)
+		if err != nil {
+			fatalf("Baseline loading failed: %v", err)
+		}
+	}
+	baseline.StampFirstSeen(aiResults, known, clock())
+
+	// Summarize the changes since a previous report if requested
+	var delta *reporter.Delta
+	if *comparePath != "" {
+		previous, err := baseline.Load(*comparePath)
+		if err != nil {
+			fatalf("Comparison report loading failed: %v", err)
+		}
+		delta = reporter.Compare(previous, aiResults)
+		logger.Infof("Since previous scan: %d new, %d fixed, %d persisting",
+			delta.NewCount, delta.FixedCount, delta.PersistingCount)
+	}
+
+	var baselineSummary *reporter.BaselineSummary
+	if *baselinePath != "" {
+		added, fixed, unchanged := baseline.Diff(aiResults, known)
+		baselineSummary = &reporter.BaselineSummary{
+			NewCount:       len(added),
+			FixedCount:     len(fixed),
+			UnchangedCount: len(unchanged),
+		}
+		logger.Infof("Baseline comparison: %d new, %d fixed, %d unchanged",
+			len(added), len(fixed), len(unchanged))
+
+		aiResults = added
+	}
+
+	// Attribute findings to their last change if requested
+	if *blameFindings {
+		if err := annotateBlame(context.Background(), aiResults, *targetPath, *relativePaths); err != nil {
+			logger.Warnf("Skipping git blame: %v", err)
+		}
+	}
+
+	// Get version information
+	vInfo := version.GetVersion()
+
+	// Create report configuration
+	reportConfig := reporter.Config{
+		Version:     vInfo.Version,
+		RulesUsed:   detector.RuleIDs(),
+		ScanType:    "Security Scan",
+		AIEnabled:   !*noAI,
+		TimeoutSecs: 30,
+	}
+
+	// Initialize reporter and generate report
+	formats := splitList(*outputFormat)
+	r := reporter.New(formats, *outputPath)
+	r.Clock = clock
+	r.Baseline = baselineSummary
+	r.Delta = delta
+	r.Suppressed = len(s.Suppressed())
+	r.Allowlisted = allowlisted
+	r.TemplatePath = *htmlTemplate
+	r.Filters = filters
+	r.Compress = *compress
+	switch {
+	case *redactAll:
+		r.Redact = reporter.RedactAll
+	case *redactSecrets:
+		r.Redact = reporter.RedactSecrets
+	}
+	if *scanSeed != "" {
+		r.ScanIDFunc = reporter.DeterministicScanID(*targetPath, *scanSeed)
+	}
+	report := r.Build(aiResults, reportConfig, *targetPath, startTime)
+	if err := r.Write(report); err != nil {
+		fatalf("Report generation failed: %v", err)
+	}
+
+	for _, format := range formats {
+		switch format {
+		case "github":
+			logger.Infof("Annotations written for %d findings", len(report.Findings))
+		case "text":
+		default:
+			logger.Infof("Report generated successfully at: %s", r.PathFor(report, format))
+		}
+	}
+
+	// Hand the report's filtered and redacted findings to the configured
+	// sinks; failures are reported but do not stop the scan
+	if *sinkFiles != "" {
+		var sinks []sink.Sink
+		for _, path := range splitList(*sinkFiles) {
+			file, err := sink.NewFile(path)
+			if err != nil {
+				logger.Warnf("Skipping sink %s: %v", path, err)
+				continue
+			}
+			sinks = append(sinks, file)
+		}
+		if err := writeSinks(context.Background(), sink.NewFanout(sinks...), report.Findings); err != nil {
+			logger.Warnf("Sink errors: %v", err)
+		}
+	}
+
+	// Send the report to the webhook if requested
+	if *webhookURL != "" {
+		if err := notifier.New(*webhookTimeout).Notify(context.Background(), *webhookURL, report); err != nil {
+			if *webhookRequired {
+				fatalf("Webhook notification failed: %v", err)
+			}
+			logger.Warnf("Webhook notification failed: %v", err)
+		} else {
+			logger.Infof("Report sent to webhook")
+		}
+	}
+
+	// Post a digest to Slack if requested
+	if *slackURL != "" {
+		sent, err := notifier.New(*webhookTimeout).NotifySlack(context.Background(), *slackURL, report, slackSeverity)
+		if err != nil {
+			logger.Warnf("Slack notification failed: %v", err)
+		} else if sent {
+			logger.Infof("Scan digest sent to Slack")
+		}
+	}
+
+	// Write the software bill of materials if requested
+	if *sbomPath != "" {
+		if err := sbom.Write(*sbomPath, s.Dependencies(), vInfo.Version); err != nil {
+			fatalf("SBOM generation failed: %v", err)
+		}
+		logger.Infof("SBOM generated successfully at: %s", *sbomPath)
+	}
+
+	// Fail the process when findings meet the threshold
+	gated := failThreshold != "" && reporter.ExceedsThreshold(report.Findings, failThreshold)
+
+	// Write the machine-readable outcome if requested
+	if *statusFile != "" {
+		if err := reporter.WriteStatus(*statusFile, reporter.NewStatus(report, failThreshold, !gated)); err != nil {
+			fatalf("Status file generation failed: %v", err)
+		}
+	}
+
+	if gated {
+		logger.Errorf("Findings at or above %s severity detected", failThreshold)
+		os.Exit(exitGated)
+	}
+}
+
+// writeSinks sends every finding to s and closes it, returning the
+// aggregated write and close errors
+func writeSinks(ctx context.Context, s sink.Sink, findings []models.Finding) error {
+	for _, finding := range findings {
+		// Failures are collected by the sink and reported on Close
+		s.Write(ctx, finding)
+	}
+	return s.Close()
+}
+
+// annotateBlame attributes findings to their last change with git blame.
+// Locations already made relative to root are resolved against it first.
+func annotateBlame(ctx context.Context, findings []models.Finding, root string, relative bool) error {
+	if !relative {
+		return blame.Annotate(ctx, findings)
+	}
+
+	// A file target's findings are relative to its directory
+	dir := root
+	if info, err := os.Stat(root); err == nil && !info.IsDir() {
+		dir = filepath.Dir(root)
+	}
+
+	resolved := make([]models.Finding, len(findings))
+	for i, finding := range findings {
+		resolved[i] = finding
+		resolved[i].Location = filepath.Join(dir, filepath.FromSlash(finding.Location))
+	}
+	err := blame.Annotate(ctx, resolved)
+	for i := range findings {
+		findings[i].Author = resolved[i].Author
+		findings[i].Commit = resolved[i].Commit
+	}
+	return err
+}
+
+// defaultRulesCacheDir returns the per-user directory caching remote rules
+func defaultRulesCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "devsecops-ai", "rules")
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/logging"
+	"github.com/SofNam/devsecops-ai/pkg/version"
+)
+
+// runVersion runs the version command
+func runVersion(args []string) {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s version [options]\n\nOptions:\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	updateURL := fs.String("update-url", "", "URL returning the latest released version to check against")
+	fs.Parse(args)
+
+	printVersion(*updateURL, logging.Default())
+}
+
+// printVersion logs the version information and, when updateURL is set,
+// whether a newer release is available
+func printVersion(updateURL string, logger logging.Logger) {
+	vInfo := version.GetVersion()
+	log.Printf("\nScanner Version Information:\n%s\n", vInfo.String())
+
+	if updateURL == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	update, err := version.CheckLatest(ctx, updateURL)
+	if err != nil {
+		logger.Warnf("Update check failed: %v", err)
+	} else if update.Available {
+		log.Printf("A newer version is available: %s (current %s)", update.Latest, update.Current)
+	}
+}
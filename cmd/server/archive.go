@@ -0,0 +1,138 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// errExtractLimit is returned when an archive expands beyond the allowed size
+var errExtractLimit = errors.New("extracted content exceeds size limit")
+
+// extractArchive unpacks a zip file or a (optionally gzipped) tarball into
+// dir, writing at most limit bytes of file content
+func extractArchive(archivePath, dir string, limit int64) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	header := make([]byte, 4)
+	n, _ := io.ReadFull(file, header)
+	header = header[:n]
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if bytes.HasPrefix(header, []byte("PK\x03\x04")) {
+		info, err := file.Stat()
+		if err != nil {
+			return err
+		}
+		return extractZip(file, info.Size(), dir, limit)
+	}
+
+	var r io.Reader = bufio.NewReader(file)
+	if bytes.HasPrefix(header, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return fmt.Errorf("invalid gzip stream: %v", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	return extractTar(r, dir, limit)
+}
+
+// extractZip unpacks the regular files of a zip archive
+func extractZip(r io.ReaderAt, size int64, dir string, limit int64) error {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("invalid zip archive: %v", err)
+	}
+
+	for _, entry := range archive.File {
+		if !entry.Mode().IsRegular() {
+			continue
+		}
+
+		rc, err := entry.Open()
+		if err != nil {
+			return fmt.Errorf("reading %s: %v", entry.Name, err)
+		}
+		written, err := writeEntry(dir, entry.Name, rc, limit)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+		limit -= written
+	}
+
+	return nil
+}
+
+// extractTar unpacks the regular files of a tar stream
+func extractTar(r io.Reader, dir string, limit int64) error {
+	archive := tar.NewReader(r)
+
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid tar archive: %v", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		written, err := writeEntry(dir, header.Name, archive, limit)
+		if err != nil {
+			return err
+		}
+		limit -= written
+	}
+}
+
+// writeEntry writes an archive entry below dir, rejecting names that would
+// escape it
+func writeEntry(dir, name string, r io.Reader, limit int64) (int64, error) {
+	target := filepath.Join(dir, filepath.FromSlash(name))
+	rel, err := filepath.Rel(dir, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return 0, fmt.Errorf("archive entry escapes target directory: %s", name)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return 0, err
+	}
+
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	// Read one byte past the limit to detect oversized content
+	written, err := io.Copy(file, io.LimitReader(r, limit+1))
+	if err != nil {
+		return written, fmt.Errorf("writing %s: %v", name, err)
+	}
+	if written > limit {
+		return written, errExtractLimit
+	}
+
+	return written, nil
+}
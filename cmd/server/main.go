@@ -0,0 +1,238 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/ai"
+	"github.com/SofNam/devsecops-ai/pkg/baseline"
+	"github.com/SofNam/devsecops-ai/pkg/models"
+	"github.com/SofNam/devsecops-ai/pkg/reporter"
+	"github.com/SofNam/devsecops-ai/pkg/scanner"
+	"github.com/SofNam/devsecops-ai/pkg/version"
+)
+
+// server scans uploaded source archives
+type server struct {
+	modelPath    string
+	maxUpload    int64
+	maxExtracted int64
+	detector     *ai.Detector
+	metrics      *metrics
+}
+
+func main() {
+	addr := flag.String("addr", ":8080", "Address to listen on")
+	modelPath := flag.String("model", "", "Path to AI model")
+	maxUpload := flag.Int64("max-upload", 50<<20, "Maximum upload size in bytes")
+	maxExtracted := flag.Int64("max-extracted", 500<<20, "Maximum total size of extracted files in bytes")
+	reloadInterval := flag.Duration("reload-interval", 5*time.Second, "How long to wait after the model path changes before reloading its rules (0 disables reloading)")
+
+	flag.Parse()
+
+	s := &server{
+		modelPath:    *modelPath,
+		maxUpload:    *maxUpload,
+		maxExtracted: *maxExtracted,
+		detector:     ai.NewDetector(*modelPath),
+		metrics:      newMetrics(),
+	}
+
+	go s.detector.Watch(context.Background(), *reloadInterval)
+
+	log.Printf("Listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, s.routes()); err != nil {
+		log.Fatalf("Server failed: %v", err)
+	}
+}
+
+// routes registers the HTTP handlers
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/scan", s.handleScan)
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.HandleFunc("/version", s.handleVersion)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	return mux
+}
+
+// handleHealth reports that the server is up
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Write([]byte("ok\n"))
+}
+
+// handleVersion returns the build version information
+func (s *server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(version.GetVersion())
+}
+
+// handleScan scans an uploaded zip or tarball and returns the JSON report.
+// Each request works in its own temporary directory, removed on completion.
+func (s *server) handleScan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	workDir, err := os.MkdirTemp("", "devsecops-scan-")
+	if err != nil {
+		log.Printf("Error: creating work directory: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	defer os.RemoveAll(workDir)
+
+	// Save the upload so zip archives can be read at random offsets
+	archivePath := filepath.Join(workDir, "upload")
+	if err := s.saveUpload(w, r, archivePath); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			http.Error(w, fmt.Sprintf("upload exceeds limit of %d bytes", s.maxUpload), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, fmt.Sprintf("reading upload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	sourceDir := filepath.Join(workDir, "src")
+	if err := os.Mkdir(sourceDir, 0755); err != nil {
+		log.Printf("Error: creating source directory: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if err := extractArchive(archivePath, sourceDir, s.maxExtracted); err != nil {
+		if errors.Is(err, errExtractLimit) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, fmt.Sprintf("extracting upload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	reportPath := filepath.Join(workDir, "report.json")
+	if err := s.scan(r, sourceDir, reportPath); err != nil {
+		log.Printf("Error: scan failed: %v", err)
+		http.Error(w, "scan failed", http.StatusInternalServerError)
+		return
+	}
+
+	report, err := os.Open(reportPath)
+	if err != nil {
+		log.Printf("Error: opening report: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	defer report.Close()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Vary", "Accept-Encoding")
+	if !acceptsGzip(r) {
+		io.Copy(w, report)
+		return
+	}
+
+	w.Header().Set("Content-Encoding", "gzip")
+	gz := gzip.NewWriter(w)
+	defer gz.Close()
+	io.Copy(gz, report)
+}
+
+// acceptsGzip reports whether the client accepts gzip-encoded responses
+func acceptsGzip(r *http.Request) bool {
+	for _, value := range r.Header.Values("Accept-Encoding") {
+		for _, part := range strings.Split(value, ",") {
+			coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+			if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+				continue
+			}
+			// An explicit zero quality value refuses the coding
+			if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+				if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// saveUpload copies the request body to path, enforcing the upload limit
+func (s *server) saveUpload(w http.ResponseWriter, r *http.Request, path string) error {
+	body := http.MaxBytesReader(w, r.Body, s.maxUpload)
+	defer body.Close()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, body)
+	return err
+}
+
+// scan analyzes the extracted source and writes a JSON report
+func (s *server) scan(r *http.Request, sourceDir, reportPath string) error {
+	startTime := time.Now()
+
+	// Scan with the detector's rules so a bad reload on disk does not
+	// fail scans while the detector keeps its last good rules
+	sc := scanner.New(&scanner.Config{
+		TargetPath: sourceDir,
+		ModelPath:  s.modelPath,
+		Rules:      s.detector.Rules(),
+	})
+
+	findings, err := sc.Scan()
+	if err != nil {
+		return err
+	}
+
+	results, err := s.detector.AnalyzeTarget(r.Context(), sourceDir, findings)
+	if err != nil {
+		return err
+	}
+
+	// Report paths relative to the upload rather than the temp directory
+	models.RelativeLocations(results, sourceDir)
+	baseline.StampFirstSeen(results, nil, time.Now())
+
+	config := reporter.Config{
+		Version:     version.GetVersion().Version,
+		RulesUsed:   s.detector.RuleIDs(),
+		ScanType:    "Security Scan",
+		AIEnabled:   true,
+		TimeoutSecs: 30,
+	}
+
+	rep := reporter.New([]string{"json"}, strings.TrimSuffix(reportPath, ".json"))
+	rep.Suppressed = len(sc.Suppressed())
+	if err := rep.Generate(results, config, "upload", startTime); err != nil {
+		return err
+	}
+
+	s.metrics.observeScan(results, time.Since(startTime))
+	return nil
+}
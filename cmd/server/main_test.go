@@ -0,0 +1,203 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/ai"
+	"github.com/SofNam/devsecops-ai/pkg/logging"
+	"github.com/SofNam/devsecops-ai/pkg/reporter"
+)
+
+// testRules flags hardcoded passwords
+const testRules = `[{"id": "PASSWORD", "name": "Hardcoded password", "pattern": "password\\s*=", "severity": "critical", "category": "Secrets", "description": "d"}]`
+
+// newTestServer returns a server using a model with testRules and the
+// given upload limit
+func newTestServer(t *testing.T, maxUpload int64) *httptest.Server {
+	t.Helper()
+	ts, _ := newTestServerModel(t, maxUpload)
+	return ts
+}
+
+// newTestServerModel returns a server like newTestServer and the path of
+// its model
+func newTestServerModel(t *testing.T, maxUpload int64) (*httptest.Server, string) {
+	t.Helper()
+	model := t.TempDir()
+	if err := os.WriteFile(filepath.Join(model, "rules.json"), []byte(testRules), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &server{
+		modelPath:    model,
+		maxUpload:    maxUpload,
+		maxExtracted: 1 << 20,
+		detector:     ai.NewDetectorWithLogger(model, logging.New(io.Discard, logging.LevelError)),
+		metrics:      newMetrics(),
+	}
+	ts := httptest.NewServer(s.routes())
+	t.Cleanup(ts.Close)
+	return ts, model
+}
+
+// zipArchive returns a zip archive of files keyed by name
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// upload posts body to the scan endpoint
+func upload(t *testing.T, ts *httptest.Server, body []byte) *http.Response {
+	t.Helper()
+	resp, err := http.Post(ts.URL+"/scan", "application/zip", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestScanUpload(t *testing.T) {
+	ts := newTestServer(t, 1<<20)
+	resp := upload(t, ts, zipArchive(t, map[string]string{
+		"app/config.py": "password = 'hunter2'\n",
+		"app/main.py":   "print('hello')\n",
+	}))
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %s", resp.Status)
+	}
+	var report reporter.Report
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf("decoding report: %v", err)
+	}
+
+	var found bool
+	for _, finding := range report.Findings {
+		if finding.RuleID == "PASSWORD" {
+			found = true
+			if finding.Location != "app/config.py" {
+				t.Errorf("location %q, want it relative to the upload", finding.Location)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("no PASSWORD finding in %+v", report.Findings)
+	}
+	if report.Target != "upload" {
+		t.Errorf("target %q, want upload", report.Target)
+	}
+}
+
+func TestScanAfterBadRulesReload(t *testing.T) {
+	ts, model := newTestServerModel(t, 1<<20)
+	if err := os.WriteFile(filepath.Join(model, "rules.json"), []byte(`[{"id": "PASSWORD",`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := upload(t, ts, zipArchive(t, map[string]string{"app/config.py": "password = 'hunter2'\n"}))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %s after a bad rules file, want the last good rules used", resp.Status)
+	}
+	var report reporter.Report
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf("decoding report: %v", err)
+	}
+	for _, finding := range report.Findings {
+		if finding.RuleID == "PASSWORD" {
+			return
+		}
+	}
+	t.Errorf("no PASSWORD finding in %+v", report.Findings)
+}
+
+func TestScanUploadTooLarge(t *testing.T) {
+	ts := newTestServer(t, 64)
+	resp := upload(t, ts, zipArchive(t, map[string]string{"big.py": string(bytes.Repeat([]byte("x = 1\n"), 100))}))
+
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("status %s, want 413", resp.Status)
+	}
+}
+
+func TestScanRejectsGet(t *testing.T) {
+	resp, err := http.Get(newTestServer(t, 1<<20).URL + "/scan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("status %s, want 405", resp.Status)
+	}
+}
+
+// scrape returns the metrics exported by the server
+func scrape(t *testing.T, ts *httptest.Server) string {
+	t.Helper()
+	resp, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %s", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestMetricsCountScans(t *testing.T) {
+	ts := newTestServer(t, 1<<20)
+	for _, files := range []map[string]string{
+		{"a.py": "password = 'x'\n", "b.py": "password = 'y'\n"},
+		{"c.py": "password = 'z'\n", "d.py": "print('hello')\n"},
+	} {
+		if resp := upload(t, ts, zipArchive(t, files)); resp.StatusCode != http.StatusOK {
+			t.Fatalf("status %s", resp.Status)
+		}
+	}
+
+	got := scrape(t, ts)
+	for _, want := range []string{
+		"devsec_scans_total 2\n",
+		`devsec_findings_total{severity="CRITICAL"} 3` + "\n",
+		`devsec_findings_total{severity="LOW"} 0` + "\n",
+		"devsec_scan_duration_seconds_count 2\n",
+		`devsec_scan_duration_seconds_bucket{le="+Inf"} 2` + "\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("metrics lack %q:\n%s", want, got)
+		}
+	}
+
+	// Every server has a registry of its own
+	if other := scrape(t, newTestServer(t, 1<<20)); !strings.Contains(other, "devsec_scans_total 0\n") {
+		t.Errorf("new server reports earlier scans:\n%s", other)
+	}
+}
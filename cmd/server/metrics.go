@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// scanDurationBuckets are the upper bounds, in seconds, of the scan
+// duration histogram buckets
+var scanDurationBuckets = []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// metrics holds the server collectors in a registry of their own, so that
+// each server, and each test, exports only its own scans. It is safe for
+// concurrent use.
+type metrics struct {
+	registry *prometheus.Registry
+
+	scans    prometheus.Counter
+	findings *prometheus.CounterVec
+	duration prometheus.Histogram
+}
+
+// newMetrics returns metrics with every known severity reported at zero
+func newMetrics() *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		scans: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "devsec_scans_total",
+			Help: "Number of completed scans.",
+		}),
+		findings: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "devsec_findings_total",
+			Help: "Number of findings reported, by severity.",
+		}, []string{"severity"}),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "devsec_scan_duration_seconds",
+			Help:    "Duration of completed scans.",
+			Buckets: scanDurationBuckets,
+		}),
+	}
+	m.registry.MustRegister(m.scans, m.findings, m.duration)
+
+	for _, severity := range models.Severities() {
+		m.findings.WithLabelValues(string(severity))
+	}
+	return m
+}
+
+// observeScan records a completed scan, its findings and its duration
+func (m *metrics) observeScan(findings []models.Finding, duration time.Duration) {
+	m.scans.Inc()
+	for _, finding := range findings {
+		severity := finding.Severity
+		if !severity.Valid() {
+			severity = "UNKNOWN"
+		}
+		m.findings.WithLabelValues(string(severity)).Inc()
+	}
+	m.duration.Observe(duration.Seconds())
+}
+
+// handleMetrics exports the server metrics for Prometheus
+func (s *server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	promhttp.HandlerFor(s.metrics.registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+}
@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// classification is a cached classifier result, categories ranked by score
+type classification []models.CategoryScore
+
+// lruCache is a bounded, concurrency-safe least-recently-used cache
+type lruCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	items    map[string]*list.Element
+}
+
+// lruEntry is the value stored in each list element
+type lruEntry struct {
+	key   string
+	value classification
+}
+
+// newLRUCache creates a cache holding at most capacity entries
+func newLRUCache(capacity int) *lruCache {
+	return &lruCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// get returns the cached value and marks it as recently used
+func (c *lruCache) get(key string) (classification, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+
+	return elem.Value.(*lruEntry).value, true
+}
+
+// put stores a value, evicting the least recently used entry when full
+func (c *lruCache) put(key string, value classification) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*lruEntry).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, value: value})
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// clear removes all entries
+func (c *lruCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.items = make(map[string]*list.Element)
+}
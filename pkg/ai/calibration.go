@@ -0,0 +1,123 @@
+package ai
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// CalibrationFunc maps a raw confidence score to a calibrated probability
+type CalibrationFunc func(score float64) float64
+
+// CalibrationPoint is a knot of a piecewise-linear calibration curve
+type CalibrationPoint struct {
+	Raw        float64 `json:"raw"`
+	Calibrated float64 `json:"calibrated"`
+}
+
+// CalibrationConfig selects the calibration curve applied to confidences
+type CalibrationConfig struct {
+	// Type is "identity" (the default), "piecewise" or "sigmoid"
+	Type string `json:"type"`
+	// Points are the knots of a piecewise-linear curve
+	Points []CalibrationPoint `json:"points"`
+	// Slope and Midpoint parameterize a sigmoid curve
+	Slope    float64 `json:"slope"`
+	Midpoint float64 `json:"midpoint"`
+}
+
+// IdentityCalibration leaves scores unchanged
+func IdentityCalibration(score float64) float64 {
+	return score
+}
+
+// PiecewiseLinearCalibration interpolates linearly between points, clamping
+// scores outside them to the first and last calibrated values. Calibrated
+// values must not decrease so that the mapping preserves ordering.
+func PiecewiseLinearCalibration(points []CalibrationPoint) (CalibrationFunc, error) {
+	if len(points) < 2 {
+		return nil, fmt.Errorf("piecewise calibration needs at least 2 points, got %d", len(points))
+	}
+
+	sorted := append([]CalibrationPoint(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Raw < sorted[j].Raw })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Raw == sorted[i-1].Raw {
+			return nil, fmt.Errorf("piecewise calibration has duplicate raw score %v", sorted[i].Raw)
+		}
+		if sorted[i].Calibrated < sorted[i-1].Calibrated {
+			return nil, fmt.Errorf("piecewise calibration must be non-decreasing at raw score %v", sorted[i].Raw)
+		}
+	}
+
+	return func(score float64) float64 {
+		if score <= sorted[0].Raw {
+			return sorted[0].Calibrated
+		}
+		for i := 1; i < len(sorted); i++ {
+			if score <= sorted[i].Raw {
+				lo, hi := sorted[i-1], sorted[i]
+				t := (score - lo.Raw) / (hi.Raw - lo.Raw)
+				return lo.Calibrated + t*(hi.Calibrated-lo.Calibrated)
+			}
+		}
+		return sorted[len(sorted)-1].Calibrated
+	}, nil
+}
+
+// SigmoidCalibration maps scores through a logistic curve centered on
+// midpoint; a positive slope preserves ordering
+func SigmoidCalibration(slope, midpoint float64) (CalibrationFunc, error) {
+	if slope <= 0 {
+		return nil, fmt.Errorf("sigmoid calibration slope must be positive, got %v", slope)
+	}
+
+	return func(score float64) float64 {
+		return 1 / (1 + math.Exp(-slope*(score-midpoint)))
+	}, nil
+}
+
+// newCalibration builds the calibration function described by config
+func newCalibration(config *CalibrationConfig) (CalibrationFunc, error) {
+	if config == nil {
+		return IdentityCalibration, nil
+	}
+
+	switch config.Type {
+	case "", "identity":
+		return IdentityCalibration, nil
+	case "piecewise":
+		return PiecewiseLinearCalibration(config.Points)
+	case "sigmoid":
+		return SigmoidCalibration(config.Slope, config.Midpoint)
+	default:
+		return nil, fmt.Errorf("unknown calibration type: %s", config.Type)
+	}
+}
+
+// SetCalibration sets the function applied to finding confidences during
+// analysis, or restores the identity mapping when fn is nil
+func (d *Detector) SetCalibration(fn CalibrationFunc) {
+	if fn == nil {
+		fn = IdentityCalibration
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.calibrate = fn
+	d.overrides.calibrate = true
+}
+
+// applyCalibration maps finding confidences through the calibration
+// function, clamped to [0, 1]
+func (d *Detector) applyCalibration(findings []models.Finding) {
+	if d.calibrate == nil {
+		return
+	}
+
+	for i := range findings {
+		findings[i].Confidence = math.Max(0, math.Min(1, d.calibrate(findings[i].Confidence)))
+	}
+}
@@ -0,0 +1,118 @@
+package ai
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// scores samples [-0.5, 1.5] so clamping at both ends is exercised
+func scores() []float64 {
+	var out []float64
+	for s := -0.5; s <= 1.5; s += 0.01 {
+		out = append(out, s)
+	}
+	return out
+}
+
+func TestCalibrationMonotonic(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *CalibrationConfig
+	}{
+		{"identity", &CalibrationConfig{Type: "identity"}},
+		{"piecewise", &CalibrationConfig{Type: "piecewise", Points: []CalibrationPoint{
+			{Raw: 0.9, Calibrated: 0.95}, {Raw: 0.2, Calibrated: 0.05}, {Raw: 0.5, Calibrated: 0.3}, {Raw: 0.7, Calibrated: 0.3},
+		}}},
+		{"sigmoid", &CalibrationConfig{Type: "sigmoid", Slope: 10, Midpoint: 0.6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calibrate, err := newCalibration(tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			prev := math.Inf(-1)
+			for _, score := range scores() {
+				got := calibrate(score)
+				if got < prev {
+					t.Fatalf("calibrate(%v) = %v, below %v for a lower score", score, got, prev)
+				}
+				prev = got
+			}
+		})
+	}
+}
+
+func TestIdentityCalibration(t *testing.T) {
+	for _, config := range []*CalibrationConfig{nil, {}, {Type: "identity"}} {
+		calibrate, err := newCalibration(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, score := range scores() {
+			if got := calibrate(score); got != score {
+				t.Fatalf("identity calibrate(%v) = %v", score, got)
+			}
+		}
+	}
+}
+
+func TestPiecewiseLinearCalibration(t *testing.T) {
+	calibrate, err := PiecewiseLinearCalibration([]CalibrationPoint{
+		{Raw: 0.2, Calibrated: 0.1}, {Raw: 0.6, Calibrated: 0.5}, {Raw: 1, Calibrated: 0.9},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for score, want := range map[float64]float64{0: 0.1, 0.2: 0.1, 0.4: 0.3, 0.6: 0.5, 0.8: 0.7, 1: 0.9, 1.2: 0.9} {
+		if got := calibrate(score); math.Abs(got-want) > 1e-9 {
+			t.Errorf("calibrate(%v) = %v, want %v", score, got, want)
+		}
+	}
+}
+
+func TestNewCalibrationErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config CalibrationConfig
+		want   string
+	}{
+		{"one point", CalibrationConfig{Type: "piecewise", Points: []CalibrationPoint{{Raw: 0.5}}}, "at least 2 points"},
+		{"duplicate raw", CalibrationConfig{Type: "piecewise", Points: []CalibrationPoint{{Raw: 0.5}, {Raw: 0.5, Calibrated: 1}}}, "duplicate raw score"},
+		{"decreasing", CalibrationConfig{Type: "piecewise", Points: []CalibrationPoint{{Raw: 0.2, Calibrated: 0.8}, {Raw: 0.6, Calibrated: 0.4}}}, "non-decreasing"},
+		{"flat sigmoid", CalibrationConfig{Type: "sigmoid"}, "slope must be positive"},
+		{"unknown", CalibrationConfig{Type: "isotonic"}, "unknown calibration type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newCalibration(&tt.config)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("newCalibration error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyCalibrationClamps(t *testing.T) {
+	d := newTestDetector()
+	d.SetCalibration(func(score float64) float64 { return 2*score - 0.5 })
+
+	findings := []models.Finding{{Confidence: 0.1}, {Confidence: 0.5}, {Confidence: 0.9}}
+	d.applyCalibration(findings)
+
+	for i, want := range []float64{0, 0.5, 1} {
+		if got := findings[i].Confidence; math.Abs(got-want) > 1e-9 {
+			t.Errorf("finding %d confidence %v, want %v", i, got, want)
+		}
+	}
+
+	d.SetCalibration(nil)
+	findings = []models.Finding{{Confidence: 0.42}}
+	d.applyCalibration(findings)
+	if findings[0].Confidence != 0.42 {
+		t.Errorf("nil calibration changed confidence to %v", findings[0].Confidence)
+	}
+}
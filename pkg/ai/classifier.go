@@ -1,24 +1,40 @@
 package ai
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
 
+	"github.com/SofNam/devsecops-ai/pkg/logging"
 	"github.com/SofNam/devsecops-ai/pkg/models"
 )
 
-// Classifier represents the AI-based security classifier
+// Classifier represents the AI-based security classifier. It is safe for
+// concurrent use: the loaded model is read-only and the cache locks itself.
 type Classifier struct {
+	// mu guards threshold, the only setting changed after loading
+	mu sync.RWMutex
+
 	modelPath    string
 	threshold    float64
 	categories   []string
 	initialized  bool
 	modelConfig  ModelConfig
 	categoryData map[string]CategoryFeatures
+	cache        *lruCache
+	logger       logging.Logger
+
+	// categoryDefaults maps lowercase categories to the severity given to
+	// classified findings that have none
+	categoryDefaults map[string]models.Severity
 }
 
 // ModelConfig holds AI model configuration
@@ -26,25 +42,46 @@ type ModelConfig struct {
 	Threshold   float64 `json:"threshold"`
 	BatchSize   int     `json:"batchSize"`
 	EnableCache bool    `json:"enableCache"`
+	CacheSize   int     `json:"cacheSize"`
+	MaxLabels   int     `json:"maxLabels"`
 }
 
 // CategoryFeatures holds feature data for each security category
 type CategoryFeatures struct {
-	Patterns  []string  `json:"patterns"`
-	Keywords  []string  `json:"keywords"`
-	Weights   []float64 `json:"weights"`
-	Threshold float64   `json:"threshold"`
+	Patterns       []string  `json:"patterns"`
+	Keywords       []string  `json:"keywords"`
+	Weights        []float64 `json:"weights"`
+	KeywordWeights []float64 `json:"keywordWeights"`
+	Threshold      float64   `json:"threshold"`
+
+	// compiled holds the precompiled form of each entry in Patterns
+	compiled []*regexp.Regexp
 }
 
+const (
+	// defaultPatternWeight is the weight of a pattern match when a rule sets none
+	defaultPatternWeight = 1.0
+	// defaultKeywordWeight is the weight of a keyword match when a rule sets none
+	defaultKeywordWeight = 0.5
+)
+
 // NewClassifier creates a new AI classifier instance
 func NewClassifier(modelPath string) *Classifier {
+	return NewClassifierWithLogger(modelPath, logging.Default())
+}
+
+// NewClassifierWithLogger creates a new AI classifier instance that reports
+// diagnostics to logger
+func NewClassifierWithLogger(modelPath string, logger logging.Logger) *Classifier {
 	c := &Classifier{
 		modelPath:    modelPath,
 		threshold:    0.8,
 		categoryData: make(map[string]CategoryFeatures),
+		logger:       logger,
 	}
 
 	if err := c.initialize(); err != nil {
+		c.logger.Debugf("Classifier not initialized: %v", err)
 		return c
 	}
 
@@ -60,59 +97,96 @@ func (c *Classifier) initialize() error {
 	}
 
 	// Load category data
-	categoryPath := filepath.Join(c.modelPath, "rules.json")
-	if err := c.loadCategories(categoryPath); err != nil {
+	if err := c.loadCategories(); err != nil {
 		return fmt.Errorf("failed to load categories: %v", err)
 	}
 
+	if c.modelConfig.EnableCache {
+		size := c.modelConfig.CacheSize
+		if size <= 0 {
+			size = 1000
+		}
+		c.cache = newLRUCache(size)
+	}
+
 	c.initialized = true
 	return nil
 }
 
 // loadConfig loads model configuration from JSON
 func (c *Classifier) loadConfig(path string) error {
-	data, err := os.ReadFile(path)
+	data, err := readModelFile(path)
 	if err != nil {
 		return err
 	}
 
 	var config struct {
-		ModelSettings ModelConfig `json:"modelSettings"`
-		Categories    []string    `json:"categories"`
+		ModelSettings    *ModelConfig      `json:"modelSettings"`
+		Categories       []string          `json:"categories"`
+		CategoryDefaults map[string]string `json:"categoryDefaults"`
 	}
 
 	if err := json.Unmarshal(data, &config); err != nil {
 		return err
 	}
 
-	c.modelConfig = config.ModelSettings
-	c.categories = config.Categories
-	c.threshold = config.ModelSettings.Threshold
-
-	return nil
-}
+	// Detector-only configs share the file; they leave the classifier off
+	if config.ModelSettings == nil {
+		return fmt.Errorf("no modelSettings in %s", path)
+	}
 
-// loadCategories loads category feature data
-func (c *Classifier) loadCategories(path string) error {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return err
+	c.modelConfig = *config.ModelSettings
+	c.categories = config.Categories
+	if c.modelConfig.Threshold > 0 {
+		c.threshold = c.modelConfig.Threshold
 	}
 
-	var rulesData struct {
-		Rules []Rule `json:"rules"`
+	c.categoryDefaults = make(map[string]models.Severity, len(config.CategoryDefaults))
+	for category, value := range config.CategoryDefaults {
+		severity, err := models.ParseSeverity(value)
+		if err != nil {
+			c.logger.Warnf("Ignoring default severity for category %s: %v", category, err)
+			continue
+		}
+		c.categoryDefaults[strings.ToLower(category)] = severity
 	}
 
-	if err := json.Unmarshal(data, &rulesData); err != nil {
+	return nil
+}
+
+// loadCategories loads category feature data from the model rules
+func (c *Classifier) loadCategories() error {
+	// Invalid rules, including uncompilable patterns, are skipped
+	rules, err := LoadModelRules(c.modelPath)
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		for _, ruleErr := range validationErr.Errors {
+			c.logger.Warnf("Classifier skipping invalid rule: %v", ruleErr)
+		}
+	} else if err != nil {
 		return err
 	}
 
 	// Process rules into category features
-	for _, rule := range rulesData.Rules {
+	for _, rule := range rules {
 		features := c.categoryData[rule.Category]
-		features.Patterns = append(features.Patterns, rule.Pattern)
-		features.Keywords = append(features.Keywords, rule.Keywords...)
-		features.Weights = append(features.Weights, 1.0) // Default weight
+		if rule.compiled != nil {
+			weight := rule.Weight
+			if weight <= 0 {
+				weight = defaultPatternWeight
+			}
+			features.Patterns = append(features.Patterns, rule.Pattern)
+			features.compiled = append(features.compiled, rule.compiled)
+			features.Weights = append(features.Weights, weight)
+		}
+		for _, keyword := range rule.Keywords {
+			weight, ok := rule.KeywordWeights[keyword]
+			if !ok || weight <= 0 {
+				weight = defaultKeywordWeight
+			}
+			features.Keywords = append(features.Keywords, keyword)
+			features.KeywordWeights = append(features.KeywordWeights, weight)
+		}
 		features.Threshold = c.threshold
 		c.categoryData[rule.Category] = features
 	}
@@ -120,51 +194,164 @@ func (c *Classifier) loadCategories(path string) error {
 	return nil
 }
 
+// Initialized reports whether the model configured the classifier. An
+// uninitialized classifier rejects every classification.
+func (c *Classifier) Initialized() bool {
+	return c.initialized
+}
+
 // Classify performs classification on a finding
 func (c *Classifier) Classify(finding *models.Finding) error {
 	if !c.initialized {
 		return fmt.Errorf("classifier not initialized")
 	}
 
-	// Calculate confidence scores for each category
-	scores := make(map[string]float64)
-	for category, features := range c.categoryData {
-		score := c.calculateScore(finding, features)
-		scores[category] = score
+	var key string
+	var ranked classification
+	cached := false
+	if c.cache != nil {
+		key = cacheKey(finding)
+		ranked, cached = c.cache.get(key)
 	}
 
-	// Get highest scoring category
-	bestCategory, bestScore := c.getBestCategory(scores)
+	if !cached {
+		// Calculate confidence scores for each category
+		scores := make(map[string]float64)
+		for category, features := range c.categoryData {
+			score := c.calculateScore(finding, features)
+			scores[category] = score
+		}
+
+		ranked = c.rankCategories(scores)
+
+		if c.cache != nil {
+			c.cache.put(key, ranked)
+		}
+	}
+
+	c.mu.RLock()
+	threshold := c.threshold
+	c.mu.RUnlock()
 
-	// Update finding if confidence threshold is met
-	if bestScore >= c.threshold {
-		finding.Category = bestCategory
-		finding.Confidence = bestScore
+	// Update finding if confidence threshold is met, giving findings
+	// without a severity their category's default
+	if len(ranked) > 0 && ranked[0].Score >= threshold {
+		finding.Category = ranked[0].Category
+		finding.Confidence = ranked[0].Score
+
+		if finding.Severity == "" {
+			if severity, ok := c.categoryDefaults[strings.ToLower(finding.Category)]; ok {
+				finding.Severity = severity
+			}
+		}
+	}
+
+	// Keep every category above threshold, up to maxLabels
+	maxLabels := c.modelConfig.MaxLabels
+	if maxLabels <= 0 {
+		maxLabels = 3
+	}
+
+	finding.Labels = nil
+	for _, label := range ranked {
+		if label.Score < threshold || len(finding.Labels) >= maxLabels {
+			break
+		}
+		finding.Labels = append(finding.Labels, label)
 	}
 
 	return nil
 }
 
+// ClassifyBatch classifies findings in place, processing chunks of
+// BatchSize concurrently with at most one worker per CPU
+func (c *Classifier) ClassifyBatch(findings []*models.Finding) error {
+	if !c.initialized {
+		return fmt.Errorf("classifier not initialized")
+	}
+
+	batchSize := c.modelConfig.BatchSize
+	if batchSize <= 0 {
+		batchSize = 32
+	}
+
+	var chunks [][]*models.Finding
+	for start := 0; start < len(findings); start += batchSize {
+		end := start + batchSize
+		if end > len(findings) {
+			end = len(findings)
+		}
+		chunks = append(chunks, findings[start:end])
+	}
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, runtime.NumCPU())
+
+	for _, chunk := range chunks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(chunk []*models.Finding) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			for _, finding := range chunk {
+				if err := c.Classify(finding); err != nil {
+					errOnce.Do(func() { firstErr = err })
+					return
+				}
+			}
+		}(chunk)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// ClassifyFindings classifies a slice of findings in place with ClassifyBatch
+func (c *Classifier) ClassifyFindings(findings []models.Finding) error {
+	batch := make([]*models.Finding, len(findings))
+	for i := range findings {
+		batch[i] = &findings[i]
+	}
+	return c.ClassifyBatch(batch)
+}
+
+// cacheKey identifies a finding by the inputs that affect its classification
+func cacheKey(finding *models.Finding) string {
+	h := sha256.Sum256([]byte(finding.CodeSnippet + "\x00" + finding.Description))
+	return hex.EncodeToString(h[:])
+}
+
 // calculateScore calculates confidence score for a category
 func (c *Classifier) calculateScore(finding *models.Finding, features CategoryFeatures) float64 {
 	var score float64
 
 	// Pattern matching
-	for i, pattern := range features.Patterns {
-		if strings.Contains(finding.CodeSnippet, pattern) {
+	for i, re := range features.compiled {
+		if re.MatchString(finding.CodeSnippet) {
 			score += features.Weights[i]
 		}
 	}
 
 	// Keyword matching
-	for _, keyword := range features.Keywords {
+	for i, keyword := range features.Keywords {
 		if strings.Contains(strings.ToLower(finding.Description), strings.ToLower(keyword)) {
-			score += 0.5 // Lower weight for keyword matches
+			score += features.KeywordWeights[i]
 		}
 	}
 
-	// Normalize score
-	maxScore := float64(len(features.Patterns)) + (float64(len(features.Keywords)) * 0.5)
+	// Normalize score by the total attainable weight
+	var maxScore float64
+	for _, weight := range features.Weights {
+		maxScore += weight
+	}
+	for _, weight := range features.KeywordWeights {
+		maxScore += weight
+	}
 	if maxScore > 0 {
 		score /= maxScore
 	}
@@ -172,35 +359,39 @@ func (c *Classifier) calculateScore(finding *models.Finding, features CategoryFe
 	return score
 }
 
-// getBestCategory returns highest scoring category and score
-func (c *Classifier) getBestCategory(scores map[string]float64) (string, float64) {
-	type categoryScore struct {
-		category string
-		score    float64
-	}
-
-	var sorted []categoryScore
+// rankCategories returns categories sorted by descending score, ties broken
+// by name so results are deterministic
+func (c *Classifier) rankCategories(scores map[string]float64) []models.CategoryScore {
+	ranked := make([]models.CategoryScore, 0, len(scores))
 	for category, score := range scores {
-		sorted = append(sorted, categoryScore{category, score})
+		ranked = append(ranked, models.CategoryScore{Category: category, Score: score})
 	}
 
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].score > sorted[j].score
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Category < ranked[j].Category
 	})
 
-	if len(sorted) > 0 {
-		return sorted[0].category, sorted[0].score
-	}
-
-	return "", 0
+	return ranked
 }
 
 // GetCategories returns list of supported categories
 func (c *Classifier) GetCategories() []string {
-	return c.categories
+	return append([]string(nil), c.categories...)
+}
+
+// ClearCache discards all cached classification results
+func (c *Classifier) ClearCache() {
+	if c.cache != nil {
+		c.cache.clear()
+	}
 }
 
 // UpdateThreshold updates classification threshold
 func (c *Classifier) UpdateThreshold(threshold float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.threshold = threshold
 }
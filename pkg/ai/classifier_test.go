@@ -0,0 +1,334 @@
+package ai
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// newTestClassifier returns a classifier loaded from the given model
+// config and rules documents
+func newTestClassifier(t *testing.T, config, rules string) *Classifier {
+	t.Helper()
+	c := NewClassifierWithLogger(writeModel(t, map[string]string{
+		"config.json": config,
+		"rules.json":  rules,
+	}), quietLogger)
+	if !c.initialized {
+		t.Fatal("classifier not initialized")
+	}
+	return c
+}
+
+// classify classifies a finding with the given snippet and description
+func classify(t *testing.T, c *Classifier, snippet, description string) models.Finding {
+	t.Helper()
+	finding := models.Finding{CodeSnippet: snippet, Description: description}
+	if err := c.Classify(&finding); err != nil {
+		t.Fatalf("Classify: %v", err)
+	}
+	return finding
+}
+
+func TestClassifierRegexPatterns(t *testing.T) {
+	c := newTestClassifier(t, `{"modelSettings": {"threshold": 0.8}}`, `[
+		{"id": "EVAL", "name": "e", "pattern": "^\\s*eval\\(", "severity": "high", "category": "Injection", "description": "d"},
+		{"id": "BROKEN", "name": "b", "pattern": "(", "severity": "low", "category": "Broken", "description": "d"}
+	]`)
+
+	tests := []struct {
+		snippet string
+		want    string
+	}{
+		// Substring matching would need the literal text ^\s*eval\( here
+		{"  eval(input)", "Injection"},
+		{"x := 1 // eval(input)", ""},
+		{"evaluate(input)", ""},
+	}
+	for _, tt := range tests {
+		if got := classify(t, c, tt.snippet, "").Category; got != tt.want {
+			t.Errorf("Classify(%q) category %q, want %q", tt.snippet, got, tt.want)
+		}
+	}
+	if _, ok := c.categoryData["Broken"]; ok {
+		t.Error("invalid pattern loaded into the classifier")
+	}
+}
+
+// injectionRules is a rules document with one injection pattern
+const injectionRules = `[{"id": "EVAL", "name": "e", "pattern": "eval\\(", "severity": "high", "category": "Injection", "description": "d"}]`
+
+func TestClassifierCacheHit(t *testing.T) {
+	c := newTestClassifier(t, `{"modelSettings": {"threshold": 0.8, "enableCache": true, "cacheSize": 10}}`, injectionRules)
+	if got := classify(t, c, "eval(x)", "").Category; got != "Injection" {
+		t.Fatalf("category %q, want Injection", got)
+	}
+
+	// Without category data only a cached result can classify the finding
+	c.categoryData = nil
+	if got := classify(t, c, "eval(x)", "").Category; got != "Injection" {
+		t.Errorf("cached category %q, want Injection", got)
+	}
+
+	c.ClearCache()
+	if got := classify(t, c, "eval(x)", "").Category; got != "" {
+		t.Errorf("category %q after clearing the cache, want it recomputed as none", got)
+	}
+}
+
+func TestClassifierCacheDisabled(t *testing.T) {
+	c := newTestClassifier(t, `{"modelSettings": {"threshold": 0.8}}`, injectionRules)
+	classify(t, c, "eval(x)", "")
+	if c.cache != nil {
+		t.Error("cache created although enableCache is off")
+	}
+}
+
+func TestLRUCacheEviction(t *testing.T) {
+	cache := newLRUCache(2)
+	cache.put("a", classification{{Category: "A"}})
+	cache.put("b", classification{{Category: "B"}})
+
+	// Using a makes b the least recently used entry
+	if _, ok := cache.get("a"); !ok {
+		t.Fatal("a missing before capacity was reached")
+	}
+	cache.put("c", classification{{Category: "C"}})
+
+	if _, ok := cache.get("b"); ok {
+		t.Error("least recently used entry b not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.get(key); !ok {
+			t.Errorf("%s evicted", key)
+		}
+	}
+	if cache.order.Len() != 2 || len(cache.items) != 2 {
+		t.Errorf("cache holds %d entries, want capacity 2", cache.order.Len())
+	}
+}
+
+func TestClassifierRequiresModelSettings(t *testing.T) {
+	c := NewClassifierWithLogger(writeModel(t, map[string]string{
+		"config.json": `{"confidence": 0.5}`,
+		"rules.json":  injectionRules,
+	}), quietLogger)
+	if c.Initialized() {
+		t.Error("classifier initialized from a detector-only config")
+	}
+	if err := c.Classify(&models.Finding{}); err == nil {
+		t.Error("uninitialized classifier classified a finding")
+	}
+}
+
+// batchFindings returns n findings cycling through snippets that fit
+// different categories, or none
+func batchFindings(n int) []models.Finding {
+	snippets := []struct{ snippet, description string }{
+		{"eval(input)", "possible injection"},
+		{"h := md5.New()", "weak hash"},
+		{`token = "abc"`, "hardcoded secret"},
+		{"x := 1", "nothing"},
+		{"eval(md5.Sum(x))", "hash injection"},
+	}
+	findings := make([]models.Finding, n)
+	for i := range findings {
+		s := snippets[i%len(snippets)]
+		findings[i] = models.Finding{ID: fmt.Sprintf("F-%d", i), CodeSnippet: s.snippet, Description: s.description}
+	}
+	return findings
+}
+
+func TestClassifyBatchMatchesSerial(t *testing.T) {
+	// The testdata model splits 50 findings into two batches
+	c := NewClassifierWithLogger("testdata/classifier", quietLogger)
+
+	serial := batchFindings(50)
+	for i := range serial {
+		if err := c.Classify(&serial[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	batched := batchFindings(50)
+	if err := c.ClassifyFindings(batched); err != nil {
+		t.Fatalf("ClassifyFindings: %v", err)
+	}
+
+	if !reflect.DeepEqual(batched, serial) {
+		t.Error("batched classification differs from serial classification")
+	}
+	if serial[0].Category != "Injection" || serial[1].Category != "Cryptography" || serial[3].Category != "" {
+		t.Errorf("unexpected categories %q, %q, %q", serial[0].Category, serial[1].Category, serial[3].Category)
+	}
+}
+
+func TestConcurrentClassify(t *testing.T) {
+	rules, err := os.ReadFile("testdata/classifier/rules.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newTestClassifier(t, `{"categories": ["Injection", "Cryptography"], "modelSettings": {"threshold": 0.5, "enableCache": true, "cacheSize": 3}}`, string(rules))
+
+	want := batchFindings(50)
+	for i := range want {
+		if err := c.Classify(&want[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	// Setting the threshold it already has must not disturb classification
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				c.UpdateThreshold(0.5)
+				categories := c.GetCategories()
+				if len(categories) > 0 {
+					categories[0] = "changed"
+				}
+			}
+		}
+	}()
+
+	var classifications sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		classifications.Add(1)
+		go func() {
+			defer classifications.Done()
+			// A small cache is evicted and refilled concurrently
+			got := batchFindings(50)
+			for j := range got {
+				if err := c.Classify(&got[j]); err != nil {
+					t.Errorf("Classify: %v", err)
+					return
+				}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Error("concurrent classification differs from serial classification")
+			}
+		}()
+	}
+	classifications.Wait()
+	close(stop)
+	wg.Wait()
+
+	if slices.Contains(c.GetCategories(), "changed") {
+		t.Error("GetCategories shares the classifier's categories")
+	}
+}
+
+func BenchmarkClassifySerial(b *testing.B) {
+	c := NewClassifierWithLogger("testdata/classifier", quietLogger)
+	findings := batchFindings(1000)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range findings {
+			c.Classify(&findings[i])
+		}
+	}
+}
+
+func BenchmarkClassifyBatch(b *testing.B) {
+	c := NewClassifierWithLogger("testdata/classifier", quietLogger)
+	findings := batchFindings(1000)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		c.ClassifyFindings(findings)
+	}
+}
+
+func TestClassifierMultipleLabels(t *testing.T) {
+	c := NewClassifierWithLogger("testdata/classifier", quietLogger)
+	finding := classify(t, c, "eval(md5.Sum(x))", "weak hash")
+
+	// Cryptography matches its pattern and keyword, Injection only its
+	// pattern, but both clear the 0.5 threshold
+	want := []models.CategoryScore{{Category: "Cryptography", Score: 1}, {Category: "Injection", Score: 1 / 1.5}}
+	if !reflect.DeepEqual(finding.Labels, want) {
+		t.Errorf("labels %+v, want %+v", finding.Labels, want)
+	}
+	if finding.Category != "Cryptography" || finding.Confidence != 1 {
+		t.Errorf("primary category %s (%v), want Cryptography (1)", finding.Category, finding.Confidence)
+	}
+
+	c.modelConfig.MaxLabels = 1
+	if labels := classify(t, c, "eval(md5.Sum(x))", "weak hash").Labels; len(labels) != 1 {
+		t.Errorf("got %d labels with MaxLabels 1", len(labels))
+	}
+}
+
+func TestClassifierKeywordWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights string
+		want    string
+	}{
+		// 0.5 of a possible 1.5 falls short of the 0.8 threshold
+		{"default weight", `{}`, ""},
+		// 5 of a possible 6 clears it
+		{"high weight", `{"password": 5}`, "Authentication"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClassifier(t, `{"modelSettings": {"threshold": 0.8}}`, `[{
+				"id": "LOGIN", "name": "l", "pattern": "login\\(", "keywords": ["password"],
+				"keywordWeights": `+tt.weights+`, "severity": "high", "category": "Authentication", "description": "d"
+			}]`)
+			if got := classify(t, c, "check(user)", "compares the password in plain text").Category; got != tt.want {
+				t.Errorf("category %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifierPatternWeight(t *testing.T) {
+	c := newTestClassifier(t, `{"modelSettings": {"threshold": 0.8}}`, `[
+		{"id": "STRONG", "name": "s", "pattern": "exec\\(", "weight": 4, "severity": "high", "category": "Injection", "description": "d"},
+		{"id": "WEAK", "name": "w", "pattern": "system\\(", "severity": "high", "category": "Injection", "description": "d"}
+	]`)
+	if got := classify(t, c, "exec(cmd)", "").Confidence; got != 0.8 {
+		t.Errorf("confidence %v, want 4 of 5 = 0.8", got)
+	}
+	if got := classify(t, c, "system(cmd)", "").Category; got != "" {
+		t.Errorf("category %q from the low-weight pattern alone, want none", got)
+	}
+}
+
+func TestClassifierCategoryDefaults(t *testing.T) {
+	c := newTestClassifier(t, `{"modelSettings": {"threshold": 0.8}, "categoryDefaults": {"injection": "critical"}}`, injectionRules)
+
+	tests := []struct {
+		name    string
+		finding models.Finding
+		want    models.Severity
+	}{
+		{"fills blank severity", models.Finding{CodeSnippet: "eval(x)"}, models.SeverityCritical},
+		{"keeps existing severity", models.Finding{CodeSnippet: "eval(x)", Severity: models.SeverityLow}, models.SeverityLow},
+		// The category came from elsewhere; the classifier matched nothing
+		{"unclassified", models.Finding{CodeSnippet: "x := 1", Category: "Injection"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finding := tt.finding
+			if err := c.Classify(&finding); err != nil {
+				t.Fatalf("Classify: %v", err)
+			}
+			if finding.Severity != tt.want {
+				t.Errorf("severity %q, want %q", finding.Severity, tt.want)
+			}
+		})
+	}
+}
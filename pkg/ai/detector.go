@@ -1,95 +1,428 @@
 package ai
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"maps"
 	"os"
 	"path/filepath"
-
+	"regexp"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/SofNam/devsecops-ai/pkg/logging"
 	"github.com/SofNam/devsecops-ai/pkg/models"
 	"github.com/SofNam/devsecops-ai/pkg/reporter"
 )
 
-// Detector represents the AI-based security detector
+// Detector represents the AI-based security detector. It is safe for
+// concurrent use: analyses only read the loaded rules and configuration, so
+// one Detector can serve many scans.
 type Detector struct {
-	modelPath   string
-	confidence  float64
-	maxFindings int
-	initialized bool
-	rules       []Rule
+	// mu guards the loaded state: analyses hold it for reading, and Reload
+	// and the setters for writing
+	mu sync.RWMutex
+
+	modelPath string
+	// rulesPath, when set, is a rules file used instead of the model's rules
+	rulesPath         string
+	confidence        float64
+	maxFindings       int
+	initialized       bool
+	rules             []Rule
+	severityOverrides map[string]models.Severity
+	llm               LLMClient
+
+	// classifier categorizes findings during analysis when the model
+	// configures it
+	classifier *Classifier
+
+	logger logging.Logger
+
+	// calibrate maps raw confidences to calibrated ones
+	calibrate CalibrationFunc
+
+	// workers bounds concurrent rule evaluation, defaulting to the CPU count
+	workers int
+
+	// remediations holds remediation templates keyed by lowercase category
+	remediations map[string]*template.Template
+
+	// tags, when non-empty, restricts detection to rules carrying one of
+	// these lowercase tags
+	tags map[string]bool
+
+	// pathSeverity adjusts severities by finding location
+	pathSeverity []PathSeverityRule
+
+	// severityPolicy restricts severity changes made by enhancement,
+	// defaulting to SeverityFreeze
+	severityPolicy SeverityPolicy
+
+	// maxPerSeverity caps the findings kept at each severity before
+	// maxFindings applies
+	maxPerSeverity map[models.Severity]int
+
+	// overrides records the settings made through setters, which Reload
+	// keeps instead of taking them from the configuration
+	overrides struct {
+		confidence, tags, calibrate, severityPolicy, llm bool
+	}
 }
 
 // Rule represents a security rule for AI analysis
 type Rule struct {
-	ID          string   `json:"id"`
-	Name        string   `json:"name"`
-	Pattern     string   `json:"pattern"`
-	Severity    string   `json:"severity"`
-	Category    string   `json:"category"`
-	Keywords    []string `json:"keywords"`
-	Description string   `json:"description"`
+	ID          string   `json:"id" yaml:"id"`
+	Name        string   `json:"name" yaml:"name"`
+	Pattern     string   `json:"pattern" yaml:"pattern"`
+	Severity    string   `json:"severity" yaml:"severity"`
+	Category    string   `json:"category" yaml:"category"`
+	Keywords    []string `json:"keywords" yaml:"keywords"`
+	Description string   `json:"description" yaml:"description"`
+	CWE         string   `json:"cwe" yaml:"cwe"`
+	OWASP       string   `json:"owasp" yaml:"owasp"`
+	Tags        []string `json:"tags" yaml:"tags"`
+
+	// Metadata is copied onto the findings of the rule, e.g. references
+	// or compliance mappings
+	Metadata map[string]string `json:"metadata" yaml:"metadata"`
+
+	// Exclude holds regular expressions that discard a match when any of
+	// them also matches the line
+	Exclude []string `json:"exclude" yaml:"exclude"`
+
+	// Extends names a parent rule whose Keywords, Category and Severity
+	// are inherited when not set on this rule, along with the Metadata keys
+	// it does not set
+	Extends string `json:"extends" yaml:"extends"`
+
+	// Weight scales pattern matches during classification, defaulting to 1.0
+	Weight float64 `json:"weight" yaml:"weight"`
+	// KeywordWeights overrides the default 0.5 weight of individual keywords
+	KeywordWeights map[string]float64 `json:"keywordWeights" yaml:"keywordWeights"`
+
+	// compiled is Pattern precompiled at load time
+	compiled *regexp.Regexp
+	// excludes is Exclude precompiled at load time
+	excludes []*regexp.Regexp
 }
 
 // DetectorConfig holds configuration for the detector
 type DetectorConfig struct {
-	Confidence  float64 `json:"confidence"`
-	MaxFindings int     `json:"maxFindings"`
+	Confidence        float64                    `json:"confidence"`
+	MaxFindings       int                        `json:"maxFindings"`
+	SeverityOverrides map[string]models.Severity `json:"severityOverrides"`
+	LLM               *LLMConfig                 `json:"llm"`
+	Workers           int                        `json:"workers"`
+	Calibration       *CalibrationConfig         `json:"calibration"`
+	Tags              []string                   `json:"tags"`
+	PathSeverity      []PathSeverityRule         `json:"pathSeverity"`
+	MaxPerSeverity    map[models.Severity]int    `json:"maxPerSeverity"`
+	SeverityPolicy    string                     `json:"severityPolicy"`
+	Severities        []models.SeverityLevel     `json:"severities"`
 }
 
 // NewDetector creates a new AI detector instance
 func NewDetector(modelPath string) *Detector {
-	d := &Detector{
-		modelPath:   modelPath,
-		confidence:  0.75, // Default confidence threshold
-		maxFindings: 100,  // Default maximum findings
-	}
+	return NewDetectorWithLogger(modelPath, logging.Default())
+}
+
+// NewDetectorWithLogger creates a new AI detector instance that reports
+// diagnostics to logger
+func NewDetectorWithLogger(modelPath string, logger logging.Logger) *Detector {
+	d := newDetector(modelPath, logger)
 
-	if err := d.initialize(); err != nil {
-		log.Printf("Warning: Failed to initialize AI detector: %v", err)
+	if err := d.initialize(false); err != nil {
+		d.logger.Warnf("Failed to initialize AI detector: %v", err)
 	}
 
 	return d
 }
 
-// initialize loads the AI model and rules
-func (d *Detector) initialize() error {
-	// Load rules from model path
-	rulesPath := filepath.Join(d.modelPath, "rules.json")
-	if _, err := os.Stat(rulesPath); err == nil {
-		rules, err := loadRules(rulesPath)
-		if err != nil {
-			return fmt.Errorf("failed to load rules: %v", err)
-		}
-		d.rules = rules
+// newDetector returns an uninitialized detector with default settings
+func newDetector(modelPath string, logger logging.Logger) *Detector {
+	return &Detector{
+		modelPath:      modelPath,
+		confidence:     0.75, // Default confidence threshold
+		maxFindings:    100,  // Default maximum findings
+		calibrate:      IdentityCalibration,
+		severityPolicy: SeverityFreeze,
+		logger:         logger,
+	}
+}
+
+// Reload loads the rules and configuration from the model path again and
+// swaps them in once in-flight analyses finish. Settings made through the
+// setters are kept. A rule set with invalid rules is rejected and the
+// current state kept.
+func (d *Detector) Reload() error {
+	next := newDetector(d.modelPath, d.logger)
+	d.mu.RLock()
+	next.rulesPath = d.rulesPath
+	d.mu.RUnlock()
+	if err := next.initialize(true); err != nil {
+		return fmt.Errorf("reloading detector: %v", err)
 	}
 
-	// Load configuration
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.rules = next.rules
+	d.severityOverrides = next.severityOverrides
+	d.maxFindings = next.maxFindings
+	d.workers = next.workers
+	d.remediations = next.remediations
+	d.pathSeverity = next.pathSeverity
+	d.maxPerSeverity = next.maxPerSeverity
+	d.classifier = next.classifier
+	if !d.overrides.confidence {
+		d.confidence = next.confidence
+	}
+	if !d.overrides.tags {
+		d.tags = next.tags
+	}
+	if !d.overrides.calibrate {
+		d.calibrate = next.calibrate
+	}
+	if !d.overrides.severityPolicy {
+		d.severityPolicy = next.severityPolicy
+	}
+	if !d.overrides.llm {
+		d.llm = next.llm
+	}
+	d.initialized = true
+
+	return nil
+}
+
+// initialize loads the AI model and rules. Invalid rules are skipped with a
+// warning, or fail the load when strict is set.
+func (d *Detector) initialize(strict bool) error {
+	// Load configuration first so custom severities are registered before
+	// the rules that use them are validated
+	var config *DetectorConfig
 	configPath := filepath.Join(d.modelPath, "config.json")
 	if _, err := os.Stat(configPath); err == nil {
-		config, err := loadConfig(configPath)
+		config, err = loadConfig(configPath)
 		if err != nil {
 			return fmt.Errorf("failed to load config: %v", err)
 		}
+		for _, level := range config.Severities {
+			if err := models.RegisterSeverity(level); err != nil {
+				d.logger.Warnf("Ignoring custom severity: %v", err)
+			}
+		}
+	}
+
+	// Load rules from model path
+	rules, err := d.loadRules()
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		if strict {
+			return fmt.Errorf("invalid rules: %v", err)
+		}
+		for _, ruleErr := range validationErr.Errors {
+			d.logger.Warnf("Ignoring invalid rule: %v", ruleErr)
+		}
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to load rules: %v", err)
+	}
+	d.rules = rules
+
+	// Load category remediation templates
+	remediations, err := loadRemediations(filepath.Join(d.modelPath, "remediations.json"), d.logger)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		d.logger.Warnf("Failed to load remediation templates: %v", err)
+	}
+	d.remediations = remediations
+
+	// Load the classifier, which stays off unless the model configures it
+	d.classifier = NewClassifierWithLogger(d.modelPath, d.logger)
+
+	// Apply configuration
+	if config != nil {
 		d.confidence = config.Confidence
 		d.maxFindings = config.MaxFindings
+		d.setSeverityOverrides(config.SeverityOverrides)
+		d.workers = config.Workers
+		d.tags = normalizeTags(config.Tags)
+		d.pathSeverity = d.compilePathSeverity(config.PathSeverity)
+		d.setMaxPerSeverity(config.MaxPerSeverity)
+		if config.SeverityPolicy != "" {
+			policy, err := ParseSeverityPolicy(config.SeverityPolicy)
+			if err != nil {
+				d.logger.Warnf("Ignoring severity policy: %v", err)
+			} else {
+				d.severityPolicy = policy
+			}
+		}
+
+		calibrate, err := newCalibration(config.Calibration)
+		if err != nil {
+			d.logger.Warnf("Ignoring confidence calibration: %v", err)
+		} else {
+			d.calibrate = calibrate
+		}
+
+		// LLM enhancement is opt-in and falls back to local analysis
+		if config.LLM != nil {
+			client, err := NewOpenAIClient(*config.LLM)
+			if err != nil {
+				d.logger.Warnf("LLM enhancement disabled: %v", err)
+			} else {
+				d.llm = newRateLimitedClient(client, config.LLM.RequestsPerSecond, config.LLM.MaxConcurrent)
+			}
+		}
 	}
 
 	d.initialized = true
 	return nil
 }
 
+// SetRulesPath replaces the model's rules with those of a JSON or YAML rules
+// file, keeping the rest of the model configuration. The current rules are
+// kept if the file does not load or holds invalid rules.
+func (d *Detector) SetRulesPath(path string) error {
+	d.mu.Lock()
+	previous := d.rulesPath
+	d.rulesPath = path
+	d.mu.Unlock()
+
+	if err := d.Reload(); err != nil {
+		d.mu.Lock()
+		d.rulesPath = previous
+		d.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// loadRules loads the rules file if set, otherwise the model's rules
+func (d *Detector) loadRules() ([]Rule, error) {
+	if d.rulesPath != "" {
+		return LoadRules(d.rulesPath)
+	}
+	return LoadModelRules(d.modelPath)
+}
+
+// SetLLMClient enables LLM-backed enhancement with the given client, or
+// disables it when client is nil
+func (d *Detector) SetLLMClient(client LLMClient) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.llm = client
+	d.overrides.llm = true
+}
+
+// SetMinConfidence sets the confidence threshold; findings whose calibrated
+// confidence is below it are dropped. The value must be within [0, 1].
+func (d *Detector) SetMinConfidence(confidence float64) error {
+	if confidence < 0 || confidence > 1 {
+		return fmt.Errorf("confidence must be between 0 and 1, got %v", confidence)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.confidence = confidence
+	d.overrides.confidence = true
+	return nil
+}
+
+// SetTags restricts detection to rules tagged with any of tags, compared
+// case-insensitively. An empty set applies all rules.
+func (d *Detector) SetTags(tags []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.overrides.tags = true
+	d.tags = normalizeTags(tags)
+}
+
+// normalizeTags returns the set of lowercased, trimmed tags, or nil when
+// there are none
+func normalizeTags(tags []string) map[string]bool {
+	var set map[string]bool
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			if set == nil {
+				set = make(map[string]bool)
+			}
+			set[tag] = true
+		}
+	}
+	return set
+}
+
+// ruleSelected reports whether the rule carries one of the requested tags
+func (d *Detector) ruleSelected(rule *Rule) bool {
+	if len(d.tags) == 0 {
+		return true
+	}
+	for _, tag := range rule.Tags {
+		if d.tags[strings.ToLower(tag)] {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleIDs returns the IDs of the loaded rules selected by the tag filter
+func (d *Detector) RuleIDs() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	ids := make([]string, 0, len(d.rules))
+	for i := range d.rules {
+		if d.ruleSelected(&d.rules[i]) {
+			ids = append(ids, d.rules[i].ID)
+		}
+	}
+	return ids
+}
+
+// Rules returns a copy of the current rules, taken consistently with
+// concurrent reloads
+func (d *Detector) Rules() []Rule {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return append([]Rule{}, d.rules...)
+}
+
 // Analyze performs AI-based analysis on findings
 func (d *Detector) Analyze(findings []models.Finding) ([]models.Finding, error) {
+	return d.AnalyzeContext(context.Background(), findings)
+}
+
+// AnalyzeContext performs AI-based analysis on findings, passing ctx to
+// any LLM requests
+func (d *Detector) AnalyzeContext(ctx context.Context, findings []models.Finding) ([]models.Finding, error) {
+	return d.AnalyzeTarget(ctx, "", findings)
+}
+
+// AnalyzeTarget performs AI-based analysis on findings scanned from target,
+// matching path severity rules against locations relative to it
+func (d *Detector) AnalyzeTarget(ctx context.Context, target string, findings []models.Finding) ([]models.Finding, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
 	if !d.initialized {
 		return findings, fmt.Errorf("detector not properly initialized")
 	}
 
+	// Compare severities in canonical form throughout analysis
+	findings = models.NormalizeSeverities(findings)
+
 	var enhancedFindings []models.Finding
 
 	for _, finding := range findings {
 		// Enhance finding with AI analysis
-		enhanced := d.enhanceFinding(finding)
+		enhanced := d.enhanceFinding(ctx, finding)
 		enhancedFindings = append(enhancedFindings, enhanced)
 	}
 
@@ -97,14 +430,61 @@ func (d *Detector) Analyze(findings []models.Finding) ([]models.Finding, error)
 	additionalFindings := d.detectAdditionalIssues(findings)
 	enhancedFindings = append(enhancedFindings, additionalFindings...)
 
+	// Fold detector findings into the scanner findings they duplicate
+	enhancedFindings = mergeFindings(enhancedFindings)
+
+	// Categorize findings before calibration, so thresholds and ordering
+	// use the confidences that are reported
+	d.classify(enhancedFindings)
+
+	// Report calibrated confidences so thresholds are meaningful
+	d.applyCalibration(enhancedFindings)
+
+	// Drop findings below the confidence threshold
+	enhancedFindings = d.filterConfidence(enhancedFindings)
+
+	// Remap severities so sorting and thresholds reflect overrides
+	d.applySeverityOverrides(enhancedFindings)
+
+	// Weigh findings by where they occur, e.g. lower in tests
+	d.applyPathSeverity(enhancedFindings, target)
+
 	// Sort and limit findings based on severity and confidence
 	enhancedFindings = d.prioritizeFindings(enhancedFindings)
 
 	return enhancedFindings, nil
 }
 
-// enhanceFinding enhances a single finding with AI insights
-func (d *Detector) enhanceFinding(finding models.Finding) models.Finding {
+// classify categorizes findings in place when the classifier is configured
+func (d *Detector) classify(findings []models.Finding) {
+	if d.classifier == nil || !d.classifier.Initialized() {
+		return
+	}
+	if err := d.classifier.ClassifyFindings(findings); err != nil {
+		d.logger.Warnf("Classification failed: %v", err)
+	}
+}
+
+// enhanceFinding enhances a single finding with AI insights, restricting
+// severity changes by the severity policy
+func (d *Detector) enhanceFinding(ctx context.Context, finding models.Finding) models.Finding {
+	enhanced := d.applySeverityPolicy(finding, d.enhance(ctx, finding))
+	// Rule metadata passes through enhancement untouched
+	enhanced.Metadata = finding.Metadata
+	return enhanced
+}
+
+// enhance returns the finding as enhanced by the LLM, or by local analysis
+// when no LLM is configured or it fails
+func (d *Detector) enhance(ctx context.Context, finding models.Finding) models.Finding {
+	if d.llm != nil {
+		enhanced, err := d.llm.Enhance(ctx, finding)
+		if err == nil {
+			return enhanced
+		}
+		d.logger.Warnf("LLM enhancement failed for %s, using local analysis: %v", finding.ID, err)
+	}
+
 	// Here you would typically:
 	// 1. Use AI to validate the finding
 	// 2. Add additional context
@@ -114,73 +494,212 @@ func (d *Detector) enhanceFinding(finding models.Finding) models.Finding {
 	// For now, we'll just add some basic enhancements
 	finding.Description = fmt.Sprintf("%s (AI Verified)", finding.Description)
 	if finding.Remediation == "" {
-		finding.Remediation = "AI suggested: Review and sanitize all inputs"
+		finding.Remediation = d.remediation(finding)
 	}
 
 	return finding
 }
 
-// detectAdditionalIssues uses AI to find additional security issues
+// detectAdditionalIssues uses AI to find additional security issues. Rules
+// are evaluated concurrently by a bounded pool of workers; the result is
+// ordered by rule ID, then location and line, regardless of scheduling.
 func (d *Detector) detectAdditionalIssues(findings []models.Finding) []models.Finding {
-	var additionalFindings []models.Finding
+	workers := d.workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
 
-	// Apply each rule
-	for _, rule := range d.rules {
-		// In a real implementation, you would:
-		// 1. Use AI to analyze code patterns
-		// 2. Look for security anti-patterns
-		// 3. Identify potential vulnerabilities
-		// 4. Calculate confidence scores
-
-		// Example placeholder for demonstration
-		if rule.Pattern != "" {
-			finding := models.Finding{
-				ID:          fmt.Sprintf("AI-%s", rule.ID),
-				Title:       rule.Name,
-				Description: rule.Description,
-				Severity:    models.Severity(reporter.Severity(rule.Severity)),
-				Category:    rule.Category,
+	jobs := make(chan *Rule)
+	results := make(chan []models.Finding)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rule := range jobs {
+				results <- d.applyRule(rule, findings)
 			}
-			additionalFindings = append(additionalFindings, finding)
+		}()
+	}
+
+	go func() {
+		for i := range d.rules {
+			jobs <- &d.rules[i]
 		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	var additionalFindings []models.Finding
+	for matches := range results {
+		additionalFindings = append(additionalFindings, matches...)
 	}
 
+	sort.SliceStable(additionalFindings, func(i, j int) bool {
+		a, b := additionalFindings[i], additionalFindings[j]
+		if a.RuleID != b.RuleID {
+			return a.RuleID < b.RuleID
+		}
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		return a.Line < b.Line
+	})
+
 	return additionalFindings
 }
 
-// prioritizeFindings sorts and limits findings based on severity and confidence
-func (d *Detector) prioritizeFindings(findings []models.Finding) []models.Finding {
+// applyRule matches a single rule against the snippets of findings. It only
+// reads detector state, so rules can be applied concurrently.
+func (d *Detector) applyRule(rule *Rule, findings []models.Finding) []models.Finding {
 	// In a real implementation, you would:
-	// 1. Sort by severity
-	// 2. Filter by confidence threshold
-	// 3. Limit to maxFindings
-	// 4. Remove duplicates
+	// 1. Use AI to analyze code patterns
+	// 2. Look for security anti-patterns
+	// 3. Identify potential vulnerabilities
+	// 4. Calculate confidence scores
 
-	if len(findings) > d.maxFindings {
-		findings = findings[:d.maxFindings]
+	if rule.compiled == nil || !d.ruleSelected(rule) {
+		return nil
 	}
 
-	return findings
+	var matches []models.Finding
+	for _, source := range findings {
+		if !rule.compiled.MatchString(source.CodeSnippet) || rule.Excluded(source.CodeSnippet) {
+			continue
+		}
+
+		finding := models.Finding{
+			ID:          fmt.Sprintf("AI-%s", rule.ID),
+			RuleID:      rule.ID,
+			Title:       rule.Name,
+			Description: rule.Description,
+			Severity:    models.Severity(reporter.Severity(rule.Severity)),
+			Category:    rule.Category,
+			Location:    source.Location,
+			Line:        source.Line,
+			CodeSnippet: source.CodeSnippet,
+			Language:    source.Language,
+			Context:     source.Context,
+			Confidence:  source.Confidence,
+			CWE:         rule.CWE,
+			OWASP:       rule.OWASP,
+			Tags:        slices.Clone(rule.Tags),
+			Metadata:    maps.Clone(rule.Metadata),
+		}
+		finding.Remediation = d.remediation(finding)
+		finding.Fingerprint = models.ComputeFingerprint(finding)
+		d.logger.Debugf("Rule %s matched snippet of %s:%d", rule.ID, source.Location, source.Line)
+		matches = append(matches, finding)
+	}
+
+	return matches
 }
 
-// loadRules loads security rules from a JSON file
-func loadRules(path string) ([]Rule, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
+// filterConfidence drops findings below the confidence threshold
+func (d *Detector) filterConfidence(findings []models.Finding) []models.Finding {
+	kept := findings[:0]
+	for _, finding := range findings {
+		if finding.Confidence >= d.confidence {
+			kept = append(kept, finding)
+		} else {
+			d.logger.Debugf("Dropping %s at %s:%d with confidence %.2f", finding.ID, finding.Location, finding.Line, finding.Confidence)
+		}
 	}
+	return kept
+}
 
-	var rules []Rule
-	if err := json.Unmarshal(data, &rules); err != nil {
-		return nil, err
+// setSeverityOverrides keeps the overrides that refer to loaded rules
+func (d *Detector) setSeverityOverrides(overrides map[string]models.Severity) {
+	known := make(map[string]bool, len(d.rules))
+	for _, rule := range d.rules {
+		known[rule.ID] = true
 	}
 
-	return rules, nil
+	d.severityOverrides = make(map[string]models.Severity, len(overrides))
+	for ruleID, severity := range overrides {
+		if !known[ruleID] {
+			d.logger.Warnf("Ignoring severity override for unknown rule %s", ruleID)
+			continue
+		}
+		canonical, err := models.ParseSeverity(string(severity))
+		if err != nil {
+			d.logger.Warnf("Ignoring severity override for rule %s: %v", ruleID, err)
+			continue
+		}
+		d.severityOverrides[ruleID] = canonical
+	}
+}
+
+// applySeverityOverrides remaps finding severities by originating rule ID
+func (d *Detector) applySeverityOverrides(findings []models.Finding) {
+	for i := range findings {
+		if severity, ok := d.severityOverrides[findings[i].RuleID]; ok {
+			findings[i].Severity = severity
+		}
+	}
+}
+
+// setMaxPerSeverity keeps the caps for known severities, normalizing
+// their names
+func (d *Detector) setMaxPerSeverity(caps map[models.Severity]int) {
+	d.maxPerSeverity = make(map[models.Severity]int, len(caps))
+	for name, limit := range caps {
+		severity, err := models.ParseSeverity(string(name))
+		if err != nil {
+			d.logger.Warnf("Ignoring finding cap: %v", err)
+			continue
+		}
+		d.maxPerSeverity[severity] = limit
+	}
+}
+
+// capPerSeverity drops the findings beyond the cap of their severity,
+// keeping the earliest ones, so sorted findings lose the least confident.
+// A negative cap leaves the severity unlimited.
+func (d *Detector) capPerSeverity(findings []models.Finding) []models.Finding {
+	if len(d.maxPerSeverity) == 0 {
+		return findings
+	}
+
+	counts := make(map[models.Severity]int, len(d.maxPerSeverity))
+	kept := findings[:0]
+	for _, finding := range findings {
+		severity := finding.Severity
+		if limit, ok := d.maxPerSeverity[severity]; ok && limit >= 0 && counts[severity] >= limit {
+			continue
+		}
+		counts[severity]++
+		kept = append(kept, finding)
+	}
+	return kept
+}
+
+// prioritizeFindings sorts and limits findings based on severity and confidence
+func (d *Detector) prioritizeFindings(findings []models.Finding) []models.Finding {
+	// Most severe first, then most confident, so truncation drops the
+	// least important findings
+	sort.SliceStable(findings, func(i, j int) bool {
+		ri, rj := findings[i].Severity.Rank(), findings[j].Severity.Rank()
+		if ri != rj {
+			return ri < rj
+		}
+		return findings[i].Confidence > findings[j].Confidence
+	})
+
+	findings = d.capPerSeverity(findings)
+
+	if len(findings) > d.maxFindings {
+		findings = findings[:d.maxFindings]
+	}
+
+	return findings
 }
 
 // loadConfig loads detector configuration from a JSON file
 func loadConfig(path string) (*DetectorConfig, error) {
-	data, err := os.ReadFile(path)
+	data, err := readModelFile(path)
 	if err != nil {
 		return nil, err
 	}
@@ -192,3 +711,24 @@ func loadConfig(path string) (*DetectorConfig, error) {
 
 	return &config, nil
 }
+
+// RegisterModelSeverities registers the custom severities declared in a
+// model's config.json, so rules using them can be loaded without a
+// Detector. Models without a config.json declare none.
+func RegisterModelSeverities(modelPath string) error {
+	configPath := filepath.Join(modelPath, "config.json")
+	if _, err := os.Stat(configPath); err != nil {
+		return nil
+	}
+
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %v", err)
+	}
+	for _, level := range config.Severities {
+		if err := models.RegisterSeverity(level); err != nil {
+			return err
+		}
+	}
+	return nil
+}
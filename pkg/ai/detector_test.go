@@ -0,0 +1,491 @@
+package ai
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/logging"
+	"github.com/SofNam/devsecops-ai/pkg/models"
+	"github.com/SofNam/devsecops-ai/pkg/reporter"
+)
+
+// quietLogger discards diagnostics in tests
+var quietLogger = logging.New(io.Discard, logging.LevelError)
+
+// newTestDetector returns an uninitialized detector with default settings
+func newTestDetector() *Detector {
+	return newDetector("", quietLogger)
+}
+
+func TestPrioritizeFindingsKeepsCriticals(t *testing.T) {
+	severities := models.Severities()
+
+	var findings []models.Finding
+	criticals := 0
+	for i := 0; i < 150; i++ {
+		severity := severities[(i*7)%len(severities)]
+		if severity == models.SeverityCritical {
+			criticals++
+		}
+		findings = append(findings, models.Finding{
+			ID:         fmt.Sprintf("F-%d", i),
+			Severity:   severity,
+			Confidence: float64(i%10) / 10,
+		})
+	}
+
+	d := newTestDetector()
+	d.maxFindings = 100
+	got := d.prioritizeFindings(findings)
+
+	if len(got) != 100 {
+		t.Fatalf("got %d findings, want 100", len(got))
+	}
+
+	kept := 0
+	for i, finding := range got {
+		if finding.Severity == models.SeverityCritical {
+			kept++
+		}
+		if i > 0 && finding.Severity.Rank() < got[i-1].Severity.Rank() {
+			t.Errorf("finding %d (%s) sorted after less severe %s", i, finding.Severity, got[i-1].Severity)
+		}
+	}
+	if kept != criticals {
+		t.Errorf("kept %d critical findings, want all %d", kept, criticals)
+	}
+}
+
+func TestCapPerSeverity(t *testing.T) {
+	d := newTestDetector()
+	d.maxFindings = 100
+	d.setMaxPerSeverity(map[models.Severity]int{"high": 2, "LOW": 0, "critical": -1, "bogus": 1})
+
+	findings := []models.Finding{
+		{ID: "H1", Severity: models.SeverityHigh, Confidence: 0.5},
+		{ID: "H2", Severity: models.SeverityHigh, Confidence: 0.9},
+		{ID: "H3", Severity: models.SeverityHigh, Confidence: 0.7},
+		{ID: "C1", Severity: models.SeverityCritical, Confidence: 0.6},
+		{ID: "C2", Severity: models.SeverityCritical, Confidence: 0.6},
+		{ID: "C3", Severity: models.SeverityCritical, Confidence: 0.6},
+		{ID: "L1", Severity: models.SeverityLow, Confidence: 1},
+		{ID: "M1", Severity: models.SeverityMedium, Confidence: 0.1},
+	}
+
+	var got []string
+	for _, finding := range d.prioritizeFindings(findings) {
+		got = append(got, finding.ID)
+	}
+	// High keeps its two most confident; critical is unlimited, low capped
+	// at none and medium uncapped
+	want := []string{"C1", "C2", "C3", "H2", "H3", "M1"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("kept %v, want %v", got, want)
+	}
+	if len(d.maxPerSeverity) != 3 {
+		t.Errorf("caps %v, want the unknown severity ignored", d.maxPerSeverity)
+	}
+}
+
+// analyze runs the detector over snippets, one finding per snippet at
+// app.go on consecutive lines
+func analyze(t *testing.T, d *Detector, snippets ...string) []models.Finding {
+	t.Helper()
+	var findings []models.Finding
+	for i, snippet := range snippets {
+		findings = append(findings, models.Finding{
+			ID:          fmt.Sprintf("SRC-%d", i),
+			Location:    "app.go",
+			Line:        i + 1,
+			CodeSnippet: snippet,
+			Confidence:  0.9,
+			Severity:    models.SeverityInfo,
+		})
+	}
+	got, err := d.Analyze(findings)
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	return got
+}
+
+// findingFor returns the finding reported by ruleID, failing the test when
+// there is none
+func findingFor(t *testing.T, findings []models.Finding, ruleID string) models.Finding {
+	t.Helper()
+	for _, finding := range findings {
+		if finding.RuleID == ruleID {
+			return finding
+		}
+	}
+	t.Fatalf("no finding for rule %s", ruleID)
+	return models.Finding{}
+}
+
+func TestClassificationFieldsCopiedFromRules(t *testing.T) {
+	d := NewDetectorWithLogger("testdata", quietLogger)
+	findings := analyze(t, d, `q := "SELECT * FROM users WHERE id=" + id`, "// TODO(security): validate")
+
+	sqli := findingFor(t, findings, "SQLI")
+	if sqli.CWE != "CWE-89" || sqli.OWASP != "A03:2021-Injection" {
+		t.Errorf("SQLI classification = %q, %q", sqli.CWE, sqli.OWASP)
+	}
+
+	todo := findingFor(t, findings, "TODO")
+	if todo.CWE != "" || todo.OWASP != "" {
+		t.Errorf("TODO classification = %q, %q, want empty", todo.CWE, todo.OWASP)
+	}
+}
+
+// writeModel writes files, keyed by slash-separated relative path, to a
+// temporary model directory and returns the directory
+func writeModel(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestExcludeSuppressesDetection(t *testing.T) {
+	dir := writeModel(t, map[string]string{
+		"rules.json": `[
+			{"id": "TODO", "name": "Todo", "pattern": "(?i)TODO.*security", "exclude": ["(?i)not a security"], "severity": "info", "category": "Hygiene", "description": "d"}
+		]`,
+		"config.json": `{"confidence": 0.5, "maxFindings": 100}`,
+	})
+	findings := analyze(t, NewDetectorWithLogger(dir, quietLogger), "// TODO: security review", "// NOTE: not a security TODO")
+
+	if got := findingFor(t, findings, "TODO").Line; got != 1 {
+		t.Errorf("TODO reported on line %d, want 1", got)
+	}
+	for _, finding := range findings {
+		if finding.RuleID == "TODO" && finding.Line == 2 {
+			t.Error("TODO reported on the excluded line")
+		}
+	}
+}
+
+func TestSeverityOverrideShowsInStats(t *testing.T) {
+	dir := writeModel(t, map[string]string{
+		"rules.json": `[
+			{"id": "EVAL", "name": "Eval", "pattern": "eval\\(", "severity": "critical", "category": "Injection", "description": "d"},
+			{"id": "EXEC", "name": "Exec", "pattern": "exec\\(", "severity": "critical", "category": "Injection", "description": "d"}
+		]`,
+		"config.json": `{"confidence": 0.5, "maxFindings": 100, "severityOverrides": {"EVAL": "LOW", "MISSING": "INFO"}}`,
+	})
+	findings := analyze(t, NewDetectorWithLogger(dir, quietLogger), "eval(input)", "exec(input)")
+
+	if got := findingFor(t, findings, "EVAL").Severity; got != models.SeverityLow {
+		t.Errorf("EVAL severity %s, want LOW", got)
+	}
+	if got := findingFor(t, findings, "EXEC").Severity; got != models.SeverityCritical {
+		t.Errorf("EXEC severity %s, want CRITICAL", got)
+	}
+
+	stats := reporter.New(nil, "").Build(findings, reporter.Config{}, ".", time.Now()).SummaryStats
+	if stats.CriticalCount != 1 || stats.LowCount != 1 {
+		t.Errorf("stats %+v, want 1 critical and 1 low", stats)
+	}
+}
+
+func TestSeverityOverrideNormalized(t *testing.T) {
+	dir := writeModel(t, map[string]string{
+		"rules.json": `[
+			{"id": "EVAL", "name": "Eval", "pattern": "eval\\(", "severity": "critical", "category": "Injection", "description": "d"},
+			{"id": "EXEC", "name": "Exec", "pattern": "exec\\(", "severity": "critical", "category": "Injection", "description": "d"},
+			{"id": "SYSTEM", "name": "System", "pattern": "system\\(", "severity": "critical", "category": "Injection", "description": "d"}
+		]`,
+		"config.json": `{"confidence": 0.5, "maxFindings": 100, "severityOverrides": {"EVAL": "low", "EXEC": "warning", "SYSTEM": "HIGHH"}}`,
+	})
+	findings := analyze(t, NewDetectorWithLogger(dir, quietLogger), "eval(input)", "exec(input)", "system(input)")
+
+	tests := []struct {
+		ruleID string
+		want   models.Severity
+	}{
+		{"EVAL", models.SeverityLow},
+		{"EXEC", models.SeverityMedium},
+		{"SYSTEM", models.SeverityCritical},
+	}
+	for _, tt := range tests {
+		if got := findingFor(t, findings, tt.ruleID).Severity; got != tt.want {
+			t.Errorf("%s severity %s, want %s", tt.ruleID, got, tt.want)
+		}
+	}
+
+	keep := reporter.MinSeverity(models.SeverityLow)
+	for _, finding := range findings {
+		if finding.RuleID != "" && !keep(finding) {
+			t.Errorf("%s at %s dropped by a LOW minimum severity", finding.RuleID, finding.Severity)
+		}
+	}
+}
+
+func TestAnalyzeClassifiesBeforeFiltering(t *testing.T) {
+	dir := writeModel(t, map[string]string{
+		"rules.json": `[
+			{"id": "EVAL", "name": "Eval", "pattern": "eval\\(", "severity": "high", "category": "Injection", "description": "d"},
+			{"id": "EXEC", "name": "Exec", "pattern": "exec\\(", "severity": "high", "category": "Injection", "description": "d"}
+		]`,
+		"config.json": `{"confidence": 0.75, "maxFindings": 100, "modelSettings": {"threshold": 0.5}}`,
+	})
+	// The first line scores 0.5 for Injection, below the detector's
+	// threshold, though its findings start at 0.9
+	findings := analyze(t, NewDetectorWithLogger(dir, quietLogger), "eval(input)", "eval(input); exec(input)")
+
+	if len(findings) == 0 {
+		t.Fatal("no findings reported")
+	}
+	for _, finding := range findings {
+		if finding.Line != 2 {
+			t.Errorf("%s reported on line %d with confidence %v, want it filtered by its classified confidence", finding.ID, finding.Line, finding.Confidence)
+			continue
+		}
+		if len(finding.Labels) == 0 || finding.Category != "Injection" || finding.Confidence != finding.Labels[0].Score {
+			t.Errorf("%s category %q, confidence %v, labels %v, want the classification reported", finding.ID, finding.Category, finding.Confidence, finding.Labels)
+		}
+	}
+}
+
+// manyRulesModel writes a model of n rules, RULE-000 matching "token000" and
+// so on, evaluated by the given number of workers
+func manyRulesModel(t testing.TB, n, workers int) string {
+	t.Helper()
+	rules := make([]string, n)
+	for i := range rules {
+		rules[i] = fmt.Sprintf(`{"id": "RULE-%03d", "name": "Rule %d", "pattern": "token%03d\\b", "severity": "medium", "category": "Test", "description": "d"}`, i, i, i)
+	}
+	dir := t.TempDir()
+	files := map[string]string{
+		"rules.json":  "[" + strings.Join(rules, ",\n") + "]",
+		"config.json": fmt.Sprintf(`{"confidence": 0.5, "maxFindings": 1000, "workers": %d}`, workers),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// manyRulesSnippets returns snippets that each mention several rule tokens
+func manyRulesSnippets(n int) []models.Finding {
+	var findings []models.Finding
+	for i := 0; i < n; i++ {
+		findings = append(findings, models.Finding{
+			ID:          fmt.Sprintf("SRC-%d", i),
+			Location:    fmt.Sprintf("file%d.go", i%7),
+			Line:        i + 1,
+			CodeSnippet: fmt.Sprintf("token%03d token%03d token%03d", i%200, (i*3)%200, (i*11)%200),
+			Confidence:  0.9,
+			Severity:    models.SeverityInfo,
+		})
+	}
+	return findings
+}
+
+// TestConcurrentRulesMatchSerial checks that a worker pool finds exactly
+// what a single worker does, in the same order. Run with -race.
+func TestConcurrentRulesMatchSerial(t *testing.T) {
+	findings := manyRulesSnippets(300)
+
+	serial := NewDetectorWithLogger(manyRulesModel(t, 200, 1), quietLogger)
+	want := serial.detectAdditionalIssues(findings)
+	if len(want) == 0 {
+		t.Fatal("no rule matched")
+	}
+
+	for _, workers := range []int{2, 8, 64} {
+		d := NewDetectorWithLogger(manyRulesModel(t, 200, workers), quietLogger)
+		got := d.detectAdditionalIssues(findings)
+		if len(got) != len(want) {
+			t.Fatalf("%d workers found %d issues, want %d", workers, len(got), len(want))
+		}
+		for i := range got {
+			if got[i].RuleID != want[i].RuleID || got[i].Location != want[i].Location || got[i].Line != want[i].Line {
+				t.Fatalf("%d workers: issue %d is %s at %s:%d, want %s at %s:%d", workers, i,
+					got[i].RuleID, got[i].Location, got[i].Line, want[i].RuleID, want[i].Location, want[i].Line)
+			}
+		}
+	}
+}
+
+func BenchmarkDetectAdditionalIssues(b *testing.B) {
+	findings := manyRulesSnippets(500)
+	for _, workers := range []int{1, 4, 0} {
+		d := NewDetectorWithLogger(manyRulesModel(b, 200, workers), quietLogger)
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				d.detectAdditionalIssues(findings)
+			}
+		})
+	}
+}
+
+func TestMinConfidenceThreshold(t *testing.T) {
+	dir := writeModel(t, map[string]string{
+		"rules.json":  `[{"id": "EVAL", "name": "Eval", "pattern": "eval\\(", "severity": "medium", "category": "Injection", "description": "d"}]`,
+		"config.json": `{"confidence": 0.5, "maxFindings": 100}`,
+	})
+
+	var findings []models.Finding
+	for i := 0; i <= 10; i++ {
+		findings = append(findings, models.Finding{
+			ID:         fmt.Sprintf("F-%d", i),
+			Location:   "app.go",
+			Line:       i + 1,
+			Severity:   models.SeverityLow,
+			Confidence: float64(i) / 10,
+		})
+	}
+
+	tests := []struct {
+		threshold float64
+		want      int
+	}{
+		{0, 11},
+		{0.25, 8},
+		{0.5, 6},
+		{0.75, 3},
+		{1, 1},
+	}
+	prev := len(findings)
+	for _, tt := range tests {
+		d := NewDetectorWithLogger(dir, quietLogger)
+		if err := d.SetMinConfidence(tt.threshold); err != nil {
+			t.Fatal(err)
+		}
+		got, err := d.Analyze(append([]models.Finding(nil), findings...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("threshold %v kept %d findings, want %d", tt.threshold, len(got), tt.want)
+		}
+		if len(got) > prev {
+			t.Errorf("raising the threshold to %v kept more findings, %d > %d", tt.threshold, len(got), prev)
+		}
+		prev = len(got)
+		for _, finding := range got {
+			if finding.Confidence < tt.threshold {
+				t.Errorf("threshold %v kept %s with confidence %v", tt.threshold, finding.ID, finding.Confidence)
+			}
+		}
+	}
+
+	for _, invalid := range []float64{-0.1, 1.1} {
+		if err := NewDetectorWithLogger(dir, quietLogger).SetMinConfidence(invalid); err == nil {
+			t.Errorf("SetMinConfidence(%v) succeeded", invalid)
+		}
+	}
+}
+
+func TestConcurrentAnalyze(t *testing.T) {
+	dir := writeModel(t, map[string]string{
+		"rules.json": `[
+			{"id": "EVAL", "name": "Eval", "pattern": "eval\\(", "severity": "high", "category": "Injection", "description": "d", "tags": ["web"]},
+			{"id": "EXEC", "name": "Exec", "pattern": "exec\\(", "severity": "critical", "category": "Injection", "description": "d", "tags": ["cli"]}
+		]`,
+		"config.json": `{"confidence": 0.5, "maxFindings": 100}`,
+	})
+	d := NewDetectorWithLogger(dir, quietLogger)
+
+	var input []models.Finding
+	for i := 0; i < 20; i++ {
+		input = append(input, models.Finding{
+			ID:          fmt.Sprintf("SRC-%d", i),
+			Location:    fmt.Sprintf("app%d.go", i%3),
+			Line:        i + 1,
+			CodeSnippet: []string{"eval(x)", "exec(y)", "fine()"}[i%3],
+			Severity:    models.SeverityMedium,
+			Confidence:  0.9,
+			Tags:        []string{"scanner"},
+		})
+	}
+	original := slices.Clone(input)
+	for i := range original {
+		original[i].Tags = slices.Clone(input[i].Tags)
+	}
+	want, err := d.Analyze(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.ContainsFunc(want, func(f models.Finding) bool { return strings.HasPrefix(f.ID, "AI-") }) {
+		t.Fatal("no detector findings to compare")
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	// Reloading the same rules and reapplying a setting must not disturb
+	// analyses in flight
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if err := d.Reload(); err != nil {
+				t.Errorf("Reload: %v", err)
+				return
+			}
+			if err := d.SetMinConfidence(0.5); err != nil {
+				t.Errorf("SetMinConfidence: %v", err)
+				return
+			}
+		}
+	}()
+
+	var analyses sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		analyses.Add(1)
+		go func() {
+			defer analyses.Done()
+			for j := 0; j < 5; j++ {
+				got, err := d.Analyze(input)
+				if err != nil {
+					t.Errorf("Analyze: %v", err)
+					return
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("concurrent Analyze returned %d findings differing from the serial result", len(got))
+					return
+				}
+				// Detector findings own their tags, copied from the rule
+				for k := range got {
+					if strings.HasPrefix(got[k].ID, "AI-") {
+						for l := range got[k].Tags {
+							got[k].Tags[l] = "changed"
+						}
+					}
+				}
+			}
+		}()
+	}
+	analyses.Wait()
+	close(stop)
+	wg.Wait()
+
+	if !reflect.DeepEqual(input, original) {
+		t.Error("Analyze modified its input findings")
+	}
+}
@@ -0,0 +1,158 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// resolveExtends applies rule inheritance: a rule naming a parent in
+// Extends inherits the parent's Keywords, Category and Severity unless it
+// sets them itself, and the parent's Metadata keys it does not set. Parents
+// may extend other rules. Rules with an unknown parent or in an inheritance
+// cycle, and rules extending them, are dropped and reported.
+func resolveExtends(rules []Rule) ([]Rule, []RuleError) {
+	byID := make(map[string]int, len(rules))
+	for i, rule := range rules {
+		if _, ok := byID[rule.ID]; !ok && rule.ID != "" {
+			byID[rule.ID] = i
+		}
+	}
+
+	r := &extendsResolver{
+		rules:  append([]Rule(nil), rules...),
+		byID:   byID,
+		state:  make([]resolveState, len(rules)),
+		errors: make([]error, len(rules)),
+	}
+
+	var resolved []Rule
+	var errs []RuleError
+	for i := range r.rules {
+		if err := r.resolve(i, nil); err != nil {
+			errs = append(errs, RuleError{RuleID: ruleLabel(i, r.rules[i]), Field: "extends", Message: err.Error()})
+			continue
+		}
+		resolved = append(resolved, r.rules[i])
+	}
+
+	return resolved, errs
+}
+
+// resolveState tracks a rule during inheritance resolution
+type resolveState int
+
+const (
+	unresolved resolveState = iota
+	resolving
+	resolved
+	failed
+)
+
+// extendsResolver resolves inheritance depth-first, memoizing results
+type extendsResolver struct {
+	rules  []Rule
+	byID   map[string]int
+	state  []resolveState
+	errors []error
+}
+
+// resolve applies the inheritance chain of rule i. path holds the IDs of
+// the rules being resolved that extend rule i, to describe cycles.
+func (r *extendsResolver) resolve(i int, path []string) error {
+	switch r.state[i] {
+	case resolved:
+		return nil
+	case failed:
+		return r.errors[i]
+	case resolving:
+		// Report only the rules forming the cycle
+		for j, id := range path {
+			if id == r.rules[i].ID {
+				path = path[j:]
+				break
+			}
+		}
+		return cycleError{path: append(path, r.rules[i].ID)}
+	}
+
+	rule := &r.rules[i]
+	if rule.Extends == "" {
+		r.state[i] = resolved
+		return nil
+	}
+
+	parent, ok := r.byID[rule.Extends]
+	if !ok {
+		return r.fail(i, fmt.Errorf("unknown parent rule %q", rule.Extends))
+	}
+
+	r.state[i] = resolving
+	if err := r.resolve(parent, append(path, rule.ID)); err != nil {
+		var cycle cycleError
+		if errors.As(err, &cycle) && cycle.contains(rule.ID) {
+			return r.fail(i, err)
+		}
+		return r.fail(i, fmt.Errorf("parent rule %s is invalid: %v", rule.Extends, err))
+	}
+
+	inherit(rule, r.rules[parent])
+	r.state[i] = resolved
+	return nil
+}
+
+// cycleError reports rules that extend each other
+type cycleError struct {
+	path []string
+}
+
+func (e cycleError) Error() string {
+	return "inheritance cycle " + strings.Join(e.path, " -> ")
+}
+
+// contains reports whether the rule is part of the cycle
+func (e cycleError) contains(id string) bool {
+	for _, member := range e.path {
+		if member == id {
+			return true
+		}
+	}
+	return false
+}
+
+// fail records the resolution error of rule i
+func (r *extendsResolver) fail(i int, err error) error {
+	r.state[i] = failed
+	r.errors[i] = err
+	return err
+}
+
+// inherit copies the fields a child rule leaves unset from its parent
+func inherit(child *Rule, parent Rule) {
+	if len(child.Keywords) == 0 {
+		child.Keywords = append([]string(nil), parent.Keywords...)
+	}
+	if child.Category == "" {
+		child.Category = parent.Category
+	}
+	if child.Severity == "" {
+		child.Severity = parent.Severity
+	}
+	for key, value := range parent.Metadata {
+		if _, ok := child.Metadata[key]; ok {
+			continue
+		}
+		if child.Metadata == nil {
+			child.Metadata = make(map[string]string, len(parent.Metadata))
+		}
+		child.Metadata[key] = value
+	}
+}
+
+// ruleLabel identifies a rule in errors, by position when it has no ID
+func ruleLabel(index int, rule Rule) string {
+	if rule.ID == "" {
+		return fmt.Sprintf("#%d", index+1)
+	}
+	return rule.ID
+}
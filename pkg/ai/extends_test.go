@@ -0,0 +1,132 @@
+package ai
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// rulesByID indexes rules by ID
+func rulesByID(rules []Rule) map[string]Rule {
+	byID := make(map[string]Rule, len(rules))
+	for _, rule := range rules {
+		byID[rule.ID] = rule
+	}
+	return byID
+}
+
+func TestResolveExtends(t *testing.T) {
+	rules := []Rule{
+		{ID: "BASE", Category: "Injection", Severity: "HIGH", Keywords: []string{"exec"}, Metadata: map[string]string{"owner": "appsec", "ref": "base"}},
+		{ID: "SINGLE", Extends: "BASE"},
+		{ID: "OVERRIDE", Extends: "BASE", Category: "RCE", Severity: "CRITICAL", Keywords: []string{"system"}, Metadata: map[string]string{"ref": "override"}},
+		{ID: "GRANDCHILD", Extends: "OVERRIDE", Severity: "LOW"},
+		{ID: "CHAIN", Extends: "SINGLE"},
+	}
+
+	resolved, errs := resolveExtends(rules)
+	if len(errs) != 0 {
+		t.Fatalf("errors %v", errs)
+	}
+	byID := rulesByID(resolved)
+
+	tests := []struct {
+		id, category, severity string
+		keywords               []string
+		metadata               map[string]string
+	}{
+		{"SINGLE", "Injection", "HIGH", []string{"exec"}, map[string]string{"owner": "appsec", "ref": "base"}},
+		{"OVERRIDE", "RCE", "CRITICAL", []string{"system"}, map[string]string{"owner": "appsec", "ref": "override"}},
+		{"GRANDCHILD", "RCE", "LOW", []string{"system"}, map[string]string{"owner": "appsec", "ref": "override"}},
+		{"CHAIN", "Injection", "HIGH", []string{"exec"}, map[string]string{"owner": "appsec", "ref": "base"}},
+	}
+	for _, tt := range tests {
+		got := byID[tt.id]
+		if got.Category != tt.category || got.Severity != tt.severity || !reflect.DeepEqual(got.Keywords, tt.keywords) || !reflect.DeepEqual(got.Metadata, tt.metadata) {
+			t.Errorf("%s = %s/%s %v %v, want %s/%s %v %v", tt.id,
+				got.Category, got.Severity, got.Keywords, got.Metadata,
+				tt.category, tt.severity, tt.keywords, tt.metadata)
+		}
+	}
+
+	// Resolution does not modify the parent or the input
+	if base := byID["BASE"]; len(base.Metadata) != 2 || base.Metadata["ref"] != "base" {
+		t.Errorf("parent metadata changed to %v", base.Metadata)
+	}
+	if rules[1].Category != "" {
+		t.Errorf("input rule modified: %+v", rules[1])
+	}
+}
+
+func TestResolveExtendsErrors(t *testing.T) {
+	rules := []Rule{
+		{ID: "OK", Category: "C", Severity: "LOW"},
+		{ID: "A", Extends: "B"},
+		{ID: "B", Extends: "C"},
+		{ID: "C", Extends: "A"},
+		{ID: "SELF", Extends: "SELF"},
+		{ID: "INTO-CYCLE", Extends: "A"},
+		{ID: "ORPHAN", Extends: "MISSING"},
+		{ID: "ORPHAN-CHILD", Extends: "ORPHAN"},
+		{ID: "CHILD", Extends: "OK"},
+	}
+
+	resolved, errs := resolveExtends(rules)
+
+	var kept []string
+	for _, rule := range resolved {
+		kept = append(kept, rule.ID)
+	}
+	if want := []string{"OK", "CHILD"}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("kept %v, want %v", kept, want)
+	}
+
+	messages := make(map[string]string)
+	for _, err := range errs {
+		if err.Field != "extends" {
+			t.Errorf("%s error on field %q, want extends", err.RuleID, err.Field)
+		}
+		messages[err.RuleID] = err.Message
+	}
+	want := map[string]string{
+		"A":            "inheritance cycle A -> B -> C -> A",
+		"B":            "inheritance cycle A -> B -> C -> A",
+		"C":            "inheritance cycle A -> B -> C -> A",
+		"SELF":         "inheritance cycle SELF -> SELF",
+		"INTO-CYCLE":   "parent rule A is invalid: inheritance cycle",
+		"ORPHAN":       `unknown parent rule "MISSING"`,
+		"ORPHAN-CHILD": "parent rule ORPHAN is invalid: unknown parent rule",
+	}
+	var ids []string
+	for id := range messages {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	if len(messages) != len(want) {
+		t.Errorf("errors for %v, want %d rules", ids, len(want))
+	}
+	for id, prefix := range want {
+		if !strings.HasPrefix(messages[id], prefix) {
+			t.Errorf("%s error %q, want prefix %q", id, messages[id], prefix)
+		}
+	}
+}
+
+func TestLoadRulesReportsExtendsErrors(t *testing.T) {
+	rules, err := LoadRules(writeFile(t, "rules.json", `[
+		{"id": "BASE", "name": "Base", "pattern": "exec\\(", "severity": "high", "category": "Injection", "description": "d"},
+		{"id": "CHILD", "name": "Child", "pattern": "system\\(", "description": "d", "extends": "BASE"},
+		{"id": "LOOP", "name": "Loop", "pattern": "x", "description": "d", "extends": "LOOP"}
+	]`))
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 || validationErr.Errors[0].RuleID != "LOOP" {
+		t.Fatalf("LoadRules error %v, want the LOOP cycle only", err)
+	}
+	child := rulesByID(rules)["CHILD"]
+	if child.Severity != "HIGH" || child.Category != "Injection" {
+		t.Errorf("CHILD inherited %s/%s, want HIGH/Injection", child.Severity, child.Category)
+	}
+}
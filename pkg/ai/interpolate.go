@@ -0,0 +1,133 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// readModelFile reads a JSON or YAML model file, chosen by extension, and
+// expands environment variable references in its string values. See
+// expandEnv for the syntax.
+func readModelFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Leave files without references untouched
+	if !bytes.Contains(data, []byte("${")) {
+		return data, nil
+	}
+
+	yamlFile := false
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		yamlFile = true
+	}
+
+	var doc interface{}
+	if yamlFile {
+		err = yaml.Unmarshal(data, &doc)
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&doc)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err = expandValues(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	if yamlFile {
+		return yaml.Marshal(doc)
+	}
+	return json.Marshal(doc)
+}
+
+// expandValues expands the string values of a decoded document
+func expandValues(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return expandEnv(v)
+	case []interface{}:
+		for i, item := range v {
+			expanded, err := expandValues(item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = expanded
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			expanded, err := expandValues(item)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = expanded
+		}
+	case map[interface{}]interface{}:
+		for key, item := range v {
+			expanded, err := expandValues(item)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = expanded
+		}
+	}
+	return value, nil
+}
+
+// expandEnv replaces ${VAR} with the value of the environment variable VAR
+// and ${VAR:-default} with its value, or default when VAR is unset or empty.
+// A reference to an undefined variable without a default is an error.
+// "$${" produces a literal "${".
+func expandEnv(s string) (string, error) {
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+
+		if start > 0 && s[start-1] == '$' {
+			b.WriteString(s[:start-1])
+			b.WriteString("${")
+			s = s[start+2:]
+			continue
+		}
+
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated variable reference in %q", s)
+		}
+		end += start
+
+		name, fallback, hasDefault := strings.Cut(s[start+2:end], ":-")
+		if name == "" {
+			return "", fmt.Errorf("empty variable reference in %q", s)
+		}
+
+		value, ok := os.LookupEnv(name)
+		switch {
+		case hasDefault && value == "":
+			value = fallback
+		case !ok:
+			return "", fmt.Errorf("undefined environment variable %s", name)
+		}
+
+		b.WriteString(s[:start])
+		b.WriteString(value)
+		s = s[end+1:]
+	}
+}
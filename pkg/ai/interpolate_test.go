@@ -0,0 +1,69 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("DEVSEC_HOST", "api.example.com")
+	t.Setenv("DEVSEC_EMPTY", "")
+
+	tests := []struct {
+		input string
+		want  string
+		err   string
+	}{
+		{"https://${DEVSEC_HOST}/v1", "https://api.example.com/v1", ""},
+		{"${DEVSEC_HOST}${DEVSEC_HOST}", "api.example.comapi.example.com", ""},
+		{"${DEVSEC_UNSET:-fallback}", "fallback", ""},
+		{"${DEVSEC_EMPTY:-fallback}", "fallback", ""},
+		{"${DEVSEC_HOST:-fallback}", "api.example.com", ""},
+		{"${DEVSEC_UNSET:-}", "", ""},
+		{"$${DEVSEC_HOST}", "${DEVSEC_HOST}", ""},
+		{"no references", "no references", ""},
+		{"${DEVSEC_UNSET}", "", "undefined environment variable DEVSEC_UNSET"},
+		{"${DEVSEC_HOST", "", "unterminated variable reference"},
+		{"${}", "", "empty variable reference"},
+	}
+	for _, tt := range tests {
+		got, err := expandEnv(tt.input)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expandEnv(%q) error %v, want %q", tt.input, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("expandEnv(%q) = %q, %v, want %q", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestModelFilesInterpolated(t *testing.T) {
+	t.Setenv("DEVSEC_RULE_SEVERITY", "critical")
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"rules.json", `[{"id": "EVAL", "name": "e", "pattern": "eval\\(", "severity": "${DEVSEC_RULE_SEVERITY}", "category": "${DEVSEC_CATEGORY:-Injection}", "description": "d"}]`},
+		{"rules.yaml", "- id: EVAL\n  name: e\n  pattern: 'eval\\('\n  severity: ${DEVSEC_RULE_SEVERITY}\n  category: ${DEVSEC_CATEGORY:-Injection}\n  description: d\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := LoadRules(writeFile(t, tt.name, tt.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rules) != 1 || rules[0].Severity != "CRITICAL" || rules[0].Category != "Injection" {
+				t.Errorf("rules %+v, want severity and category expanded", rules)
+			}
+		})
+	}
+
+	_, err := LoadRules(writeFile(t, "rules.json", `[{"id": "EVAL", "severity": "${DEVSEC_UNDEFINED}"}]`))
+	if err == nil || !strings.Contains(err.Error(), "undefined environment variable DEVSEC_UNDEFINED") {
+		t.Errorf("LoadRules error %v, want the undefined variable named", err)
+	}
+}
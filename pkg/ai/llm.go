@@ -0,0 +1,165 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// LLMClient enhances a finding using a large language model
+type LLMClient interface {
+	Enhance(ctx context.Context, finding models.Finding) (models.Finding, error)
+}
+
+// LLMConfig holds settings for the LLM-backed enhancement mode
+type LLMConfig struct {
+	BaseURL     string `json:"baseUrl"`
+	Model       string `json:"model"`
+	APIKeyEnv   string `json:"apiKeyEnv"`
+	TimeoutSecs int    `json:"timeoutSecs"`
+
+	// RequestsPerSecond limits the rate of enhancement requests and
+	// MaxConcurrent the number in flight; zero leaves them unlimited
+	RequestsPerSecond float64 `json:"requestsPerSecond"`
+	MaxConcurrent     int     `json:"maxConcurrent"`
+}
+
+// OpenAIClient is an LLMClient for OpenAI-compatible chat completion APIs
+type OpenAIClient struct {
+	baseURL    string
+	model      string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewOpenAIClient creates a client from config, reading the API key from the
+// configured environment variable (OPENAI_API_KEY by default)
+func NewOpenAIClient(config LLMConfig) (*OpenAIClient, error) {
+	keyEnv := config.APIKeyEnv
+	if keyEnv == "" {
+		keyEnv = "OPENAI_API_KEY"
+	}
+
+	apiKey := os.Getenv(keyEnv)
+	if apiKey == "" {
+		return nil, fmt.Errorf("environment variable %s is not set", keyEnv)
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+
+	model := config.Model
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+
+	timeout := time.Duration(config.TimeoutSecs) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &OpenAIClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		model:      model,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// llmSystemPrompt instructs the model to answer with a fixed JSON shape
+const llmSystemPrompt = `You are an application security expert. Given a security finding, ` +
+	`respond with a JSON object {"remediation": string, "severity": string} where remediation ` +
+	`is concise, specific guidance to fix the issue and severity is one of CRITICAL, HIGH, ` +
+	`MEDIUM, LOW or INFO reflecting the real risk in context.`
+
+// llmSuggestion is the JSON answer expected from the model
+type llmSuggestion struct {
+	Remediation string `json:"remediation"`
+	Severity    string `json:"severity"`
+}
+
+// Enhance asks the model for a remediation and severity for the finding
+func (c *OpenAIClient) Enhance(ctx context.Context, finding models.Finding) (models.Finding, error) {
+	prompt, err := json.Marshal(map[string]interface{}{
+		"title":       finding.Title,
+		"description": finding.Description,
+		"severity":    finding.Severity,
+		"category":    finding.Category,
+		"location":    finding.Location,
+		"codeSnippet": finding.CodeSnippet,
+	})
+	if err != nil {
+		return finding, err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"model": c.model,
+		"messages": []map[string]string{
+			{"role": "system", "content": llmSystemPrompt},
+			{"role": "user", "content": string(prompt)},
+		},
+		"response_format": map[string]string{"type": "json_object"},
+	})
+	if err != nil {
+		return finding, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return finding, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return finding, fmt.Errorf("LLM request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return finding, fmt.Errorf("failed to read LLM response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return finding, fmt.Errorf("LLM request returned %s", resp.Status)
+	}
+
+	var completion struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(data, &completion); err != nil {
+		return finding, fmt.Errorf("failed to parse LLM response: %v", err)
+	}
+	if len(completion.Choices) == 0 {
+		return finding, fmt.Errorf("LLM response contained no choices")
+	}
+
+	var suggestion llmSuggestion
+	if err := json.Unmarshal([]byte(completion.Choices[0].Message.Content), &suggestion); err != nil {
+		return finding, fmt.Errorf("failed to parse LLM suggestion: %v", err)
+	}
+
+	if suggestion.Remediation != "" {
+		finding.Remediation = suggestion.Remediation
+	}
+	if severity, err := models.ParseSeverity(suggestion.Severity); err == nil {
+		finding.Severity = severity
+	}
+
+	return finding, nil
+}
@@ -0,0 +1,49 @@
+//go:build integration
+
+package ai
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// TestOpenAIClientIntegration calls a live OpenAI-compatible API. Run it
+// with -tags integration and OPENAI_API_KEY set; LLM_BASE_URL and LLM_MODEL
+// select another endpoint or model.
+func TestOpenAIClientIntegration(t *testing.T) {
+	if os.Getenv("OPENAI_API_KEY") == "" {
+		t.Skip("OPENAI_API_KEY is not set")
+	}
+
+	client, err := NewOpenAIClient(LLMConfig{
+		BaseURL: os.Getenv("LLM_BASE_URL"),
+		Model:   os.Getenv("LLM_MODEL"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	finding, err := client.Enhance(ctx, models.Finding{
+		Title:       "SQL injection",
+		Description: "Query built by string concatenation",
+		Severity:    models.SeverityMedium,
+		Category:    "Injection",
+		Location:    "db.go",
+		CodeSnippet: `db.Query("SELECT * FROM users WHERE id=" + id)`,
+	})
+	if err != nil {
+		t.Fatalf("Enhance: %v", err)
+	}
+	if finding.Remediation == "" {
+		t.Error("no remediation suggested")
+	}
+	if _, err := models.ParseSeverity(string(finding.Severity)); err != nil {
+		t.Errorf("suggested severity %q is not recognized", finding.Severity)
+	}
+}
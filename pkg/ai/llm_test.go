@@ -0,0 +1,160 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// fakeLLM is an LLMClient returning canned suggestions, or err when set
+type fakeLLM struct {
+	remediation string
+	severity    models.Severity
+	err         error
+	calls       int
+}
+
+func (f *fakeLLM) Enhance(ctx context.Context, finding models.Finding) (models.Finding, error) {
+	f.calls++
+	if f.err != nil {
+		return finding, f.err
+	}
+	finding.Remediation = f.remediation
+	finding.Severity = f.severity
+	return finding, nil
+}
+
+func TestLLMEnhancement(t *testing.T) {
+	tests := []struct {
+		name            string
+		llm             *fakeLLM
+		policy          SeverityPolicy
+		wantRemediation string
+		wantSeverity    models.Severity
+	}{
+		{"suggestion applied", &fakeLLM{remediation: "Validate input", severity: models.SeverityHigh}, SeverityFree, "Validate input", models.SeverityHigh},
+		{"severity frozen", &fakeLLM{remediation: "Validate input", severity: models.SeverityHigh}, SeverityFreeze, "Validate input", models.SeverityInfo},
+		{"failure falls back", &fakeLLM{err: errors.New("unavailable")}, SeverityFree, "", models.SeverityInfo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDetectorWithLogger("", quietLogger)
+			d.SetLLMClient(tt.llm)
+			if err := d.SetSeverityPolicy(tt.policy); err != nil {
+				t.Fatal(err)
+			}
+
+			findings := analyze(t, d, "x := 1")
+			if tt.llm.calls != 1 {
+				t.Errorf("LLM called %d times, want 1", tt.llm.calls)
+			}
+			got := findings[0]
+			if tt.wantRemediation != "" && got.Remediation != tt.wantRemediation {
+				t.Errorf("remediation %q, want %q", got.Remediation, tt.wantRemediation)
+			}
+			if got.Severity != tt.wantSeverity {
+				t.Errorf("severity %s, want %s", got.Severity, tt.wantSeverity)
+			}
+			if fellBack := strings.HasSuffix(got.Description, "(AI Verified)"); fellBack != (tt.llm.err != nil) {
+				t.Errorf("description %q, local fallback = %v, want %v", got.Description, fellBack, tt.llm.err != nil)
+			}
+		})
+	}
+}
+
+func TestOpenAIClientEnhance(t *testing.T) {
+	var request struct {
+		Model    string              `json:"model"`
+		Messages []map[string]string `json:"messages"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" || r.Header.Get("Authorization") != "Bearer test-key" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"choices": [{"message": {"content": "{\"remediation\": \"Use prepared statements\", \"severity\": \"critical\"}"}}]}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("TEST_LLM_KEY", "test-key")
+	client, err := NewOpenAIClient(LLMConfig{BaseURL: server.URL + "/", Model: "test-model", APIKeyEnv: "TEST_LLM_KEY"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	finding, err := client.Enhance(context.Background(), models.Finding{Title: "SQL injection", Severity: models.SeverityMedium})
+	if err != nil {
+		t.Fatalf("Enhance: %v", err)
+	}
+	if finding.Remediation != "Use prepared statements" || finding.Severity != models.SeverityCritical {
+		t.Errorf("got remediation %q severity %s", finding.Remediation, finding.Severity)
+	}
+	if request.Model != "test-model" || len(request.Messages) != 2 || !strings.Contains(request.Messages[1]["content"], "SQL injection") {
+		t.Errorf("unexpected request %+v", request)
+	}
+}
+
+func TestOpenAIClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	t.Setenv("TEST_LLM_KEY", "test-key")
+	client, err := NewOpenAIClient(LLMConfig{BaseURL: server.URL, APIKeyEnv: "TEST_LLM_KEY"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := models.Finding{Title: "t", Severity: models.SeverityLow}
+	finding, err := client.Enhance(context.Background(), original)
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("err = %v, want the HTTP status", err)
+	}
+	if !reflect.DeepEqual(finding, original) {
+		t.Errorf("failed enhancement changed the finding to %+v", finding)
+	}
+
+	t.Setenv("TEST_LLM_KEY", "")
+	if _, err := NewOpenAIClient(LLMConfig{APIKeyEnv: "TEST_LLM_KEY"}); err == nil {
+		t.Error("client created without an API key")
+	}
+}
+
+func TestAnalyzeNormalizesInputSeverities(t *testing.T) {
+	d := NewDetectorWithLogger("", quietLogger)
+	d.SetLLMClient(&fakeLLM{severity: models.SeverityLow})
+	if err := d.SetSeverityPolicy(SeverityAugmentOnly); err != nil {
+		t.Fatal(err)
+	}
+	d.maxPerSeverity = map[models.Severity]int{models.SeverityHigh: 1}
+
+	findings, err := d.Analyze([]models.Finding{
+		{ID: "H1", Location: "app.go", Line: 1, Severity: "high", Confidence: 0.8},
+		{ID: "H2", Location: "app.go", Line: 2, Severity: "HIGH", Confidence: 0.9},
+		{ID: "C1", Location: "app.go", Line: 3, Severity: "crit", Confidence: 0.8},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Aliased originals are not lowered under augment-only, rank as their
+	// level and share its cap
+	var got []string
+	for _, finding := range findings {
+		got = append(got, finding.ID+"="+string(finding.Severity))
+	}
+	if want := "C1=CRITICAL,H2=HIGH"; strings.Join(got, ",") != want {
+		t.Errorf("findings %v, want %s", got, want)
+	}
+}
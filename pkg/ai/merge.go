@@ -0,0 +1,117 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// mergeFindings combines findings reported by the same rule at the same
+// file and line, such as a scanner finding and the detector finding
+// synthesized from it. The first finding of each group is kept, taking the
+// highest confidence, the union of labels and tags and any fields it lacks.
+func mergeFindings(findings []models.Finding) []models.Finding {
+	index := make(map[string]int, len(findings))
+	merged := make([]models.Finding, 0, len(findings))
+
+	for _, finding := range findings {
+		// Findings without a rule cannot be attributed reliably
+		if finding.RuleID == "" {
+			merged = append(merged, finding)
+			continue
+		}
+
+		key := fmt.Sprintf("%s\x00%d\x00%s", finding.Location, finding.Line, finding.RuleID)
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(merged)
+			merged = append(merged, finding)
+			continue
+		}
+
+		mergeInto(&merged[i], finding)
+	}
+
+	return merged
+}
+
+// mergeInto folds other into f
+func mergeInto(f *models.Finding, other models.Finding) {
+	if other.Confidence > f.Confidence {
+		f.Confidence = other.Confidence
+	}
+	f.Labels = mergeLabels(f.Labels, other.Labels)
+	f.Tags = mergeTags(f.Tags, other.Tags)
+
+	if f.Remediation == "" {
+		f.Remediation = other.Remediation
+	}
+	if f.CWE == "" {
+		f.CWE = other.CWE
+	}
+	if f.OWASP == "" {
+		f.OWASP = other.OWASP
+	}
+	if len(f.Metadata) == 0 {
+		f.Metadata = other.Metadata
+	}
+	if f.CodeSnippet == "" {
+		f.CodeSnippet = other.CodeSnippet
+	}
+	if len(f.Context) == 0 {
+		f.Context = other.Context
+	}
+	if f.Column == 0 {
+		f.Column = other.Column
+	}
+}
+
+// mergeLabels returns the union of two label sets, keeping the higher score
+// for a category present in both
+func mergeLabels(a, b []models.CategoryScore) []models.CategoryScore {
+	if len(b) == 0 {
+		return a
+	}
+
+	merged := append([]models.CategoryScore(nil), a...)
+	for _, label := range b {
+		found := false
+		for i := range merged {
+			if merged[i].Category == label.Category {
+				if label.Score > merged[i].Score {
+					merged[i].Score = label.Score
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, label)
+		}
+	}
+	return merged
+}
+
+// mergeTags returns the union of two tag sets, keeping the first spelling of
+// a tag present in both regardless of case
+func mergeTags(a, b []string) []string {
+	if len(b) == 0 {
+		return a
+	}
+
+	merged := append([]string(nil), a...)
+	for _, tag := range b {
+		found := false
+		for _, existing := range merged {
+			if strings.EqualFold(existing, tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
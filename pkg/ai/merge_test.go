@@ -0,0 +1,62 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+func TestMergeFindings(t *testing.T) {
+	findings := []models.Finding{
+		{
+			ID: "scanner", RuleID: "EVAL", Location: "app.py", Line: 3, Column: 5,
+			Confidence: 0.6, CodeSnippet: "eval(data)",
+			Tags:   []string{"Python", "injection"},
+			Labels: []models.CategoryScore{{Category: "Injection", Score: 0.5}},
+		},
+		{ID: "other-line", RuleID: "EVAL", Location: "app.py", Line: 4},
+		{ID: "other-rule", RuleID: "SQLI", Location: "app.py", Line: 3},
+		{
+			ID: "detector", RuleID: "EVAL", Location: "app.py", Line: 3, Column: 1,
+			Confidence: 0.9, Remediation: "Avoid eval", CWE: "CWE-95",
+			Tags:   []string{"python", "rce"},
+			Labels: []models.CategoryScore{{Category: "Injection", Score: 0.8}, {Category: "RCE", Score: 0.4}},
+		},
+		{ID: "no-rule-1", Location: "app.py", Line: 3},
+		{ID: "no-rule-2", Location: "app.py", Line: 3},
+	}
+
+	got := mergeFindings(findings)
+
+	var ids []string
+	for _, finding := range got {
+		ids = append(ids, finding.ID)
+	}
+	if want := []string{"scanner", "other-line", "other-rule", "no-rule-1", "no-rule-2"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("merged IDs %v, want %v", ids, want)
+	}
+
+	merged := got[0]
+	if merged.Confidence != 0.9 {
+		t.Errorf("confidence %v, want the higher 0.9", merged.Confidence)
+	}
+	if merged.Column != 5 || merged.CodeSnippet != "eval(data)" {
+		t.Errorf("column %d, snippet %q, want the first finding's kept", merged.Column, merged.CodeSnippet)
+	}
+	if merged.Remediation != "Avoid eval" || merged.CWE != "CWE-95" {
+		t.Errorf("remediation %q, CWE %q, want the missing fields filled", merged.Remediation, merged.CWE)
+	}
+	if want := []string{"Python", "injection", "rce"}; !reflect.DeepEqual(merged.Tags, want) {
+		t.Errorf("tags %v, want the case-insensitive union %v", merged.Tags, want)
+	}
+	wantLabels := []models.CategoryScore{{Category: "Injection", Score: 0.8}, {Category: "RCE", Score: 0.4}}
+	if !reflect.DeepEqual(merged.Labels, wantLabels) {
+		t.Errorf("labels %v, want %v", merged.Labels, wantLabels)
+	}
+
+	// The input's labels are not modified through the shared slice
+	if findings[0].Labels[0].Score != 0.5 {
+		t.Errorf("input label score changed to %v", findings[0].Labels[0].Score)
+	}
+}
@@ -0,0 +1,60 @@
+package ai
+
+import (
+	"path/filepath"
+	"regexp"
+
+	"github.com/SofNam/devsecops-ai/pkg/glob"
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// PathSeverityRule raises or lowers the severity of findings whose location
+// matches a path glob, so that the same issue weighs differently in test
+// code and in production configuration
+type PathSeverityRule struct {
+	// Pattern is a slash-separated glob such as "**/test/**"
+	Pattern string `json:"pattern"`
+	// Adjust is the number of levels to raise the severity by, negative to
+	// lower it
+	Adjust int `json:"adjust"`
+
+	compiled *regexp.Regexp
+}
+
+// compilePathSeverity compiles the path rules, skipping invalid patterns
+func (d *Detector) compilePathSeverity(rules []PathSeverityRule) []PathSeverityRule {
+	var compiled []PathSeverityRule
+	for _, rule := range rules {
+		re, err := glob.Compile(rule.Pattern)
+		if err != nil {
+			d.logger.Warnf("Ignoring path severity rule %q: %v", rule.Pattern, err)
+			continue
+		}
+		rule.compiled = re
+		compiled = append(compiled, rule)
+	}
+	return compiled
+}
+
+// applyPathSeverity adjusts finding severities by location relative to the
+// scan target, or as reported when target is empty. Rules are checked in
+// order and the first matching rule applies.
+func (d *Detector) applyPathSeverity(findings []models.Finding, target string) {
+	for i := range findings {
+		location := filepath.ToSlash(findings[i].Location)
+		if target != "" {
+			location = models.RelativeLocation(findings[i].Location, target)
+		}
+		for _, rule := range d.pathSeverity {
+			if !rule.compiled.MatchString(location) {
+				continue
+			}
+			adjusted := findings[i].Severity.Adjust(rule.Adjust)
+			if adjusted != findings[i].Severity {
+				d.logger.Debugf("Adjusting %s at %s from %s to %s", findings[i].ID, findings[i].Location, findings[i].Severity, adjusted)
+				findings[i].Severity = adjusted
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package ai
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+func TestApplyPathSeverity(t *testing.T) {
+	d := newTestDetector()
+	d.pathSeverity = d.compilePathSeverity([]PathSeverityRule{
+		{Pattern: "**/test/**", Adjust: -2},
+		{Pattern: "config/**", Adjust: 1},
+		{Pattern: "config/prod/**", Adjust: 3},
+	})
+	tests := []struct {
+		location string
+		severity models.Severity
+		want     models.Severity
+	}{
+		{"src/test/db_test.go", models.SeverityHigh, models.SeverityLow},
+		{"src/db.go", models.SeverityHigh, models.SeverityHigh},
+		{"config/app.yaml", models.SeverityMedium, models.SeverityHigh},
+		// The first matching rule applies, not the more specific one
+		{"config/prod/app.yaml", models.SeverityMedium, models.SeverityHigh},
+		// Adjustments stop at the ends of the scale
+		{"config/app.yaml", models.SeverityCritical, models.SeverityCritical},
+		{"pkg/test/x.go", models.SeverityLow, models.SeverityInfo},
+		{"config/app.yaml", "bogus", "bogus"},
+	}
+	for _, tt := range tests {
+		findings := []models.Finding{{ID: "F", Location: tt.location, Severity: tt.severity}}
+		d.applyPathSeverity(findings, "")
+		if got := findings[0].Severity; got != tt.want {
+			t.Errorf("%s at %s adjusted to %s, want %s", tt.severity, tt.location, got, tt.want)
+		}
+	}
+}
+
+func TestApplyPathSeverityRelativeToTarget(t *testing.T) {
+	d := newTestDetector()
+	d.pathSeverity = d.compilePathSeverity([]PathSeverityRule{
+		{Pattern: "**/config/**", Adjust: -1},
+		{Pattern: "deploy/**", Adjust: 1},
+	})
+	// An absolute target below a directory named like a rule's pattern
+	target := filepath.Join(t.TempDir(), "config", "app")
+
+	tests := []struct {
+		location string
+		want     models.Severity
+	}{
+		{filepath.Join(target, "a.go"), models.SeverityHigh},
+		{filepath.Join(target, "config", "a.go"), models.SeverityMedium},
+		{filepath.Join(target, "deploy", "a.yaml"), models.SeverityCritical},
+	}
+	for _, tt := range tests {
+		findings := []models.Finding{{ID: "F", Location: tt.location, Severity: models.SeverityHigh}}
+		d.applyPathSeverity(findings, target)
+		if got := findings[0].Severity; got != tt.want {
+			t.Errorf("HIGH at %s adjusted to %s, want %s", tt.location, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// SeverityPolicy controls whether finding enhancement may change severities
+type SeverityPolicy string
+
+const (
+	// SeverityFreeze keeps the severity a finding had before enhancement
+	SeverityFreeze SeverityPolicy = "freeze"
+	// SeverityAugmentOnly lets enhancement raise severities but not lower them
+	SeverityAugmentOnly SeverityPolicy = "augment-only"
+	// SeverityFree lets enhancement set any severity
+	SeverityFree SeverityPolicy = "free"
+)
+
+// ParseSeverityPolicy parses a policy name case-insensitively
+func ParseSeverityPolicy(s string) (SeverityPolicy, error) {
+	switch policy := SeverityPolicy(strings.ToLower(strings.TrimSpace(s))); policy {
+	case SeverityFreeze, SeverityAugmentOnly, SeverityFree:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown severity policy %q, expected freeze, augment-only or free", s)
+	}
+}
+
+// SetSeverityPolicy sets how enhancement may change severities
+func (d *Detector) SetSeverityPolicy(policy SeverityPolicy) error {
+	policy, err := ParseSeverityPolicy(string(policy))
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.severityPolicy = policy
+	d.overrides.severityPolicy = true
+	return nil
+}
+
+// applySeverityPolicy returns the enhanced finding with its severity
+// restricted by the policy relative to the original finding. Enhanced
+// severities that are not recognized never replace the original.
+func (d *Detector) applySeverityPolicy(original, enhanced models.Finding) models.Finding {
+	if enhanced.Severity == original.Severity {
+		return enhanced
+	}
+
+	proposed, err := models.ParseSeverity(string(enhanced.Severity))
+	keep := err != nil
+	switch d.severityPolicy {
+	case SeverityFree:
+	case SeverityAugmentOnly:
+		keep = keep || proposed.Rank() >= original.Severity.Rank()
+	default:
+		keep = true
+	}
+
+	if keep {
+		d.logger.Debugf("Keeping severity %s of %s, enhancement proposed %s under %s policy",
+			original.Severity, original.ID, enhanced.Severity, d.severityPolicy)
+		enhanced.Severity = original.Severity
+		return enhanced
+	}
+
+	enhanced.Severity = proposed
+	return enhanced
+}
@@ -0,0 +1,56 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+func TestApplySeverityPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   SeverityPolicy
+		original models.Severity
+		proposed models.Severity
+		want     models.Severity
+	}{
+		{"augment raises", SeverityAugmentOnly, models.SeverityMedium, models.SeverityHigh, models.SeverityHigh},
+		{"augment refuses lowering", SeverityAugmentOnly, models.SeverityHigh, models.SeverityLow, models.SeverityHigh},
+		{"augment canonicalizes alias", SeverityAugmentOnly, models.SeverityLow, "critical", models.SeverityCritical},
+		{"augment refuses unknown", SeverityAugmentOnly, models.SeverityLow, "severe", models.SeverityLow},
+		{"freeze keeps", SeverityFreeze, models.SeverityMedium, models.SeverityCritical, models.SeverityMedium},
+		{"unset policy freezes", "", models.SeverityMedium, models.SeverityCritical, models.SeverityMedium},
+		{"free lowers", SeverityFree, models.SeverityHigh, models.SeverityInfo, models.SeverityInfo},
+		{"free refuses unknown", SeverityFree, models.SeverityHigh, "severe", models.SeverityHigh},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDetector()
+			d.severityPolicy = tt.policy
+			original := models.Finding{ID: "F", Severity: tt.original}
+			enhanced := models.Finding{ID: "F", Severity: tt.proposed, Remediation: "fix it"}
+
+			got := d.applySeverityPolicy(original, enhanced)
+			if got.Severity != tt.want {
+				t.Errorf("severity %q, want %q", got.Severity, tt.want)
+			}
+			if got.Remediation != "fix it" {
+				t.Error("enhancement other than severity dropped")
+			}
+		})
+	}
+}
+
+func TestParseSeverityPolicy(t *testing.T) {
+	for input, want := range map[string]SeverityPolicy{"freeze": SeverityFreeze, " Augment-Only ": SeverityAugmentOnly, "FREE": SeverityFree} {
+		if got, err := ParseSeverityPolicy(input); err != nil || got != want {
+			t.Errorf("ParseSeverityPolicy(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseSeverityPolicy("loose"); err == nil {
+		t.Error("ParseSeverityPolicy accepted an unknown policy")
+	}
+	if err := newTestDetector().SetSeverityPolicy("loose"); err == nil {
+		t.Error("SetSeverityPolicy accepted an unknown policy")
+	}
+}
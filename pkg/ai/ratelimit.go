@@ -0,0 +1,61 @@
+package ai
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// rateLimitedClient bounds the request rate and concurrency of an LLMClient
+// shared by concurrent analyses
+type rateLimitedClient struct {
+	client LLMClient
+
+	// limiter spaces request starts evenly, nil for no limit
+	limiter *rate.Limiter
+
+	// slots holds a token per request in flight, nil for no limit
+	slots chan struct{}
+}
+
+// newRateLimitedClient wraps client so that it starts at most
+// requestsPerSecond requests per second and runs at most maxConcurrent at
+// once. A value of zero or less leaves that dimension unlimited.
+func newRateLimitedClient(client LLMClient, requestsPerSecond float64, maxConcurrent int) LLMClient {
+	if requestsPerSecond <= 0 && maxConcurrent <= 0 {
+		return client
+	}
+
+	limited := &rateLimitedClient{client: client}
+	if requestsPerSecond > 0 {
+		// A burst of one keeps requests evenly spaced rather than letting
+		// a second's worth start together
+		limited.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), 1)
+	}
+	if maxConcurrent > 0 {
+		limited.slots = make(chan struct{}, maxConcurrent)
+	}
+	return limited
+}
+
+// Enhance waits for a free slot and the next permitted start time, then
+// calls the wrapped client. It returns ctx's error if ctx ends first.
+func (c *rateLimitedClient) Enhance(ctx context.Context, finding models.Finding) (models.Finding, error) {
+	if c.slots != nil {
+		select {
+		case c.slots <- struct{}{}:
+			defer func() { <-c.slots }()
+		case <-ctx.Done():
+			return finding, ctx.Err()
+		}
+	}
+
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return finding, err
+		}
+	}
+	return c.client.Enhance(ctx, finding)
+}
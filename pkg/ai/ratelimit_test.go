@@ -0,0 +1,101 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// slowLLM is an LLMClient that holds each call for delay, recording the
+// most calls in flight at once
+type slowLLM struct {
+	delay    time.Duration
+	inFlight atomic.Int32
+	peak     atomic.Int32
+}
+
+func (s *slowLLM) Enhance(ctx context.Context, finding models.Finding) (models.Finding, error) {
+	n := s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+	for {
+		peak := s.peak.Load()
+		if n <= peak || s.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(s.delay)
+	return finding, nil
+}
+
+// enhanceAll calls client once per finding from concurrent goroutines
+func enhanceAll(t *testing.T, client LLMClient, n int) {
+	t.Helper()
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Enhance(context.Background(), models.Finding{}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRateLimitSpacesRequests(t *testing.T) {
+	const calls, perSecond = 6, 50
+	client := newRateLimitedClient(&slowLLM{}, perSecond, 0)
+
+	start := time.Now()
+	enhanceAll(t, client, calls)
+
+	// The first call starts at once, each later one a 1/perSecond after
+	if elapsed, min := time.Since(start), (calls-1)*time.Second/perSecond; elapsed < min {
+		t.Errorf("%d calls took %v, want at least %v", calls, elapsed, min)
+	}
+}
+
+func TestRateLimitBoundsConcurrency(t *testing.T) {
+	llm := &slowLLM{delay: 20 * time.Millisecond}
+	enhanceAll(t, newRateLimitedClient(llm, 0, 2), 8)
+	if peak := llm.peak.Load(); peak != 2 {
+		t.Errorf("peak of %d calls in flight, want 2", peak)
+	}
+}
+
+func TestRateLimitHonorsContext(t *testing.T) {
+	client := newRateLimitedClient(&slowLLM{}, 1, 0)
+	if _, err := client.Enhance(context.Background(), models.Finding{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The next slot is a second away, beyond the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.Enhance(ctx, models.Finding{}); err == nil {
+		t.Fatal("Enhance succeeded past the context deadline")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Enhance returned after %v, want it to give up early", elapsed)
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Enhance(canceled, models.Finding{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Enhance error %v, want context.Canceled", err)
+	}
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+	llm := &slowLLM{}
+	if client := newRateLimitedClient(llm, 0, 0); client != LLMClient(llm) {
+		t.Error("client wrapped with no limits set")
+	}
+}
@@ -0,0 +1,58 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/SofNam/devsecops-ai/pkg/logging"
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// genericRemediation is used when no rule or category guidance applies
+const genericRemediation = "AI suggested: Review and sanitize all inputs"
+
+// loadRemediations reads category remediation templates from a JSON object
+// mapping category names to template text. Templates that fail to parse are
+// skipped with a warning.
+func loadRemediations(path string, logger logging.Logger) (map[string]*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var texts map[string]string
+	if err := json.Unmarshal(data, &texts); err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", path, err)
+	}
+
+	templates := make(map[string]*template.Template, len(texts))
+	for category, text := range texts {
+		tmpl, err := template.New(category).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			logger.Warnf("Ignoring remediation template for %s: %v", category, err)
+			continue
+		}
+		templates[strings.ToLower(category)] = tmpl
+	}
+
+	return templates, nil
+}
+
+// remediation returns guidance for a finding without one, rendered from
+// its category template or the generic fallback
+func (d *Detector) remediation(finding models.Finding) string {
+	tmpl, ok := d.remediations[strings.ToLower(finding.Category)]
+	if !ok {
+		return genericRemediation
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, finding); err != nil {
+		d.logger.Warnf("Remediation template for %s failed: %v", finding.Category, err)
+		return genericRemediation
+	}
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+func TestRemediation(t *testing.T) {
+	dir := writeModel(t, map[string]string{
+		"rules.json": `[{"id": "EVAL", "name": "Eval", "pattern": "eval\\(", "severity": "high", "category": "Injection", "description": "d"}]`,
+		"remediations.json": `{
+			"injection": "Avoid {{.Title}} at {{.Location}}:{{.Line}}",
+			"Broken": "{{.Title"
+		}`,
+	})
+	d := NewDetectorWithLogger(dir, quietLogger)
+
+	findings, err := d.Analyze([]models.Finding{
+		{ID: "OWN", Location: "a.go", Line: 1, Category: "Injection", Remediation: "Keep this advice", Confidence: 0.9},
+		{ID: "TPL", Title: "String eval", Location: "b.go", Line: 7, Category: "INJECTION", Confidence: 0.9},
+		{ID: "BROKEN", Location: "c.go", Line: 1, Category: "Broken", Confidence: 0.9},
+		{ID: "NONE", Location: "d.go", Line: 1, Category: "Misc", Confidence: 0.9},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"OWN":    "Keep this advice",
+		"TPL":    "Avoid String eval at b.go:7",
+		"BROKEN": genericRemediation,
+		"NONE":   genericRemediation,
+	}
+	for _, finding := range findings {
+		expected, ok := want[finding.ID]
+		if !ok {
+			continue
+		}
+		if finding.Remediation != expected {
+			t.Errorf("%s remediation %q, want %q", finding.ID, finding.Remediation, expected)
+		}
+		delete(want, finding.ID)
+	}
+	if len(want) > 0 {
+		t.Errorf("findings missing from the analysis: %v", want)
+	}
+
+	// Rule findings render their category template
+	rule := findingFor(t, analyze(t, d, "eval(x)"), "EVAL")
+	if rule.Remediation != "Avoid Eval at app.go:1" {
+		t.Errorf("rule remediation %q", rule.Remediation)
+	}
+}
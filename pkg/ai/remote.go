@@ -0,0 +1,121 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/logging"
+)
+
+// maxRemoteRulesBytes bounds the size of a downloaded rules file
+const maxRemoteRulesBytes = 10 << 20
+
+// IsRemoteRules reports whether a rules location is an HTTP(S) URL
+func IsRemoteRules(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// FetchRules downloads a JSON rules file into cacheDir and returns the path
+// of the local copy. The copy's ETag is stored alongside it so an unchanged
+// file is not downloaded again. A download that fails, or whose rules do not
+// load and validate, falls back to the cached copy with a warning; the
+// error is returned only when there is no cached copy.
+func FetchRules(ctx context.Context, url, cacheDir string, timeout time.Duration, logger logging.Logger) (string, error) {
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", fmt.Errorf("creating rules cache: %v", err)
+	}
+
+	sum := sha256.Sum256([]byte(url))
+	path := filepath.Join(cacheDir, "rules-"+hex.EncodeToString(sum[:8])+".json")
+
+	err := fetchRules(ctx, url, path, timeout)
+	if err == nil {
+		return path, nil
+	}
+	if _, statErr := os.Stat(path); statErr != nil {
+		return "", fmt.Errorf("fetching rules from %s: %v", url, err)
+	}
+	logger.Warnf("Using cached rules for %s: %v", url, err)
+	return path, nil
+}
+
+// fetchRules refreshes the cached copy at path, leaving it untouched when
+// the server reports it unchanged
+func fetchRules(ctx context.Context, url, path string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	etagPath := path + ".etag"
+	if _, err := os.Stat(path); err == nil {
+		if etag, err := os.ReadFile(etagPath); err == nil && len(etag) > 0 {
+			req.Header.Set("If-None-Match", string(etag))
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil
+	default:
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteRulesBytes+1))
+	if err != nil {
+		return fmt.Errorf("reading response: %v", err)
+	}
+	if len(data) > maxRemoteRulesBytes {
+		return fmt.Errorf("rules exceed %d bytes", maxRemoteRulesBytes)
+	}
+
+	// Validate before replacing the cached copy
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".rules-*.json")
+	if err != nil {
+		return fmt.Errorf("caching rules: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("caching rules: %v", err)
+	}
+	if _, err := LoadRules(tmp.Name()); err != nil {
+		return fmt.Errorf("invalid rules: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("caching rules: %v", err)
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		if err := os.WriteFile(etagPath, []byte(etag), 0644); err != nil {
+			return fmt.Errorf("caching rules: %v", err)
+		}
+	} else {
+		os.Remove(etagPath)
+	}
+
+	return nil
+}
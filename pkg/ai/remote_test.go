@@ -0,0 +1,142 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/logging"
+)
+
+// rulesServer serves a rules document with an ETag, answering conditional
+// requests for the current ETag with 304 Not Modified
+type rulesServer struct {
+	mu          sync.Mutex
+	body        string
+	etag        string
+	served      int
+	notModified int
+}
+
+func (s *rulesServer) set(body, etag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.body, s.etag = body, etag
+}
+
+// counts returns how often the document was served and found unchanged
+func (s *rulesServer) counts() (served, notModified int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.served, s.notModified
+}
+
+func (s *rulesServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.Header.Get("If-None-Match") == s.etag {
+		s.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	s.served++
+	w.Header().Set("ETag", s.etag)
+	w.Write([]byte(s.body))
+}
+
+func TestFetchRules(t *testing.T) {
+	rules := &rulesServer{body: watchRules("EVAL"), etag: `"v1"`}
+	ts := httptest.NewServer(rules)
+	defer ts.Close()
+
+	var logs syncBuffer
+	logger := logging.New(&logs, logging.LevelWarn)
+	cache := t.TempDir()
+	fetch := func() (string, error) {
+		return FetchRules(context.Background(), ts.URL+"/rules.json", cache, time.Second, logger)
+	}
+	cachedIDs := func(path string) []string {
+		t.Helper()
+		loaded, err := LoadRules(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, rule := range loaded {
+			ids = append(ids, rule.ID)
+		}
+		return ids
+	}
+
+	path, err := fetch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := cachedIDs(path); strings.Join(ids, ",") != "EVAL" {
+		t.Errorf("cached rules %v, want EVAL", ids)
+	}
+
+	// An unchanged file is not downloaded again
+	if again, err := fetch(); err != nil || again != path {
+		t.Fatalf("refetch = %q, %v", again, err)
+	}
+	if served, notModified := rules.counts(); served != 1 || notModified != 1 {
+		t.Errorf("served %d and 304 %d times, want 1 each", served, notModified)
+	}
+
+	// A changed file replaces the cached copy
+	rules.set(watchRules("EVAL", "EXEC"), `"v2"`)
+	if _, err := fetch(); err != nil {
+		t.Fatal(err)
+	}
+	if ids := cachedIDs(path); strings.Join(ids, ",") != "EVAL,EXEC" {
+		t.Errorf("cached rules %v after an update, want EVAL,EXEC", ids)
+	}
+
+	// Invalid rules and an unreachable server fall back to the cache
+	rules.set(`[{"id": "BROKEN", "pattern": "("}]`, `"v3"`)
+	if got, err := fetch(); err != nil || got != path {
+		t.Fatalf("fetch of invalid rules = %q, %v, want the cached copy", got, err)
+	}
+	ts.Close()
+	if got, err := fetch(); err != nil || got != path {
+		t.Fatalf("offline fetch = %q, %v, want the cached copy", got, err)
+	}
+	if ids := cachedIDs(path); strings.Join(ids, ",") != "EVAL,EXEC" {
+		t.Errorf("cached rules %v after failed fetches, want them kept", ids)
+	}
+	if got := strings.Count(logs.String(), "Using cached rules"); got != 2 {
+		t.Errorf("logged %d fallbacks, want 2:\n%s", got, logs.String())
+	}
+
+	// Without a cached copy the failure is returned
+	if _, err := FetchRules(context.Background(), ts.URL+"/rules.json", t.TempDir(), time.Second, logger); err == nil {
+		t.Error("offline fetch without a cache succeeded")
+	}
+}
+
+func TestFetchRulesWithoutETag(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			t.Error("conditional request sent without a stored ETag")
+		}
+		w.Write([]byte(watchRules("EVAL")))
+	}))
+	defer ts.Close()
+
+	cache := t.TempDir()
+	for i := 0; i < 2; i++ {
+		path, err := FetchRules(context.Background(), ts.URL, cache, time.Second, quietLogger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(path + ".etag"); !os.IsNotExist(err) {
+			t.Errorf("ETag stored although the server sent none: %v", err)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// rulesDocument is the on-disk layout of a rules file
+type rulesDocument struct {
+	Rules []Rule `json:"rules" yaml:"rules"`
+}
+
+// LoadModelRules loads the rules for a model path. A "rules" directory takes
+// precedence, then rules.yaml or rules.yml, then rules.json; when none exists
+// the returned error wraps os.ErrNotExist.
+func LoadModelRules(modelPath string) ([]Rule, error) {
+	dir := filepath.Join(modelPath, "rules")
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return LoadRulesDir(dir)
+	}
+
+	for _, name := range []string{"rules.yaml", "rules.yml"} {
+		path := filepath.Join(modelPath, name)
+		if _, err := os.Stat(path); err == nil {
+			return LoadRules(path)
+		}
+	}
+
+	return LoadRules(filepath.Join(modelPath, "rules.json"))
+}
+
+// LoadRulesDir loads and merges every JSON and YAML rules file in a
+// directory. Rule IDs must be unique across all files.
+func LoadRulesDir(dir string) ([]Rule, error) {
+	var paths []string
+	for _, pattern := range []string{"*.json", "*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+
+	var merged []Rule
+	definedIn := make(map[string]string)
+
+	for _, path := range paths {
+		rules, err := readRules(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+
+		for _, rule := range rules {
+			if first, ok := definedIn[rule.ID]; ok && rule.ID != "" {
+				return nil, fmt.Errorf("duplicate rule ID %s in %s (first defined in %s)", rule.ID, path, first)
+			}
+			definedIn[rule.ID] = path
+			merged = append(merged, rule)
+		}
+	}
+
+	// Resolve inheritance across files once every rule is known
+	return prepareRules(merged)
+}
+
+// LoadRules loads security rules from a JSON or YAML file, chosen by
+// extension, resolves their inheritance and compiles their patterns. The
+// file may hold either a document with a "rules" list or a bare list of
+// rules. Invalid rules are dropped and reported through a *ValidationError
+// alongside the valid ones.
+func LoadRules(path string) ([]Rule, error) {
+	rules, err := readRules(path)
+	if err != nil {
+		return nil, err
+	}
+	return prepareRules(rules)
+}
+
+// readRules decodes a rules file, expanding environment variable
+// references, without validating it
+func readRules(path string) ([]Rule, error) {
+	data, err := readModelFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return decodeYAMLRules(data)
+	default:
+		return decodeJSONRules(data)
+	}
+}
+
+// prepareRules resolves inheritance, then validates and compiles the rules,
+// reporting dropped rules through a *ValidationError
+func prepareRules(rules []Rule) ([]Rule, error) {
+	validationErr := &ValidationError{}
+
+	rules, extendsErrs := resolveExtends(rules)
+	validationErr.Errors = append(validationErr.Errors, extendsErrs...)
+
+	valid := make([]Rule, 0, len(rules))
+	for i, rule := range rules {
+		if errs := validateRule(i, rule); len(errs) > 0 {
+			validationErr.Errors = append(validationErr.Errors, errs...)
+			continue
+		}
+		// Store the canonical form so aliases match severity comparisons
+		severity, _ := models.ParseSeverity(rule.Severity)
+		rule.Severity = string(severity)
+		rule.compile()
+		valid = append(valid, rule)
+	}
+
+	if len(validationErr.Errors) > 0 {
+		return valid, validationErr
+	}
+	return valid, nil
+}
+
+// decodeJSONRules parses a JSON rules document or array
+func decodeJSONRules(data []byte) ([]Rule, error) {
+	var doc rulesDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		if arrErr := json.Unmarshal(data, &doc.Rules); arrErr != nil {
+			return nil, err
+		}
+	}
+	return doc.Rules, nil
+}
+
+// decodeYAMLRules parses a YAML rules document or list
+func decodeYAMLRules(data []byte) ([]Rule, error) {
+	var doc rulesDocument
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		if listErr := yaml.Unmarshal(data, &doc.Rules); listErr != nil {
+			return nil, err
+		}
+	}
+	return doc.Rules, nil
+}
+
+// RuleError describes a problem with one field of a rule
+type RuleError struct {
+	RuleID  string
+	Field   string
+	Message string
+}
+
+func (e RuleError) Error() string {
+	return fmt.Sprintf("rule %s: %s: %s", e.RuleID, e.Field, e.Message)
+}
+
+// ValidationError aggregates the problems found across a rule set
+type ValidationError struct {
+	Errors []RuleError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// ValidateRules checks every rule and returns a *ValidationError naming each
+// offending rule and field, or nil when all rules are valid
+func ValidateRules(rules []Rule) error {
+	validationErr := &ValidationError{}
+
+	rules, extendsErrs := resolveExtends(rules)
+	validationErr.Errors = append(validationErr.Errors, extendsErrs...)
+
+	for i, rule := range rules {
+		validationErr.Errors = append(validationErr.Errors, validateRule(i, rule)...)
+	}
+
+	if len(validationErr.Errors) > 0 {
+		return validationErr
+	}
+	return nil
+}
+
+// validateRule returns the problems with a single rule, identifying rules
+// without an ID by their position
+func validateRule(index int, rule Rule) []RuleError {
+	var errs []RuleError
+
+	id := rule.ID
+	if id == "" {
+		id = fmt.Sprintf("#%d", index+1)
+		errs = append(errs, RuleError{RuleID: id, Field: "id", Message: "must not be empty"})
+	}
+
+	if _, err := models.ParseSeverity(rule.Severity); err != nil {
+		errs = append(errs, RuleError{RuleID: id, Field: "severity", Message: fmt.Sprintf("unrecognized value %q", rule.Severity)})
+	}
+
+	if rule.Pattern != "" {
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			errs = append(errs, RuleError{RuleID: id, Field: "pattern", Message: fmt.Sprintf("invalid regular expression: %v", err)})
+		}
+	}
+
+	for _, exclude := range rule.Exclude {
+		if _, err := regexp.Compile(exclude); err != nil {
+			errs = append(errs, RuleError{RuleID: id, Field: "exclude", Message: fmt.Sprintf("invalid regular expression %q: %v", exclude, err)})
+		}
+	}
+
+	if rule.Pattern == "" && len(rule.Keywords) == 0 {
+		errs = append(errs, RuleError{RuleID: id, Field: "pattern", Message: "a pattern or at least one keyword is required"})
+	}
+
+	return errs
+}
+
+// compile precompiles the rule patterns, which must already be validated
+func (r *Rule) compile() {
+	if r.Pattern != "" {
+		r.compiled = regexp.MustCompile(r.Pattern)
+	}
+	r.excludes = nil
+	for _, exclude := range r.Exclude {
+		r.excludes = append(r.excludes, regexp.MustCompile(exclude))
+	}
+}
+
+// Match returns the byte offsets of the first pattern match in s, or nil
+// when the rule has no pattern, does not match or an exclude pattern
+// matches s
+func (r *Rule) Match(s string) []int {
+	if r.compiled == nil {
+		return nil
+	}
+	match := r.compiled.FindStringIndex(s)
+	if match == nil || r.Excluded(s) {
+		return nil
+	}
+	return match
+}
+
+// Excluded reports whether any of the rule's exclude patterns matches s
+func (r *Rule) Excluded(s string) bool {
+	for _, re := range r.excludes {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+package ai
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFile writes content to name in a temporary directory and returns
+// its path
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// loadTestRules loads a JSON rules document, failing the test on error
+func loadTestRules(t *testing.T, content string) []Rule {
+	t.Helper()
+	rules, err := LoadRules(writeFile(t, "rules.json", content))
+	if err != nil {
+		t.Fatalf("LoadRules: %v", err)
+	}
+	return rules
+}
+
+func TestRulePatterns(t *testing.T) {
+	rules := loadTestRules(t, `[
+		{"id": "ANCHORED", "name": "a", "pattern": "^import os$", "severity": "low", "category": "C", "description": "d"},
+		{"id": "NOCASE", "name": "n", "pattern": "(?i)password\\s*=", "severity": "high", "category": "C", "description": "d"}
+	]`)
+
+	tests := []struct {
+		rule  int
+		input string
+		want  bool
+	}{
+		{0, "import os", true},
+		{0, "  import os", false},
+		{0, "import os.path", false},
+		{1, "password = 1", true},
+		{1, "PassWord=1", true},
+		{1, "pass word = 1", false},
+	}
+	for _, tt := range tests {
+		rule := &rules[tt.rule]
+		if got := rule.Match(tt.input) != nil; got != tt.want {
+			t.Errorf("%s.Match(%q) = %v, want %v", rule.ID, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestRuleExclude(t *testing.T) {
+	rules := loadTestRules(t, `[
+		{"id": "TODO", "name": "t", "pattern": "(?i)TODO.*security", "exclude": ["(?i)not a security TODO", "NOTE:"], "severity": "info", "category": "C", "description": "d"}
+	]`)
+	rule := &rules[0]
+
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"// TODO: security review", true},
+		{"// NOTE: not a security TODO", false},
+		{"// todo security, but Not A Security TODO really", false},
+		{"// nothing to see", false},
+	}
+	for _, tt := range tests {
+		if got := rule.Match(tt.input) != nil; got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestRulePatternsCompiledOnce(t *testing.T) {
+	rules := loadTestRules(t, `[{"id": "R", "name": "r", "pattern": "x+", "severity": "low", "category": "C", "description": "d"}]`)
+	if rules[0].compiled == nil {
+		t.Fatal("pattern not compiled at load time")
+	}
+	if got := rules[0].Match("axxb"); len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Errorf("Match = %v, want [1 3]", got)
+	}
+}
+
+func TestInvalidPatternNamesRule(t *testing.T) {
+	_, err := LoadRules(writeFile(t, "rules.json", `[{"id": "BROKEN", "name": "b", "pattern": "(", "severity": "low", "category": "C", "description": "d"}]`))
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("err = %v, want *ValidationError", err)
+	}
+	if !strings.Contains(err.Error(), "BROKEN") || !strings.Contains(err.Error(), "pattern") {
+		t.Errorf("error %q does not name the rule and field", err)
+	}
+}
+
+func TestLoadRulesDirMergesFiles(t *testing.T) {
+	dir := writeModel(t, map[string]string{
+		"rules/injection.json": `[{"id": "EVAL", "name": "Eval", "pattern": "eval\\(", "severity": "high", "category": "Injection", "description": "d"}]`,
+		"rules/secrets.yaml":   "rules:\n  - id: TOKEN\n    name: Token\n    pattern: 'token\\s*='\n    severity: high\n    category: Secrets\n    description: d\n",
+		"rules/notes.txt":      "not a rules file",
+	})
+
+	rules, err := LoadModelRules(dir)
+	if err != nil {
+		t.Fatalf("LoadModelRules: %v", err)
+	}
+	if len(rules) != 2 || rules[0].ID != "EVAL" || rules[1].ID != "TOKEN" {
+		t.Fatalf("got rules %+v, want EVAL and TOKEN", rules)
+	}
+
+	findings := analyze(t, NewDetectorWithLogger(dir, quietLogger), "eval(input)", `token = "abc"`)
+	findingFor(t, findings, "EVAL")
+	findingFor(t, findings, "TOKEN")
+}
+
+func TestLoadRulesDirDuplicateIDs(t *testing.T) {
+	dir := writeModel(t, map[string]string{
+		"a.json": `[{"id": "DUP", "name": "a", "pattern": "a", "severity": "low", "category": "C", "description": "d"}]`,
+		"b.yml":  "- id: DUP\n  name: b\n  pattern: b\n  severity: low\n  category: C\n  description: d\n",
+	})
+
+	_, err := LoadRulesDir(dir)
+	if err == nil {
+		t.Fatal("duplicate rule IDs loaded without error")
+	}
+	for _, want := range []string{"DUP", "a.json", "b.yml"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
+
+func TestYAMLAndJSONRulesEquivalent(t *testing.T) {
+	jsonDir := writeModel(t, map[string]string{"rules.json": `[
+		{"id": "SQLI", "name": "SQL", "pattern": "SELECT .* \\+", "severity": "high", "category": "Injection",
+		 "keywords": ["query"], "description": "d", "cwe": "CWE-89", "tags": ["owasp"]},
+		{"id": "TODO", "name": "Todo", "pattern": "TODO\\(security\\)", "severity": "low", "category": "Maintenance", "description": "d"}
+	]`})
+	yamlDir := writeModel(t, map[string]string{"rules.yaml": `
+- id: SQLI
+  name: SQL
+  pattern: 'SELECT .* \+'
+  severity: high
+  category: Injection
+  keywords: [query]
+  description: d
+  cwe: CWE-89
+  tags: [owasp]
+- id: TODO
+  name: Todo
+  pattern: 'TODO\(security\)'
+  severity: low
+  category: Maintenance
+  description: d
+`})
+
+	snippets := []string{`q := "SELECT * FROM t WHERE id=" + id`, "// TODO(security): check", "x := 1"}
+	fromJSON := analyze(t, NewDetectorWithLogger(jsonDir, quietLogger), snippets...)
+	fromYAML := analyze(t, NewDetectorWithLogger(yamlDir, quietLogger), snippets...)
+
+	findingFor(t, fromYAML, "SQLI")
+	findingFor(t, fromYAML, "TODO")
+	if len(fromJSON) != len(fromYAML) {
+		t.Fatalf("JSON rules gave %d findings, YAML rules %d", len(fromJSON), len(fromYAML))
+	}
+	for i := range fromJSON {
+		a, b := fromJSON[i], fromYAML[i]
+		a.Timestamp, b.Timestamp = time.Time{}, time.Time{}
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("finding %d differs:\nJSON: %+v\nYAML: %+v", i, a, b)
+		}
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	valid := Rule{ID: "OK", Pattern: "x", Severity: "low"}
+	tests := []struct {
+		name  string
+		rule  Rule
+		id    string
+		field string
+	}{
+		{"missing id", Rule{Pattern: "x", Severity: "low"}, "#2", "id"},
+		{"unknown severity", Rule{ID: "SEV", Pattern: "x", Severity: "severe"}, "SEV", "severity"},
+		{"invalid pattern", Rule{ID: "PAT", Pattern: "(", Severity: "low"}, "PAT", "pattern"},
+		{"invalid exclude", Rule{ID: "EXC", Pattern: "x", Exclude: []string{"["}, Severity: "low"}, "EXC", "exclude"},
+		{"no pattern or keywords", Rule{ID: "EMPTY", Severity: "low"}, "EMPTY", "pattern"},
+		{"unknown parent", Rule{ID: "CHILD", Pattern: "x", Severity: "low", Extends: "NOPE"}, "CHILD", "extends"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRules([]Rule{valid, tt.rule})
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("err = %v, want *ValidationError", err)
+			}
+			if len(validationErr.Errors) != 1 {
+				t.Fatalf("got %d errors, want 1: %v", len(validationErr.Errors), err)
+			}
+			if got := validationErr.Errors[0]; got.RuleID != tt.id || got.Field != tt.field {
+				t.Errorf("error names rule %s field %s, want %s %s", got.RuleID, got.Field, tt.id, tt.field)
+			}
+		})
+	}
+
+	if err := ValidateRules([]Rule{valid}); err != nil {
+		t.Errorf("valid rules: %v", err)
+	}
+}
+
+func TestLoadRulesKeepsValidRules(t *testing.T) {
+	rules, err := LoadRules(writeFile(t, "rules.json", `[
+		{"id": "GOOD", "name": "g", "pattern": "x", "severity": "medium", "category": "C", "description": "d"},
+		{"id": "BAD", "name": "b", "pattern": "x", "severity": "urgent", "category": "C", "description": "d"}
+	]`))
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Errors) != 1 {
+		t.Fatalf("err = %v, want one validation error", err)
+	}
+	if len(rules) != 1 || rules[0].ID != "GOOD" || rules[0].Severity != "MEDIUM" {
+		t.Errorf("got rules %+v, want GOOD with canonical severity", rules)
+	}
+}
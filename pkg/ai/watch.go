@@ -0,0 +1,115 @@
+package ai
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchedFiles are the files in a model path that Watch monitors
+var watchedFiles = []string{"config.json", "rules.json", "rules.yaml", "rules.yml", "remediations.json"}
+
+// Watch reloads the detector whenever the rules or configuration in its
+// model path change, until ctx is canceled. Reloading waits until no
+// change has been seen for delay, so that an editor's burst of writes
+// causes a single reload. A reload that fails keeps the current rules and
+// is retried on the next change.
+func (d *Detector) Watch(ctx context.Context, delay time.Duration) {
+	if d.modelPath == "" || delay <= 0 {
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		d.logger.Warnf("Not watching %s for rule changes: %v", d.modelPath, err)
+		return
+	}
+	defer watcher.Close()
+
+	// Directories are watched rather than files so that files replaced by
+	// renaming, as many editors save them, are still seen
+	d.watchDirs(watcher)
+
+	timer := time.NewTimer(delay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			d.logger.Warnf("Watching %s: %v", d.modelPath, err)
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !d.watched(event.Name) {
+				continue
+			}
+			// A rules directory created after Watch started is watched too
+			d.watchDirs(watcher)
+			timer.Reset(delay)
+		case <-timer.C:
+			if err := d.Reload(); err != nil {
+				d.logger.Warnf("Keeping current rules: %v", err)
+				continue
+			}
+			d.logger.Infof("Reloaded %d rules from %s", len(d.RuleIDs()), d.modelPath)
+		}
+	}
+}
+
+// watchDirs adds the model path, its rules directory and the directory of
+// a separate rules file to the watcher. Directories that do not exist are
+// skipped; adding a watched directory again has no effect.
+func (d *Detector) watchDirs(watcher *fsnotify.Watcher) {
+	for _, dir := range d.watchedDirs() {
+		if slices.Contains(watcher.WatchList(), dir) {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil && dir == filepath.Clean(d.modelPath) {
+			d.logger.Warnf("Watching %s: %v", dir, err)
+		}
+	}
+}
+
+// watchedDirs returns the directories holding the files Watch monitors
+func (d *Detector) watchedDirs() []string {
+	dirs := []string{filepath.Clean(d.modelPath), filepath.Join(d.modelPath, "rules")}
+
+	d.mu.RLock()
+	rulesPath := d.rulesPath
+	d.mu.RUnlock()
+	if rulesPath != "" {
+		dirs = append(dirs, filepath.Dir(filepath.Clean(rulesPath)))
+	}
+	return dirs
+}
+
+// watched reports whether a changed path affects the detector: one of the
+// watched model files, the rules directory or its entries, or the rules
+// file
+func (d *Detector) watched(path string) bool {
+	path = filepath.Clean(path)
+	dir := filepath.Dir(path)
+	rulesDir := filepath.Join(d.modelPath, "rules")
+
+	if path == rulesDir || dir == rulesDir {
+		return true
+	}
+	if dir == filepath.Clean(d.modelPath) && slices.Contains(watchedFiles, filepath.Base(path)) {
+		return true
+	}
+
+	d.mu.RLock()
+	rulesPath := d.rulesPath
+	d.mu.RUnlock()
+	return rulesPath != "" && path == filepath.Clean(rulesPath)
+}
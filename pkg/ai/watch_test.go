@@ -0,0 +1,229 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/logging"
+	"go.uber.org/goleak"
+)
+
+// watchRules returns a rules document with one rule per ID
+func watchRules(ids ...string) string {
+	var rules []string
+	for _, id := range ids {
+		rules = append(rules, `{"id": "`+id+`", "name": "n", "pattern": "`+strings.ToLower(id)+`\\(", "severity": "high", "category": "Injection", "description": "d", "tags": ["web"]}`)
+	}
+	return "[" + strings.Join(rules, ",") + "]"
+}
+
+// syncBuffer is a buffer safe for concurrent use by a logger and a test
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// watch runs Watch until the test ends, then checks that it stopped
+// without leaking goroutines
+func watch(t *testing.T, d *Detector) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Watch(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		goleak.VerifyNone(t)
+	})
+}
+
+// rewriteUntil writes content to path until cond holds, failing the test
+// after a few seconds. Rewriting covers writes made before the watcher
+// was set up.
+func rewriteUntil(t *testing.T, path, content string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the change to be picked up")
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// setOverrides applies a non-default value through every setter Reload
+// must preserve
+func setOverrides(t *testing.T, d *Detector) {
+	t.Helper()
+	if err := d.SetMinConfidence(0.2); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetSeverityPolicy(SeverityAugmentOnly); err != nil {
+		t.Fatal(err)
+	}
+	d.SetTags([]string{"web"})
+	d.SetCalibration(func(c float64) float64 { return c / 2 })
+}
+
+// checkOverrides verifies the values set by setOverrides are in effect
+func checkOverrides(t *testing.T, d *Detector) {
+	t.Helper()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.confidence != 0.2 {
+		t.Errorf("confidence %v, want 0.2", d.confidence)
+	}
+	if d.severityPolicy != SeverityAugmentOnly {
+		t.Errorf("severity policy %q, want %q", d.severityPolicy, SeverityAugmentOnly)
+	}
+	if len(d.tags) != 1 || !d.tags["web"] {
+		t.Errorf("tags %v, want web", d.tags)
+	}
+	if d.calibrate == nil || d.calibrate(0.8) != 0.4 {
+		t.Error("calibration replaced")
+	}
+}
+
+func TestReloadKeepsSetterOverrides(t *testing.T) {
+	dir := writeModel(t, map[string]string{
+		"rules.json":  watchRules("EVAL"),
+		"config.json": `{"confidence": 0.9, "maxFindings": 100, "tags": ["cli"], "severityPolicy": "free"}`,
+	})
+	d := NewDetectorWithLogger(dir, quietLogger)
+	setOverrides(t, d)
+
+	if err := d.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	checkOverrides(t, d)
+
+	// Settings never overridden still follow the configuration
+	fresh := NewDetectorWithLogger(dir, quietLogger)
+	if err := fresh.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if fresh.confidence != 0.9 {
+		t.Errorf("confidence %v, want 0.9 from the configuration", fresh.confidence)
+	}
+}
+
+func TestReloadAppliesChangedTags(t *testing.T) {
+	dir := writeModel(t, map[string]string{
+		"rules.json":  watchRules("EVAL"),
+		"config.json": `{"tags": ["web"]}`,
+	})
+	d := NewDetectorWithLogger(dir, quietLogger)
+
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"tags": ["CLI", "api"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if len(d.tags) != 2 || !d.tags["cli"] || !d.tags["api"] {
+		t.Errorf("tags %v after reload, want cli and api from the configuration", d.tags)
+	}
+}
+
+func TestReloadRemovesLLM(t *testing.T) {
+	t.Setenv("TEST_LLM_KEY", "test-key")
+	dir := writeModel(t, map[string]string{
+		"rules.json":  watchRules("EVAL"),
+		"config.json": `{"llm": {"baseUrl": "http://127.0.0.1:1", "apiKeyEnv": "TEST_LLM_KEY"}}`,
+	})
+	configured := NewDetectorWithLogger(dir, quietLogger)
+	explicit := NewDetectorWithLogger(dir, quietLogger)
+	client := &fakeLLM{}
+	explicit.SetLLMClient(client)
+	if configured.llm == nil {
+		t.Fatal("LLM enhancement not enabled by the configuration")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []*Detector{configured, explicit} {
+		if err := d.Reload(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if configured.llm != nil {
+		t.Error("LLM enhancement still enabled after its configuration was removed")
+	}
+	if explicit.llm != client {
+		t.Error("reload replaced the client set with SetLLMClient")
+	}
+}
+
+func TestWatchReloadsChangedRules(t *testing.T) {
+	dir := writeModel(t, map[string]string{
+		"rules.json":  watchRules("EVAL"),
+		"config.json": `{"confidence": 0.9, "maxFindings": 100}`,
+	})
+	d := NewDetectorWithLogger(dir, quietLogger)
+	setOverrides(t, d)
+	watch(t, d)
+
+	rewriteUntil(t, filepath.Join(dir, "rules.json"), watchRules("EVAL", "EXEC"), func() bool {
+		return slices.Contains(d.RuleIDs(), "EXEC")
+	})
+	checkOverrides(t, d)
+
+	// Rules added in a rules directory created while watching are seen
+	rulesDir := filepath.Join(dir, "rules")
+	if err := os.Mkdir(rulesDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	rewriteUntil(t, filepath.Join(rulesDir, "extra.json"), watchRules("SYSTEM"), func() bool {
+		return slices.Contains(d.RuleIDs(), "SYSTEM")
+	})
+}
+
+func TestWatchKeepsRulesOnBadReload(t *testing.T) {
+	var logs syncBuffer
+	dir := writeModel(t, map[string]string{"rules.json": watchRules("EVAL")})
+	d := NewDetectorWithLogger(dir, logging.New(&logs, logging.LevelWarn))
+	watch(t, d)
+
+	bad := `[{"id": "BROKEN", "name": "b", "pattern": "(", "severity": "high", "category": "c", "description": "d"}]`
+	rewriteUntil(t, filepath.Join(dir, "rules.json"), bad, func() bool {
+		return strings.Contains(logs.String(), "Keeping current rules")
+	})
+	if got := d.RuleIDs(); !slices.Equal(got, []string{"EVAL"}) {
+		t.Errorf("rules %v after a bad reload, want EVAL kept", got)
+	}
+
+	// A later good change is still picked up
+	rewriteUntil(t, filepath.Join(dir, "rules.json"), watchRules("EXEC"), func() bool {
+		return slices.Equal(d.RuleIDs(), []string{"EXEC"})
+	})
+}
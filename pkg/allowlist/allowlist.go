@@ -0,0 +1,107 @@
+package allowlist
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/SofNam/devsecops-ai/pkg/glob"
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// Entry accepts the findings at matching locations
+type Entry struct {
+	// Location is a slash-separated glob relative to the scan target, where
+	// * matches within a path segment and ** matches across segments
+	Location string `json:"location"`
+	// Line restricts the entry to a single line when positive
+	Line int `json:"line,omitempty"`
+	// Rules restricts the entry to these rule IDs when non-empty
+	Rules []string `json:"rules,omitempty"`
+	// Reason documents why the risk is accepted
+	Reason string `json:"reason,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// Allowlist holds accepted-risk locations reviewed in a central file
+type Allowlist struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Load reads an allowlist file and compiles its location globs
+func Load(path string) (*Allowlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read allowlist: %v", err)
+	}
+
+	var list Allowlist
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse allowlist: %v", err)
+	}
+
+	for i := range list.Entries {
+		entry := &list.Entries[i]
+		if entry.Location == "" {
+			return nil, fmt.Errorf("allowlist entry %d: location is required", i+1)
+		}
+		pattern, err := glob.Compile(entry.Location)
+		if err != nil {
+			return nil, fmt.Errorf("allowlist entry %d: %v", i+1, err)
+		}
+		entry.pattern = pattern
+	}
+
+	return &list, nil
+}
+
+// Filter partitions findings into those to report and those accepted by
+// the allowlist. Finding locations are matched relative to root.
+func (a *Allowlist) Filter(findings []models.Finding, root string) (kept, allowlisted []models.Finding) {
+	for _, finding := range findings {
+		if a.allows(finding, relativePath(finding.Location, root)) {
+			allowlisted = append(allowlisted, finding)
+		} else {
+			kept = append(kept, finding)
+		}
+	}
+	return kept, allowlisted
+}
+
+// allows reports whether any entry accepts the finding
+func (a *Allowlist) allows(finding models.Finding, path string) bool {
+	for _, entry := range a.Entries {
+		if entry.Line > 0 && entry.Line != finding.Line {
+			continue
+		}
+		if len(entry.Rules) > 0 && !contains(entry.Rules, finding.RuleID) && !contains(entry.Rules, finding.ID) {
+			continue
+		}
+		if entry.pattern.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// relativePath returns location relative to root in slash form, or the
+// cleaned location when it is outside root
+func relativePath(location, root string) string {
+	if rel, err := filepath.Rel(root, location); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(filepath.Clean(location))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
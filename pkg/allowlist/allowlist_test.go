@@ -0,0 +1,84 @@
+package allowlist
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// load writes an allowlist file and loads it
+func load(t *testing.T, content string) (*Allowlist, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "allowlist.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return Load(path)
+}
+
+// ids returns the IDs of findings
+func ids(findings []models.Finding) []string {
+	var out []string
+	for _, finding := range findings {
+		out = append(out, finding.ID)
+	}
+	return out
+}
+
+func TestFilter(t *testing.T) {
+	list, err := load(t, `{"entries": [
+		{"location": "vendor/**", "reason": "third-party code"},
+		{"location": "src/*_test.go"},
+		{"location": "src/db.go", "line": 12},
+		{"location": "**/config.py", "rules": ["SECRET"]}
+	]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := filepath.Join("project")
+	at := func(id, rule, path string, line int) models.Finding {
+		return models.Finding{ID: id, RuleID: rule, Location: filepath.Join(root, filepath.FromSlash(path)), Line: line}
+	}
+	findings := []models.Finding{
+		at("vendored", "SQLI", "vendor/lib/x.go", 1),
+		at("test-file", "SQLI", "src/db_test.go", 4),
+		at("nested-test", "SQLI", "src/pkg/db_test.go", 4),
+		at("pinned-line", "SQLI", "src/db.go", 12),
+		at("other-line", "SQLI", "src/db.go", 13),
+		at("allowed-rule", "SECRET", "deploy/config.py", 3),
+		at("other-rule", "MD5", "deploy/config.py", 3),
+		{ID: "outside-root", RuleID: "SQLI", Location: "/elsewhere/vendor/x.go", Line: 1},
+	}
+
+	kept, allowlisted := list.Filter(findings, root)
+	if want := []string{"vendored", "test-file", "pinned-line", "allowed-rule"}; !reflect.DeepEqual(ids(allowlisted), want) {
+		t.Errorf("allowlisted %v, want %v", ids(allowlisted), want)
+	}
+	if want := []string{"nested-test", "other-line", "other-rule", "outside-root"}; !reflect.DeepEqual(ids(kept), want) {
+		t.Errorf("kept %v, want %v", ids(kept), want)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"malformed", `{"entries": [`, "failed to parse allowlist"},
+		{"missing location", `{"entries": [{"line": 3}]}`, "entry 1: location is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := load(t, tt.content)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
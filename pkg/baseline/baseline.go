@@ -0,0 +1,102 @@
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+	"github.com/SofNam/devsecops-ai/pkg/reporter"
+)
+
+// Load reads the findings of a previous JSON report. A missing or empty
+// file is treated as an empty baseline.
+func Load(path string) ([]models.Finding, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %v", err)
+	}
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var report reporter.Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline: %v", err)
+	}
+
+	return report.Findings, nil
+}
+
+// Diff partitions findings by fingerprint into those not present in the
+// baseline, baseline findings no longer present, and findings present in both
+func Diff(current, baseline []models.Finding) (added, fixed, unchanged []models.Finding) {
+	known := make(map[string]bool, len(baseline))
+	for _, finding := range baseline {
+		known[fingerprint(finding)] = true
+	}
+
+	seen := make(map[string]bool, len(current))
+	for _, finding := range current {
+		fp := fingerprint(finding)
+		seen[fp] = true
+
+		if known[fp] {
+			unchanged = append(unchanged, finding)
+		} else {
+			added = append(added, finding)
+		}
+	}
+
+	for _, finding := range baseline {
+		if !seen[fingerprint(finding)] {
+			fixed = append(fixed, finding)
+		}
+	}
+
+	return added, fixed, unchanged
+}
+
+// StampFirstSeen sets the FirstSeen time of each finding to that of the
+// baseline finding with the same fingerprint, or to now for new findings.
+// Baseline findings without a FirstSeen time, including those written as
+// the zero time by earlier versions, contribute their Timestamp instead.
+func StampFirstSeen(findings, baseline []models.Finding, now time.Time) {
+	firstSeen := make(map[string]time.Time, len(baseline))
+	for _, finding := range baseline {
+		seen := finding.Timestamp
+		if finding.FirstSeen != nil && !finding.FirstSeen.IsZero() {
+			seen = *finding.FirstSeen
+		}
+		if seen.IsZero() {
+			continue
+		}
+
+		fp := fingerprint(finding)
+		if earlier, ok := firstSeen[fp]; !ok || seen.Before(earlier) {
+			firstSeen[fp] = seen
+		}
+	}
+
+	for i := range findings {
+		seen, ok := firstSeen[fingerprint(findings[i])]
+		if !ok {
+			seen = now
+		}
+		findings[i].FirstSeen = &seen
+	}
+}
+
+// fingerprint returns the finding fingerprint, computing it for findings
+// from reports that predate the field
+func fingerprint(f models.Finding) string {
+	if f.Fingerprint != "" {
+		return f.Fingerprint
+	}
+	return models.ComputeFingerprint(f)
+}
@@ -0,0 +1,134 @@
+package baseline
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// finding returns a fingerprinted finding for snippet
+func finding(id, snippet string) models.Finding {
+	f := models.Finding{ID: id, RuleID: id, Category: "C", Location: "app.go", CodeSnippet: snippet}
+	f.Fingerprint = models.ComputeFingerprint(f)
+	return f
+}
+
+// ids returns the IDs of findings
+func ids(findings []models.Finding) []string {
+	var out []string
+	for _, f := range findings {
+		out = append(out, f.ID)
+	}
+	return out
+}
+
+func TestDiffPartitions(t *testing.T) {
+	kept := finding("KEPT", "a")
+	gone := finding("GONE", "b")
+	fresh := finding("NEW", "c")
+
+	added, fixed, unchanged := Diff([]models.Finding{kept, fresh}, []models.Finding{kept, gone})
+
+	if got := ids(added); len(got) != 1 || got[0] != "NEW" {
+		t.Errorf("added = %v, want [NEW]", got)
+	}
+	if got := ids(fixed); len(got) != 1 || got[0] != "GONE" {
+		t.Errorf("fixed = %v, want [GONE]", got)
+	}
+	if got := ids(unchanged); len(got) != 1 || got[0] != "KEPT" {
+		t.Errorf("unchanged = %v, want [KEPT]", got)
+	}
+}
+
+func TestDiffMatchesFindingsWithoutFingerprint(t *testing.T) {
+	old := finding("R", "a")
+	old.Fingerprint = ""
+
+	added, fixed, unchanged := Diff([]models.Finding{finding("R", "a")}, []models.Finding{old})
+	if len(added) != 0 || len(fixed) != 0 || len(unchanged) != 1 {
+		t.Errorf("got %d added, %d fixed, %d unchanged, want 0, 0, 1", len(added), len(fixed), len(unchanged))
+	}
+}
+
+func TestEmptyOrMissingBaseline(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.json")
+	if err := os.WriteFile(empty, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	current := []models.Finding{finding("A", "a"), finding("B", "b")}
+	for _, path := range []string{empty, filepath.Join(dir, "missing.json")} {
+		known, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%s): %v", filepath.Base(path), err)
+		}
+
+		added, fixed, unchanged := Diff(current, known)
+		if len(added) != 2 || len(fixed) != 0 || len(unchanged) != 0 {
+			t.Errorf("%s: got %d added, %d fixed, %d unchanged, want 2, 0, 0",
+				filepath.Base(path), len(added), len(fixed), len(unchanged))
+		}
+	}
+}
+
+func TestLoadRejectsMalformedBaseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load accepted a malformed baseline")
+	}
+}
+
+func TestStampFirstSeen(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	january := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	stamped := finding("STAMPED", "a")
+	stamped.FirstSeen = &march
+	stamped.Timestamp = now
+	legacy := finding("LEGACY", "b")
+	legacy.Timestamp = january
+	zero := finding("ZERO", "c")
+	zero.FirstSeen = &time.Time{}
+	zero.Timestamp = march
+
+	current := []models.Finding{finding("STAMPED", "a"), finding("LEGACY", "b"), finding("ZERO", "c"), finding("NEW", "d")}
+	StampFirstSeen(current, []models.Finding{stamped, legacy, zero}, now)
+
+	want := map[string]time.Time{"STAMPED": march, "LEGACY": january, "ZERO": march, "NEW": now}
+	for _, f := range current {
+		if f.FirstSeen == nil || !f.FirstSeen.Equal(want[f.ID]) {
+			t.Errorf("%s first seen %v, want %v", f.ID, f.FirstSeen, want[f.ID])
+		}
+	}
+}
+
+func TestFirstSeenOmittedUntilStamped(t *testing.T) {
+	f := finding("R", "a")
+	data, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "firstSeen") {
+		t.Errorf("unstamped finding serialized as %s", data)
+	}
+
+	findings := []models.Finding{f}
+	StampFirstSeen(findings, nil, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	data, err = json.Marshal(findings[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"firstSeen":"2024-06-01T00:00:00Z"`) {
+		t.Errorf("stamped finding serialized as %s", data)
+	}
+}
@@ -0,0 +1,107 @@
+package blame
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// uncommitted is the commit git blame reports for lines not yet committed
+const uncommitted = "0000000000000000000000000000000000000000"
+
+// lineInfo is the blame result for a single line
+type lineInfo struct {
+	author string
+	commit string
+}
+
+// Annotate sets the Author and Commit of findings from git blame of their
+// file and line. Findings outside a git repository, without a line, or on
+// uncommitted lines are left unchanged. An error is returned only when git
+// is not installed.
+func Annotate(ctx context.Context, findings []models.Finding) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("git not available: %v", err)
+	}
+
+	// Several findings often share a line, blame each line once
+	cache := make(map[string]*lineInfo)
+
+	for i := range findings {
+		finding := &findings[i]
+		if finding.Line <= 0 || finding.Location == "" {
+			continue
+		}
+
+		key := fmt.Sprintf("%s:%d", finding.Location, finding.Line)
+		info, ok := cache[key]
+		if !ok {
+			info = blameLine(ctx, finding.Location, finding.Line)
+			cache[key] = info
+		}
+		if info == nil {
+			continue
+		}
+
+		finding.Author = info.author
+		finding.Commit = info.commit
+	}
+
+	return nil
+}
+
+// blameLine runs git blame for one line, returning nil when the file is not
+// tracked or the line is uncommitted
+func blameLine(ctx context.Context, path string, line int) *lineInfo {
+	cmd := exec.CommandContext(ctx, "git", "-C", filepath.Dir(path),
+		"blame", "--porcelain", "-L", fmt.Sprintf("%d,%d", line, line), "--", filepath.Base(path))
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	return parsePorcelain(out)
+}
+
+// parsePorcelain extracts the commit and author from porcelain blame output
+func parsePorcelain(out []byte) *lineInfo {
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	if !scanner.Scan() {
+		return nil
+	}
+
+	header := strings.Fields(scanner.Text())
+	if len(header) == 0 || header[0] == uncommitted {
+		return nil
+	}
+	info := &lineInfo{commit: header[0]}
+
+	var mail string
+	for scanner.Scan() {
+		text := scanner.Text()
+		switch {
+		case strings.HasPrefix(text, "author "):
+			info.author = strings.TrimPrefix(text, "author ")
+		case strings.HasPrefix(text, "author-mail "):
+			mail = strings.TrimPrefix(text, "author-mail ")
+		case strings.HasPrefix(text, "\t"):
+			// The line content ends the entry
+			if mail != "" {
+				info.author += " " + mail
+			}
+			return info
+		}
+	}
+
+	if mail != "" {
+		info.author += " " + mail
+	}
+	return info
+}
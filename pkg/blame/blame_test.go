@@ -0,0 +1,72 @@
+package blame
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// git runs a git command in dir, failing the test on error
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Ada Lovelace", "GIT_AUTHOR_EMAIL=ada@example.com",
+		"GIT_COMMITTER_NAME=Ada Lovelace", "GIT_COMMITTER_EMAIL=ada@example.com",
+		"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestAnnotate(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	repo := t.TempDir()
+	git(t, repo, "init", "-q")
+	path := filepath.Join(repo, "app.py")
+	if err := os.WriteFile(path, []byte("password = 'x'\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, repo, "add", "app.py")
+	git(t, repo, "commit", "-q", "-m", "add app")
+	commit := git(t, repo, "rev-parse", "HEAD")
+
+	// A second, uncommitted line
+	if err := os.WriteFile(path, []byte("password = 'x'\neval(data)\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	untracked := filepath.Join(t.TempDir(), "other.py")
+	if err := os.WriteFile(untracked, []byte("eval(data)\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	findings := []models.Finding{
+		{ID: "committed", Location: path, Line: 1},
+		{ID: "uncommitted", Location: path, Line: 2},
+		{ID: "no-line", Location: path},
+		{ID: "outside-repo", Location: untracked, Line: 1},
+	}
+	if err := Annotate(context.Background(), findings); err != nil {
+		t.Fatalf("Annotate: %v", err)
+	}
+
+	if got := findings[0]; got.Author != "Ada Lovelace <ada@example.com>" || got.Commit != commit {
+		t.Errorf("committed line blamed on %q at %q, want Ada at %s", got.Author, got.Commit, commit)
+	}
+	for _, finding := range findings[1:] {
+		if finding.Author != "" || finding.Commit != "" {
+			t.Errorf("%s blamed on %q at %q, want no attribution", finding.ID, finding.Author, finding.Commit)
+		}
+	}
+}
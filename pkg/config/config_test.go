@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes a config file named name and returns its path
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	want := map[string]string{
+		"path":       "src",
+		"fail-on":    "high",
+		"workers":    "4",
+		"relative":   "true",
+		"categories": "Secrets,Injection",
+		"output":     "",
+	}
+	tests := []struct {
+		name, file, content string
+	}{
+		{"yaml", "scanner.yaml", `
+path: src
+fail-on: high
+workers: 4
+relative: true
+categories: [Secrets, Injection]
+output:
+`},
+		{"json", "scanner.json", `{
+	"path": "src",
+	"fail-on": "high",
+	"workers": 4,
+	"relative": true,
+	"categories": ["Secrets", "Injection"],
+	"output": null
+}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(writeConfig(t, tt.file, tt.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Load = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name, file, content, want string
+	}{
+		{"malformed json", "scanner.json", `{"path": `, "parsing config file"},
+		{"malformed yaml", "scanner.yaml", "path: [src\n", "parsing config file"},
+		{"nested map", "scanner.yaml", "path:\n  dir: src\n", "option path: unsupported value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, tt.file, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load error %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Load of a missing file succeeded")
+	}
+}
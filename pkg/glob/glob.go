@@ -0,0 +1,61 @@
+package glob
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Compile converts a slash-separated path glob into an anchored regular
+// expression. * and ? match within a path segment, ** matches across
+// segments and "**/" also matches no directories at all.
+func Compile(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// CompileAll compiles each pattern
+func CompileAll(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// MatchAny reports whether path matches any of the compiled globs
+func MatchAny(globs []*regexp.Regexp, path string) bool {
+	for _, re := range globs {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package glob
+
+import "testing"
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "pkg/main.go", false},
+		{"pkg/*.go", "pkg/main.go", true},
+		{"pkg/*.go", "pkg/sub/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "pkg/sub/main.go", true},
+		{"vendor/**", "vendor/a/b.go", true},
+		{"vendor/**", "src/vendor/a.go", false},
+		{"**/testdata/**", "pkg/scanner/testdata/x.py", true},
+		{"file?.py", "file1.py", true},
+		{"file?.py", "file10.py", false},
+		{"file?.py", "dir/.py", false},
+		{"a.b", "axb", false},
+		{"[x].py", "[x].py", true},
+	}
+	for _, tt := range tests {
+		re, err := Compile(tt.pattern)
+		if err != nil {
+			t.Fatalf("Compile(%q): %v", tt.pattern, err)
+		}
+		if got := re.MatchString(tt.path); got != tt.want {
+			t.Errorf("%q matching %q = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestMatchAny(t *testing.T) {
+	globs, err := CompileAll([]string{"*.md", "docs/**"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]bool{
+		"README.md":     true,
+		"docs/guide.go": true,
+		"src/main.go":   false,
+	} {
+		if got := MatchAny(globs, path); got != want {
+			t.Errorf("MatchAny(%q) = %v, want %v", path, got, want)
+		}
+	}
+	if MatchAny(nil, "anything") {
+		t.Error("no globs matched a path")
+	}
+}
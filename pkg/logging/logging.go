@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+// Level is the minimum priority of messages a logger emits
+type Level int
+
+const (
+	LevelError Level = iota
+	LevelWarn
+	LevelInfo
+	LevelDebug
+)
+
+// levelNames maps command line names to levels
+var levelNames = map[string]Level{
+	"error": LevelError,
+	"warn":  LevelWarn,
+	"info":  LevelInfo,
+	"debug": LevelDebug,
+}
+
+// ParseLevel parses a level name (error/warn/info/debug), ignoring case
+func ParseLevel(name string) (Level, error) {
+	level, ok := levelNames[strings.ToLower(name)]
+	if !ok {
+		return LevelInfo, fmt.Errorf("unknown log level: %s", name)
+	}
+	return level, nil
+}
+
+// Logger receives diagnostics from the scanner, detector and classifier
+type Logger interface {
+	Errorf(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Debugf(format string, args ...interface{})
+}
+
+// levelLogger writes messages at or above its level to a standard logger
+type levelLogger struct {
+	out   *log.Logger
+	level Level
+}
+
+// New creates a logger writing messages at or above level to w
+func New(w io.Writer, level Level) Logger {
+	return &levelLogger{
+		out:   log.New(w, "", log.LstdFlags),
+		level: level,
+	}
+}
+
+// Default returns a logger writing info and above to stderr, matching the
+// standard log package output
+func Default() Logger {
+	return New(os.Stderr, LevelInfo)
+}
+
+func (l *levelLogger) Errorf(format string, args ...interface{}) {
+	l.logf(LevelError, "Error: ", format, args)
+}
+
+func (l *levelLogger) Warnf(format string, args ...interface{}) {
+	l.logf(LevelWarn, "Warning: ", format, args)
+}
+
+func (l *levelLogger) Infof(format string, args ...interface{}) {
+	l.logf(LevelInfo, "Info: ", format, args)
+}
+
+func (l *levelLogger) Debugf(format string, args ...interface{}) {
+	l.logf(LevelDebug, "Debug: ", format, args)
+}
+
+// logf writes a prefixed message if level is enabled
+func (l *levelLogger) logf(level Level, prefix, format string, args []interface{}) {
+	if level > l.level {
+		return
+	}
+	l.out.Print(prefix + fmt.Sprintf(format, args...))
+}
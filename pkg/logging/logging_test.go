@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLevelsSuppressed(t *testing.T) {
+	tests := []struct {
+		level Level
+		want  []string
+	}{
+		{LevelError, []string{"Error: e"}},
+		{LevelWarn, []string{"Error: e", "Warning: w"}},
+		{LevelInfo, []string{"Error: e", "Warning: w", "Info: i"}},
+		{LevelDebug, []string{"Error: e", "Warning: w", "Info: i", "Debug: d"}},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		logger := New(&buf, tt.level)
+		logger.Errorf("e")
+		logger.Warnf("w")
+		logger.Infof("i")
+		logger.Debugf("d")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != len(tt.want) {
+			t.Errorf("level %d logged %d lines, want %d:\n%s", tt.level, len(lines), len(tt.want), buf.String())
+			continue
+		}
+		for i, want := range tt.want {
+			if !strings.HasSuffix(lines[i], want) {
+				t.Errorf("level %d line %d = %q, want suffix %q", tt.level, i, lines[i], want)
+			}
+		}
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, want := range map[string]Level{"error": LevelError, "WARN": LevelWarn, "Info": LevelInfo, "debug": LevelDebug} {
+		if got, err := ParseLevel(name); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v, want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("ParseLevel accepted an unknown level")
+	}
+}
@@ -1,6 +1,11 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -17,14 +22,129 @@ const (
 
 // Finding represents a security finding or vulnerability
 type Finding struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Severity    Severity  `json:"severity"`
-	Category    string    `json:"category"`
-	Location    string    `json:"location"`
-	CodeSnippet string    `json:"codeSnippet,omitempty"`
+	ID          string   `json:"id"`
+	RuleID      string   `json:"ruleId,omitempty"`
+	Fingerprint string   `json:"fingerprint,omitempty"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Severity    Severity `json:"severity"`
+	Category    string   `json:"category"`
+	Location    string   `json:"location"`
+	Line        int      `json:"line,omitempty"`
+	Column      int      `json:"column,omitempty"`
+	CodeSnippet string   `json:"codeSnippet,omitempty"`
+	// Language names the language of the snippet for syntax highlighting
+	Language    string    `json:"language,omitempty"`
 	Timestamp   time.Time `json:"timestamp"`
 	Remediation string    `json:"remediation,omitempty"`
 	Confidence  float64   `json:"confidence"`
+	CWE         string    `json:"cwe,omitempty"`
+	OWASP       string    `json:"owasp,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+
+	// Metadata holds arbitrary key/value data from the rule that produced
+	// the finding, such as references or compliance mappings
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	Labels []CategoryScore `json:"labels,omitempty"`
+
+	// Context holds the lines surrounding the match, including it
+	Context []ContextLine `json:"context,omitempty"`
+
+	// Author and Commit identify the last change to the line, from git blame
+	Author string `json:"author,omitempty"`
+	Commit string `json:"commit,omitempty"`
+
+	// FirstSeen is when the finding was first reported, carried forward
+	// from the baseline by fingerprint; nil until stamped
+	FirstSeen *time.Time `json:"firstSeen,omitempty"`
+}
+
+// ContextLine is a source line near a finding
+type ContextLine struct {
+	Line  int    `json:"line"`
+	Text  string `json:"text"`
+	Match bool   `json:"match,omitempty"`
+}
+
+// CategoryScore is a candidate category for a finding with its confidence
+type CategoryScore struct {
+	Category string  `json:"category"`
+	Score    float64 `json:"score"`
+}
+
+// Dependency represents a third-party component declared in a manifest
+type Dependency struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Type     string `json:"type"`
+	Indirect bool   `json:"indirect,omitempty"`
+	Location string `json:"location"`
+	Line     int    `json:"line,omitempty"`
+}
+
+// lineSuffix matches a trailing ":line" or ":line:column" position
+var lineSuffix = regexp.MustCompile(`(:\d+)+$`)
+
+// ComputeFingerprint returns a deterministic identifier for a finding that
+// survives line shifts and whitespace-only reformatting of the snippet
+func ComputeFingerprint(f Finding) string {
+	location := filepath.ToSlash(filepath.Clean(lineSuffix.ReplaceAllString(f.Location, "")))
+	snippet := strings.Join(strings.Fields(f.CodeSnippet), " ")
+
+	h := sha256.New()
+	for _, part := range []string{f.Category, snippet, location, f.RuleID} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// FingerprintRelative returns the fingerprint of a finding with its
+// location taken relative to root, so scans of the same tree through
+// different paths, such as "src" and "/work/src", agree
+func FingerprintRelative(f Finding, root string) string {
+	f.Location = RelativeLocation(f.Location, root)
+	return ComputeFingerprint(f)
+}
+
+// SetFingerprints recomputes the fingerprints of findings with locations
+// taken relative to root
+func SetFingerprints(findings []Finding, root string) {
+	for i := range findings {
+		findings[i].Fingerprint = FingerprintRelative(findings[i], root)
+	}
+}
+
+// RelativeLocation returns location relative to root using forward slashes.
+// Locations outside root are reduced to their base name so that absolute
+// paths, such as those of temporary directories, never leak into reports.
+func RelativeLocation(location, root string) string {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return filepath.Base(location)
+	}
+	absLocation, err := filepath.Abs(location)
+	if err != nil {
+		return filepath.Base(location)
+	}
+
+	rel, err := filepath.Rel(absRoot, absLocation)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.Base(location)
+	}
+	if rel == "." {
+		return filepath.Base(absLocation)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// RelativeLocations rewrites finding locations relative to root and
+// recomputes their fingerprints
+func RelativeLocations(findings []Finding, root string) {
+	for i := range findings {
+		findings[i].Location = RelativeLocation(findings[i].Location, root)
+		findings[i].Fingerprint = ComputeFingerprint(findings[i])
+	}
 }
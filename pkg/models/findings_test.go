@@ -0,0 +1,74 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFingerprintStability(t *testing.T) {
+	base := Finding{
+		RuleID:      "PASSWORD",
+		Category:    "Secrets",
+		Location:    "src/app.py",
+		Line:        10,
+		CodeSnippet: `password = "hunter2"`,
+	}
+	fingerprint := ComputeFingerprint(base)
+
+	shifted := base
+	shifted.Line = 42
+	shifted.Location = "src/app.py:42:3"
+	if got := ComputeFingerprint(shifted); got != fingerprint {
+		t.Error("fingerprint changed when the finding moved to another line")
+	}
+
+	reformatted := base
+	reformatted.CodeSnippet = "password   =\t\"hunter2\""
+	if got := ComputeFingerprint(reformatted); got != fingerprint {
+		t.Error("fingerprint changed with whitespace-only reformatting")
+	}
+
+	changed := base
+	changed.CodeSnippet = `password = "swordfish"`
+	if got := ComputeFingerprint(changed); got == fingerprint {
+		t.Error("fingerprint unchanged for different code")
+	}
+
+	moved := base
+	moved.Location = "src/other.py"
+	if got := ComputeFingerprint(moved); got == fingerprint {
+		t.Error("fingerprint unchanged for a different file")
+	}
+}
+
+func TestFingerprintRelativeToTarget(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	finding := Finding{RuleID: "R", Category: "C", CodeSnippet: "x"}
+	fingerprint := func(location, root string) string {
+		f := finding
+		f.Location = location
+		return FingerprintRelative(f, root)
+	}
+
+	want := fingerprint(filepath.Join("src", "pkg", "a.go"), "src")
+	targets := []struct{ location, root string }{
+		{filepath.Join(dir, "src", "pkg", "a.go"), filepath.Join(dir, "src")},
+		{filepath.Join(".", "src", "pkg", "a.go"), "./src"},
+		{filepath.Join("pkg", "a.go"), "."},
+	}
+	for _, tt := range targets {
+		if got := fingerprint(tt.location, tt.root); got != want {
+			t.Errorf("FingerprintRelative(%q, %q) differs from scanning src", tt.location, tt.root)
+		}
+	}
+}
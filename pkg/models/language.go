@@ -0,0 +1,34 @@
+package models
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// languages maps file extensions to the language names understood by
+// syntax highlighters
+var languages = map[string]string{
+	".go":   "go",
+	".py":   "python",
+	".js":   "javascript",
+	".ts":   "typescript",
+	".java": "java",
+	".rb":   "ruby",
+	".php":  "php",
+	".c":    "c",
+	".cpp":  "cpp",
+	".cs":   "csharp",
+	".rs":   "rust",
+	".sh":   "bash",
+	".yaml": "yaml",
+	".yml":  "yaml",
+	".json": "json",
+	".xml":  "xml",
+	".sql":  "sql",
+}
+
+// Language infers the language of a file from its extension, returning ""
+// for unknown extensions
+func Language(path string) string {
+	return languages[strings.ToLower(filepath.Ext(path))]
+}
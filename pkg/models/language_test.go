@@ -0,0 +1,25 @@
+package models
+
+import "testing"
+
+func TestLanguage(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"main.go", "go"},
+		{"src/app.py", "python"},
+		{"web/App.TS", "typescript"},
+		{"deploy.yml", "yaml"},
+		{"deploy.yaml", "yaml"},
+		{"bundle.zip!lib/util.rb", "ruby"},
+		{"notes.txt", ""},
+		{"Makefile", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Language(tt.path); got != tt.want {
+			t.Errorf("Language(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
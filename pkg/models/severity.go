@@ -0,0 +1,194 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SeverityLevel describes a severity: its rank, lower is more severe, and
+// the color used for it in reports
+type SeverityLevel struct {
+	Severity Severity `json:"name"`
+	Rank     int      `json:"rank"`
+	Color    string   `json:"color,omitempty"`
+}
+
+// severityMu guards the severity table and aliases, which may be extended
+// at runtime by RegisterSeverity
+var severityMu sync.RWMutex
+
+// severityLevels is the severity table, ordered most severe first
+var severityLevels = []SeverityLevel{
+	{Severity: SeverityCritical, Rank: 0, Color: "#dc3545"},
+	{Severity: SeverityHigh, Rank: 1, Color: "#fd7e14"},
+	{Severity: SeverityMedium, Rank: 2, Color: "#ffc107"},
+	{Severity: SeverityLow, Rank: 3, Color: "#28a745"},
+	{Severity: SeverityInfo, Rank: 4, Color: "#17a2b8"},
+}
+
+// level returns the table entry for s and its position in the table
+func (s Severity) level() (SeverityLevel, int, bool) {
+	for i, level := range severityLevels {
+		if level.Severity == s {
+			return level, i, true
+		}
+	}
+	return SeverityLevel{}, -1, false
+}
+
+// Rank returns the priority of the severity, lower values are more severe.
+// Unknown severities rank below the least severe level.
+func (s Severity) Rank() int {
+	severityMu.RLock()
+	defer severityMu.RUnlock()
+	if level, _, ok := s.level(); ok {
+		return level.Rank
+	}
+	return severityLevels[len(severityLevels)-1].Rank + 1
+}
+
+// Color returns the report color of the severity, or "" if it is unknown
+func (s Severity) Color() string {
+	severityMu.RLock()
+	defer severityMu.RUnlock()
+	level, _, _ := s.level()
+	return level.Color
+}
+
+// Valid reports whether s is a built-in or registered severity
+func (s Severity) Valid() bool {
+	severityMu.RLock()
+	defer severityMu.RUnlock()
+	_, _, ok := s.level()
+	return ok
+}
+
+// AtLeast reports whether s is at least as severe as threshold
+func (s Severity) AtLeast(threshold Severity) bool {
+	return s.Rank() <= threshold.Rank()
+}
+
+// Severities returns the known severities, most severe first
+func Severities() []Severity {
+	severityMu.RLock()
+	defer severityMu.RUnlock()
+	severities := make([]Severity, len(severityLevels))
+	for i, level := range severityLevels {
+		severities[i] = level.Severity
+	}
+	return severities
+}
+
+// SeverityLevels returns the severity table, most severe first
+func SeverityLevels() []SeverityLevel {
+	severityMu.RLock()
+	defer severityMu.RUnlock()
+	return append([]SeverityLevel(nil), severityLevels...)
+}
+
+// RegisterSeverity adds a severity level, or updates the rank and color of
+// an existing one. Names are upper-cased and become parseable by
+// ParseSeverity; the rank must not be taken by another severity.
+func RegisterSeverity(level SeverityLevel) error {
+	level.Severity = Severity(strings.ToUpper(strings.TrimSpace(string(level.Severity))))
+	if level.Severity == "" {
+		return fmt.Errorf("severity name is required")
+	}
+
+	severityMu.Lock()
+	defer severityMu.Unlock()
+
+	for _, existing := range severityLevels {
+		if existing.Rank == level.Rank && existing.Severity != level.Severity {
+			return fmt.Errorf("severity %s: rank %d is already used by %s", level.Severity, level.Rank, existing.Severity)
+		}
+	}
+
+	if existing, i, ok := level.Severity.level(); ok {
+		if level.Color == "" {
+			level.Color = existing.Color
+		}
+		severityLevels = append(severityLevels[:i:i], severityLevels[i+1:]...)
+	}
+	levels := append(severityLevels, level)
+	sort.SliceStable(levels, func(i, j int) bool {
+		return levels[i].Rank < levels[j].Rank
+	})
+	severityLevels = levels
+
+	if _, ok := severityAliases[strings.ToLower(string(level.Severity))]; !ok {
+		severityAliases[strings.ToLower(string(level.Severity))] = level.Severity
+	}
+	return nil
+}
+
+// severityAliases maps lowercase names accepted by ParseSeverity
+var severityAliases = map[string]Severity{
+	"critical":      SeverityCritical,
+	"crit":          SeverityCritical,
+	"high":          SeverityHigh,
+	"medium":        SeverityMedium,
+	"med":           SeverityMedium,
+	"moderate":      SeverityMedium,
+	"warn":          SeverityMedium,
+	"warning":       SeverityMedium,
+	"low":           SeverityLow,
+	"info":          SeverityInfo,
+	"informational": SeverityInfo,
+}
+
+// ParseSeverity parses a severity case-insensitively, accepting common
+// aliases such as "crit" and "warn"
+func ParseSeverity(s string) (Severity, error) {
+	severityMu.RLock()
+	defer severityMu.RUnlock()
+	if severity, ok := severityAliases[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return severity, nil
+	}
+	return "", fmt.Errorf("unknown severity %q", s)
+}
+
+// Canonical returns the canonical form of s given by ParseSeverity, or s
+// unchanged when it is unknown
+func (s Severity) Canonical() Severity {
+	if severity, err := ParseSeverity(string(s)); err == nil {
+		return severity
+	}
+	return s
+}
+
+// NormalizeSeverities returns a copy of findings with each severity in
+// canonical form. Unknown severities are kept as is.
+func NormalizeSeverities(findings []Finding) []Finding {
+	if findings == nil {
+		return nil
+	}
+	normalized := make([]Finding, len(findings))
+	for i, finding := range findings {
+		finding.Severity = finding.Severity.Canonical()
+		normalized[i] = finding
+	}
+	return normalized
+}
+
+// Adjust returns the severity raised by levels, or lowered when levels is
+// negative, one step per level in the severity table, clamped to the most
+// and least severe levels. Unknown severities are returned unchanged.
+func (s Severity) Adjust(levels int) Severity {
+	severityMu.RLock()
+	defer severityMu.RUnlock()
+	_, position, ok := s.level()
+	if !ok {
+		return s
+	}
+	position -= levels
+	if position < 0 {
+		position = 0
+	}
+	if last := len(severityLevels) - 1; position > last {
+		position = last
+	}
+	return severityLevels[position].Severity
+}
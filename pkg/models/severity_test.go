@@ -0,0 +1,116 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Severity
+		wantErr bool
+	}{
+		{"critical", SeverityCritical, false},
+		{"CRITICAL", SeverityCritical, false},
+		{"Crit", SeverityCritical, false},
+		{"high", SeverityHigh, false},
+		{"  High\t", SeverityHigh, false},
+		{"medium", SeverityMedium, false},
+		{"med", SeverityMedium, false},
+		{"moderate", SeverityMedium, false},
+		{"warn", SeverityMedium, false},
+		{"WARNING", SeverityMedium, false},
+		{"low", SeverityLow, false},
+		{"info", SeverityInfo, false},
+		{"Informational", SeverityInfo, false},
+		{"", "", true},
+		{"severe", "", true},
+		{"hi", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSeverity(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSeverity(%q) error %v, want error %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSeverity(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// restoreSeverities puts back the severity table and aliases when the test
+// ends, undoing any severities it registers
+func restoreSeverities(t *testing.T) {
+	t.Helper()
+	severityMu.RLock()
+	levels := append([]SeverityLevel(nil), severityLevels...)
+	aliases := make(map[string]Severity, len(severityAliases))
+	for name, severity := range severityAliases {
+		aliases[name] = severity
+	}
+	severityMu.RUnlock()
+
+	t.Cleanup(func() {
+		severityMu.Lock()
+		defer severityMu.Unlock()
+		severityLevels, severityAliases = levels, aliases
+	})
+}
+
+func TestRegisterSeverity(t *testing.T) {
+	restoreSeverities(t)
+	blocker := Severity("BLOCKER")
+	if err := RegisterSeverity(SeverityLevel{Severity: "Blocker", Rank: -1, Color: "#6f42c1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := Severities()
+	want := []Severity{blocker, SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityInfo}
+	if !slices.Equal(got, want) {
+		t.Errorf("Severities() = %v, want %v", got, want)
+	}
+	if severity, err := ParseSeverity(" blocker "); err != nil || severity != blocker {
+		t.Errorf("ParseSeverity(blocker) = %q, %v, want %q", severity, err, blocker)
+	}
+	if !blocker.Valid() || blocker.Color() != "#6f42c1" {
+		t.Errorf("BLOCKER valid %v color %q, want registered", blocker.Valid(), blocker.Color())
+	}
+	if !blocker.AtLeast(SeverityCritical) || SeverityCritical.AtLeast(blocker) {
+		t.Error("BLOCKER does not rank above CRITICAL")
+	}
+	if got := SeverityCritical.Adjust(1); got != blocker {
+		t.Errorf("CRITICAL raised one level = %q, want %q", got, blocker)
+	}
+	if got := SeverityInfo.Adjust(10); got != blocker {
+		t.Errorf("INFO raised ten levels = %q, want %q", got, blocker)
+	}
+
+	// Re-registering moves a level and keeps its color unless one is given
+	if err := RegisterSeverity(SeverityLevel{Severity: blocker, Rank: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if got := Severities(); got[len(got)-1] != blocker || blocker.Color() != "#6f42c1" {
+		t.Errorf("after moving BLOCKER, severities %v color %q", got, blocker.Color())
+	}
+	if got := Severity("OTHER").Rank(); got != 11 {
+		t.Errorf("unknown severity rank %d, want 11, below every level", got)
+	}
+}
+
+func TestRegisterSeverityErrors(t *testing.T) {
+	restoreSeverities(t)
+	for _, level := range []SeverityLevel{
+		{Severity: " ", Rank: 9},
+		{Severity: "BLOCKER", Rank: 0},
+	} {
+		if err := RegisterSeverity(level); err == nil {
+			t.Errorf("RegisterSeverity(%+v) succeeded, want an error", level)
+		}
+	}
+	if got := Severities(); len(got) != 5 {
+		t.Errorf("severities %v after failed registrations, want the built-in five", got)
+	}
+}
@@ -0,0 +1,105 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultTimeout bounds each webhook request
+	DefaultTimeout = 10 * time.Second
+	// DefaultRetries is the number of retries after a failed request
+	DefaultRetries = 3
+	// DefaultBackoff is the delay before the first retry, doubled for each
+	// further retry
+	DefaultBackoff = 500 * time.Millisecond
+)
+
+// Notifier posts reports to webhooks
+type Notifier struct {
+	Client  *http.Client
+	Timeout time.Duration
+	Retries int
+	Backoff time.Duration
+}
+
+// New creates a notifier whose requests time out after timeout, using the
+// default retry policy
+func New(timeout time.Duration) *Notifier {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Notifier{
+		Client:  http.DefaultClient,
+		Timeout: timeout,
+		Retries: DefaultRetries,
+		Backoff: DefaultBackoff,
+	}
+}
+
+// Notify posts report as JSON to url with the default settings
+func Notify(ctx context.Context, url string, report interface{}) error {
+	return New(DefaultTimeout).Notify(ctx, url, report)
+}
+
+// Notify posts report as JSON to url. Network errors and 5xx responses are
+// retried with exponential backoff; any other non-2xx response fails
+// immediately.
+func (n *Notifier) Notify(ctx context.Context, url string, report interface{}) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("encoding report: %v", err)
+	}
+
+	delay := n.Backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := n.post(ctx, url, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= n.Retries {
+			return err
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return fmt.Errorf("posting report: %v", ctx.Err())
+		}
+		delay *= 2
+	}
+}
+
+// post sends one request, reporting whether a failure may be retried
+func (n *Notifier) post(ctx context.Context, url string, body []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, n.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("posting report: %v", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	return resp.StatusCode >= 500, fmt.Errorf("posting report: unexpected status %s", resp.Status)
+}
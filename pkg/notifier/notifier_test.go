@@ -0,0 +1,112 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testNotifier returns a notifier that retries quickly
+func testNotifier() *Notifier {
+	n := New(time.Second)
+	n.Backoff = time.Millisecond
+	return n
+}
+
+// statusServer responds with the given statuses in turn, repeating the
+// last one, and counts requests
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(requests.Add(1)) - 1
+		if i >= len(statuses) {
+			i = len(statuses) - 1
+		}
+		w.WriteHeader(statuses[i])
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestNotifyPostsJSON(t *testing.T) {
+	var got map[string]string
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &got)
+	}))
+	defer srv.Close()
+
+	if err := testNotifier().Notify(context.Background(), srv.URL, map[string]string{"scanId": "SCAN-1"}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if contentType != "application/json" || got["scanId"] != "SCAN-1" {
+		t.Errorf("posted %v as %q, want the report as JSON", got, contentType)
+	}
+}
+
+func TestNotifyRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		wantErr  string
+		requests int32
+	}{
+		{"success", []int{http.StatusNoContent}, "", 1},
+		{"retried server error", []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK}, "", 3},
+		{"persistent server error", []int{http.StatusInternalServerError}, "500 Internal Server Error", 1 + DefaultRetries},
+		{"client error is permanent", []int{http.StatusNotFound, http.StatusOK}, "404 Not Found", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, requests := statusServer(t, tt.statuses...)
+			err := testNotifier().Notify(context.Background(), srv.URL, map[string]int{})
+
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Notify: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Notify error %v, want %q", err, tt.wantErr)
+			}
+			if got := requests.Load(); got != tt.requests {
+				t.Errorf("%d requests, want %d", got, tt.requests)
+			}
+		})
+	}
+}
+
+func TestNotifyNetworkErrorRetried(t *testing.T) {
+	srv, _ := statusServer(t, http.StatusOK)
+	url := srv.URL
+	srv.Close()
+
+	n := testNotifier()
+	n.Retries = 2
+	if err := n.Notify(context.Background(), url, map[string]int{}); err == nil || !strings.Contains(err.Error(), "posting report") {
+		t.Errorf("Notify error %v, want a posting error", err)
+	}
+}
+
+func TestNotifyStopsOnCancel(t *testing.T) {
+	srv, requests := statusServer(t, http.StatusServiceUnavailable)
+	n := testNotifier()
+	n.Backoff = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := n.Notify(ctx, srv.URL, map[string]int{})
+	if err == nil || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+		t.Errorf("Notify error %v, want the context deadline", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("%d requests, want 1 before the cancelled backoff", got)
+	}
+}
@@ -0,0 +1,132 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+	"github.com/SofNam/devsecops-ai/pkg/reporter"
+)
+
+// slackTopFindings is the number of findings listed in a Slack digest
+const slackTopFindings = 5
+
+// slackEscaper escapes the control characters of Slack mrkdwn text
+var slackEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+)
+
+// slackMessage is an incoming-webhook payload using Block Kit
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// slackBlock is a Block Kit layout block
+type slackBlock struct {
+	Type   string      `json:"type"`
+	Text   *slackText  `json:"text,omitempty"`
+	Fields []slackText `json:"fields,omitempty"`
+}
+
+// slackText is a Block Kit text object
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// SlackMessage returns the JSON payload of a Slack digest for report: the
+// scan target, severity counts, risk score and the most severe findings
+func SlackMessage(report reporter.Report) ([]byte, error) {
+	stats := report.SummaryStats
+	summary := fmt.Sprintf("Security scan of %s found %d finding(s)", report.Target, stats.TotalFindings)
+
+	msg := slackMessage{
+		Text: summary,
+		Blocks: []slackBlock{
+			{
+				Type: "header",
+				Text: &slackText{Type: "plain_text", Text: "Security scan: " + report.Target},
+			},
+			{
+				Type: "section",
+				Fields: []slackText{
+					mrkdwn(fmt.Sprintf("*Critical:* %d", stats.CriticalCount)),
+					mrkdwn(fmt.Sprintf("*High:* %d", stats.HighCount)),
+					mrkdwn(fmt.Sprintf("*Medium:* %d", stats.MediumCount)),
+					mrkdwn(fmt.Sprintf("*Low:* %d", stats.LowCount)),
+					mrkdwn(fmt.Sprintf("*Info:* %d", stats.InfoCount)),
+					mrkdwn(fmt.Sprintf("*Risk score:* %.1f", report.RiskScore)),
+				},
+			},
+		},
+	}
+
+	if top := topFindings(report.Findings, slackTopFindings); len(top) > 0 {
+		var b strings.Builder
+		for _, finding := range top {
+			location := finding.Location
+			if finding.Line > 0 {
+				location = fmt.Sprintf("%s:%d", location, finding.Line)
+			}
+			fmt.Fprintf(&b, "• *%s* %s `%s`\n", finding.Severity, slackEscaper.Replace(finding.Title), slackEscaper.Replace(location))
+		}
+
+		msg.Blocks = append(msg.Blocks,
+			slackBlock{Type: "divider"},
+			slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: strings.TrimSuffix(b.String(), "\n")}},
+		)
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("encoding Slack message: %v", err)
+	}
+	return data, nil
+}
+
+// NotifySlack posts a digest of report to a Slack incoming webhook when any
+// finding is at or above threshold, reporting whether a message was sent
+func (n *Notifier) NotifySlack(ctx context.Context, url string, report reporter.Report, threshold models.Severity) (bool, error) {
+	if !reporter.ExceedsThreshold(report.Findings, threshold) {
+		return false, nil
+	}
+
+	payload, err := SlackMessage(report)
+	if err != nil {
+		return false, err
+	}
+
+	if err := n.Notify(ctx, url, json.RawMessage(payload)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// topFindings returns up to n findings, most severe and then most confident
+// first
+func topFindings(findings []models.Finding, n int) []models.Finding {
+	top := append([]models.Finding(nil), findings...)
+	sort.SliceStable(top, func(i, j int) bool {
+		ri, rj := top[i].Severity.Rank(), top[j].Severity.Rank()
+		if ri != rj {
+			return ri < rj
+		}
+		return top[i].Confidence > top[j].Confidence
+	})
+
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// mrkdwn returns a mrkdwn text object
+func mrkdwn(text string) slackText {
+	return slackText{Type: "mrkdwn", Text: text}
+}
@@ -0,0 +1,126 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+	"github.com/SofNam/devsecops-ai/pkg/reporter"
+)
+
+// slackReport builds a report of findings against ./app
+func slackReport(findings []models.Finding) reporter.Report {
+	return reporter.New(nil, "").Build(findings, reporter.Config{}, "./app", time.Now())
+}
+
+func TestSlackMessage(t *testing.T) {
+	var findings []models.Finding
+	for i := 0; i < 7; i++ {
+		findings = append(findings, models.Finding{
+			Title:      fmt.Sprintf("Low %d", i),
+			Severity:   models.SeverityLow,
+			Location:   "a.go",
+			Line:       i + 1,
+			Confidence: 0.5,
+		})
+	}
+	findings = append(findings,
+		models.Finding{Title: "<script> & co", Severity: models.SeverityCritical, Location: "x<y>.go", Line: 3, Confidence: 0.5},
+		models.Finding{Title: "Confident high", Severity: models.SeverityHigh, Location: "b.go", Confidence: 0.9},
+		models.Finding{Title: "Unsure high", Severity: models.SeverityHigh, Location: "c.go", Confidence: 0.2},
+	)
+
+	data, err := SlackMessage(slackReport(findings))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg slackMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Fatalf("payload is not JSON: %v\n%s", err, data)
+	}
+
+	if msg.Text != "Security scan of ./app found 10 finding(s)" {
+		t.Errorf("text %q", msg.Text)
+	}
+	if len(msg.Blocks) != 4 || msg.Blocks[0].Type != "header" || msg.Blocks[2].Type != "divider" {
+		t.Fatalf("blocks %+v, want header, counts, divider and findings", msg.Blocks)
+	}
+
+	var counts []string
+	for _, field := range msg.Blocks[1].Fields {
+		counts = append(counts, field.Text)
+	}
+	if want := "*Critical:* 1,*High:* 2,*Medium:* 0,*Low:* 7,*Info:* 0"; !strings.HasPrefix(strings.Join(counts, ","), want) {
+		t.Errorf("counts %v, want prefix %s", counts, want)
+	}
+
+	lines := strings.Split(msg.Blocks[3].Text.Text, "\n")
+	if len(lines) != slackTopFindings {
+		t.Fatalf("listed %d findings, want %d:\n%s", len(lines), slackTopFindings, msg.Blocks[3].Text.Text)
+	}
+	wantLines := []string{
+		"• *CRITICAL* &lt;script&gt; &amp; co `x&lt;y&gt;.go:3`",
+		"• *HIGH* Confident high `b.go`",
+		"• *HIGH* Unsure high `c.go`",
+		"• *LOW* Low 0 `a.go:1`",
+		"• *LOW* Low 1 `a.go:2`",
+	}
+	for i, want := range wantLines {
+		if lines[i] != want {
+			t.Errorf("line %d %q, want %q", i, lines[i], want)
+		}
+	}
+}
+
+func TestSlackMessageWithoutFindings(t *testing.T) {
+	data, err := SlackMessage(slackReport(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg slackMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Fatal(err)
+	}
+	if len(msg.Blocks) != 2 {
+		t.Errorf("%d blocks, want only the header and counts", len(msg.Blocks))
+	}
+}
+
+func TestNotifySlackThreshold(t *testing.T) {
+	var posted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		posted = append(posted, string(body))
+	}))
+	defer srv.Close()
+
+	report := slackReport([]models.Finding{{Title: "Weak hash", Severity: models.SeverityMedium}})
+	tests := []struct {
+		threshold models.Severity
+		want      bool
+	}{
+		{models.SeverityHigh, false},
+		{models.SeverityMedium, true},
+		{models.SeverityLow, true},
+	}
+	for _, tt := range tests {
+		posted = nil
+		sent, err := testNotifier().NotifySlack(context.Background(), srv.URL, report, tt.threshold)
+		if err != nil {
+			t.Fatalf("threshold %s: %v", tt.threshold, err)
+		}
+		if sent != tt.want || (len(posted) == 1) != tt.want {
+			t.Errorf("threshold %s: sent %v with %d posts, want %v", tt.threshold, sent, len(posted), tt.want)
+		}
+		if tt.want && !strings.Contains(posted[0], `"blocks"`) {
+			t.Errorf("threshold %s: posted %s, want a Block Kit message", tt.threshold, posted[0])
+		}
+	}
+}
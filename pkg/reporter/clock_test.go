@@ -0,0 +1,50 @@
+package reporter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPinnedClockReproducesReports(t *testing.T) {
+	formats := []string{"json", "html", "junit", "md", "ndjson"}
+	start := testTime.Add(-90 * time.Second)
+
+	// write generates the reports in a fresh directory, pinning only the
+	// clock, and returns the built report and its reporter
+	write := func() (*Reporter, Report) {
+		r := New(formats, filepath.Join(t.TempDir(), "report"))
+		r.Clock = func() time.Time { return testTime }
+		report := r.Build(sampleFindings(), Config{Version: "1.0"}, "./project", start)
+		if err := r.Write(report); err != nil {
+			t.Fatal(err)
+		}
+		return r, report
+	}
+
+	first, report := write()
+	second, _ := write()
+
+	if !report.Timestamp.Equal(testTime) || report.ScanDuration != "1m30s" {
+		t.Errorf("timestamp %v and duration %s, want them from the clock", report.Timestamp, report.ScanDuration)
+	}
+	if want := "SCAN-1709294400"; report.ScanID != want {
+		t.Errorf("scan ID %q, want %q from the clock", report.ScanID, want)
+	}
+
+	for _, format := range formats {
+		a, err := os.ReadFile(first.PathFor(report, format))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(second.PathFor(report, format))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(a, b) {
+			t.Errorf("%s reports differ under the same clock", format)
+		}
+	}
+}
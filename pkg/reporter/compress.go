@@ -0,0 +1,56 @@
+package reporter
+
+import (
+	"compress/gzip"
+	"os"
+	"strings"
+)
+
+// gzipExtension is appended to the paths of compressed reports
+const gzipExtension = ".gz"
+
+// compressible reports whether a format can be written gzip-compressed
+func compressible(format string) bool {
+	return format == "json" || format == "ndjson"
+}
+
+// reportFile is a report output file, gzip-compressed when its path ends in
+// gzipExtension
+type reportFile struct {
+	file *os.File
+	gz   *gzip.Writer
+}
+
+// createReportFile creates the report file at path. Content is compressed
+// as it is written, so large reports are never held in memory.
+func createReportFile(path string) (*reportFile, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &reportFile{file: file}
+	if strings.HasSuffix(path, gzipExtension) {
+		out.gz = gzip.NewWriter(file)
+	}
+	return out, nil
+}
+
+func (f *reportFile) Write(p []byte) (int, error) {
+	if f.gz != nil {
+		return f.gz.Write(p)
+	}
+	return f.file.Write(p)
+}
+
+// Close completes the gzip stream, if any, and closes the file. It is safe
+// to call again after a deferred Close.
+func (f *reportFile) Close() error {
+	if f.gz != nil {
+		if err := f.gz.Close(); err != nil {
+			f.file.Close()
+			return err
+		}
+	}
+	return f.file.Close()
+}
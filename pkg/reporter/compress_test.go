@@ -0,0 +1,83 @@
+package reporter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"testing"
+)
+
+// readGzip returns the decompressed content of a gzip file
+func readGzip(t *testing.T, path string) []byte {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// writeReport writes the sample report in format, compressed if requested,
+// returning the path written
+func writeReport(t *testing.T, format string, compress bool) string {
+	t.Helper()
+	r := testReporter(t, format)
+	r.Compress = compress
+	report := r.Build(sampleFindings(), Config{}, ".", testTime)
+	if err := r.Write(report); err != nil {
+		t.Fatal(err)
+	}
+	return r.PathFor(report, format)
+}
+
+func TestCompressedReportsMatchPlain(t *testing.T) {
+	for _, format := range []string{"json", "ndjson"} {
+		t.Run(format, func(t *testing.T) {
+			plain, err := os.ReadFile(writeReport(t, format, false))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			path := writeReport(t, format, true)
+			if got := path[len(path)-len(gzipExtension):]; got != gzipExtension {
+				t.Fatalf("compressed report written to %s", path)
+			}
+			if !bytes.Equal(readGzip(t, path), plain) {
+				t.Error("decompressed report differs from the plain report")
+			}
+
+			first, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			second, err := os.ReadFile(writeReport(t, format, true))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(first, second) {
+				t.Error("compressing the same report twice gave different bytes")
+			}
+		})
+	}
+}
+
+func TestCompressSkipsOtherFormats(t *testing.T) {
+	path := writeReport(t, "md", true)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("markdown report not written uncompressed: %v", err)
+	}
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		t.Error("markdown report was compressed")
+	}
+}
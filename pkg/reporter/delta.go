@@ -0,0 +1,106 @@
+package reporter
+
+import (
+	"sort"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// Delta summarizes what changed since a previous scan. Findings are matched
+// by fingerprint, so a finding that only moved to another line persists.
+type Delta struct {
+	NewCount        int `json:"newCount"`
+	FixedCount      int `json:"fixedCount"`
+	PersistingCount int `json:"persistingCount"`
+
+	// Counts breaks the totals down by severity, most severe first
+	Counts []DeltaCount `json:"counts"`
+
+	// New lists the findings not present in the previous scan
+	New []models.Finding `json:"new"`
+}
+
+// DeltaCount counts the changes at one severity. Fixed findings count at
+// their previous severity, others at their current one.
+type DeltaCount struct {
+	Severity   models.Severity `json:"severity"`
+	New        int             `json:"new"`
+	Fixed      int             `json:"fixed"`
+	Persisting int             `json:"persisting"`
+}
+
+// Compare computes the delta from the findings of a previous scan to the
+// current ones
+func Compare(previous, current []models.Finding) *Delta {
+	// Earlier reports may hold severities in any case or alias
+	previous = models.NormalizeSeverities(previous)
+	current = models.NormalizeSeverities(current)
+	before := fingerprints(previous)
+	after := fingerprints(current)
+
+	delta := &Delta{New: []models.Finding{}}
+	counts := make(map[models.Severity]*DeltaCount)
+	count := func(severity models.Severity) *DeltaCount {
+		c, ok := counts[severity]
+		if !ok {
+			c = &DeltaCount{Severity: severity}
+			counts[severity] = c
+		}
+		return c
+	}
+
+	for i, finding := range current {
+		if before.set[after.keys[i]] {
+			delta.PersistingCount++
+			count(finding.Severity).Persisting++
+			continue
+		}
+		delta.NewCount++
+		count(finding.Severity).New++
+		delta.New = append(delta.New, finding)
+	}
+	for i, finding := range previous {
+		if !after.set[before.keys[i]] {
+			delta.FixedCount++
+			count(finding.Severity).Fixed++
+		}
+	}
+
+	for _, severity := range models.Severities() {
+		delta.Counts = append(delta.Counts, *count(severity))
+		delete(counts, severity)
+	}
+	// Unrecognized severities follow the known ones
+	var unknown []string
+	for severity := range counts {
+		unknown = append(unknown, string(severity))
+	}
+	sort.Strings(unknown)
+	for _, severity := range unknown {
+		delta.Counts = append(delta.Counts, *counts[models.Severity(severity)])
+	}
+
+	return delta
+}
+
+// fingerprintSet holds the fingerprint of each finding by position and the
+// set of all of them
+type fingerprintSet struct {
+	keys []string
+	set  map[string]bool
+}
+
+// fingerprints computes the fingerprints of findings, deriving those not
+// already set
+func fingerprints(findings []models.Finding) fingerprintSet {
+	fs := fingerprintSet{keys: make([]string, len(findings)), set: make(map[string]bool, len(findings))}
+	for i, finding := range findings {
+		key := finding.Fingerprint
+		if key == "" {
+			key = models.ComputeFingerprint(finding)
+		}
+		fs.keys[i] = key
+		fs.set[key] = true
+	}
+	return fs
+}
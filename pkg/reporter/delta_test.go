@@ -0,0 +1,54 @@
+package reporter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+func TestCompare(t *testing.T) {
+	previous := []models.Finding{
+		{ID: "P1", RuleID: "SQLI", Category: "Injection", Severity: High, Location: "src/db.go", Line: 10, CodeSnippet: `db.Query("SELECT " + name)`},
+		{ID: "P2", RuleID: "MD5", Category: "Crypto", Severity: Medium, Location: "src/hash.go", Line: 7, CodeSnippet: "md5.Sum(data)"},
+	}
+	current := []models.Finding{
+		// The same query moved down and reindented
+		{ID: "C1", RuleID: "SQLI", Category: "Injection", Severity: "high", Location: "src/db.go", Line: 14, CodeSnippet: `	db.Query("SELECT "  +  name)`},
+		{ID: "C2", RuleID: "SECRET", Category: "Secrets", Severity: Critical, Location: "config.py", Line: 3, CodeSnippet: `token = "x"`},
+	}
+
+	delta := Compare(previous, current)
+	if delta.NewCount != 1 || delta.FixedCount != 1 || delta.PersistingCount != 1 {
+		t.Errorf("delta %d new, %d fixed, %d persisting, want 1 of each", delta.NewCount, delta.FixedCount, delta.PersistingCount)
+	}
+	if len(delta.New) != 1 || delta.New[0].ID != "C2" {
+		t.Errorf("new findings %+v, want C2", delta.New)
+	}
+
+	counts := make(map[models.Severity]DeltaCount)
+	for _, c := range delta.Counts {
+		counts[c.Severity] = c
+	}
+	want := map[models.Severity]DeltaCount{
+		Critical: {Severity: Critical, New: 1},
+		High:     {Severity: High, Persisting: 1},
+		Medium:   {Severity: Medium, Fixed: 1},
+	}
+	for severity, c := range want {
+		if !reflect.DeepEqual(counts[severity], c) {
+			t.Errorf("%s counts %+v, want %+v", severity, counts[severity], c)
+		}
+	}
+	if len(delta.Counts) != len(models.Severities()) {
+		t.Errorf("got %d severity rows, want one per known severity", len(delta.Counts))
+	}
+}
+
+func TestCompareUsesStoredFingerprints(t *testing.T) {
+	previous := []models.Finding{{ID: "P1", RuleID: "SQLI", Location: "old/db.go", Fingerprint: "fp-1"}}
+	current := []models.Finding{{ID: "C1", RuleID: "SQLI", Location: "new/db.go", Fingerprint: "fp-1"}}
+	if delta := Compare(previous, current); delta.PersistingCount != 1 || delta.NewCount != 0 {
+		t.Errorf("delta %+v, want the fingerprinted finding persisting", delta)
+	}
+}
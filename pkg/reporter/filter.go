@@ -0,0 +1,62 @@
+package reporter
+
+import (
+	"strings"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// FilterFunc reports whether a finding should be kept in the report
+type FilterFunc func(finding models.Finding) bool
+
+// MinSeverity keeps findings at or above the given severity
+func MinSeverity(severity models.Severity) FilterFunc {
+	return func(finding models.Finding) bool {
+		return finding.Severity.AtLeast(severity)
+	}
+}
+
+// CategoryIn keeps findings whose category is one of the given categories,
+// compared case-insensitively
+func CategoryIn(categories ...string) FilterFunc {
+	set := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		set[strings.ToLower(strings.TrimSpace(category))] = true
+	}
+
+	return func(finding models.Finding) bool {
+		return set[strings.ToLower(finding.Category)]
+	}
+}
+
+// All keeps findings accepted by every filter
+func All(filters ...FilterFunc) FilterFunc {
+	return func(finding models.Finding) bool {
+		for _, filter := range filters {
+			if !filter(finding) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// keep reports whether a finding passes the reporter's filters
+func (r *Reporter) keep(finding models.Finding) bool {
+	return All(r.Filters...)(finding)
+}
+
+// filter returns the findings that pass the reporter's filters
+func (r *Reporter) filter(findings []models.Finding) []models.Finding {
+	if len(r.Filters) == 0 {
+		return findings
+	}
+
+	var kept []models.Finding
+	for _, finding := range findings {
+		if r.keep(finding) {
+			kept = append(kept, finding)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,70 @@
+package reporter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFiltersChangeFindingsAndCounts(t *testing.T) {
+	tests := []struct {
+		name     string
+		filters  []FilterFunc
+		ids      []string
+		stats    Stats
+		coverage map[string]int
+	}{
+		{
+			name:     "none",
+			ids:      []string{"F1", "F2", "F3", "F4"},
+			stats:    Stats{TotalFindings: 4, CriticalCount: 1, HighCount: 1, MediumCount: 1, InfoCount: 1},
+			coverage: map[string]int{"SQLI": 1, "SECRET": 1, "MD5": 1, "TODO": 1},
+		},
+		{
+			name:     "min severity",
+			filters:  []FilterFunc{MinSeverity(High)},
+			ids:      []string{"F1", "F2"},
+			stats:    Stats{TotalFindings: 2, CriticalCount: 1, HighCount: 1},
+			coverage: map[string]int{"SQLI": 1, "SECRET": 1},
+		},
+		{
+			name:     "category",
+			filters:  []FilterFunc{CategoryIn(" crypto", "HYGIENE")},
+			ids:      []string{"F3", "F4"},
+			stats:    Stats{TotalFindings: 2, MediumCount: 1, InfoCount: 1},
+			coverage: map[string]int{"MD5": 1, "TODO": 1},
+		},
+		{
+			name:     "combined",
+			filters:  []FilterFunc{MinSeverity(Medium), CategoryIn("Crypto", "Hygiene")},
+			ids:      []string{"F3"},
+			stats:    Stats{TotalFindings: 1, MediumCount: 1},
+			coverage: map[string]int{"MD5": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testReporter(t)
+			r.Filters = tt.filters
+			report := r.Build(sampleFindings(), Config{}, ".", testTime)
+
+			var ids []string
+			for _, finding := range report.Findings {
+				ids = append(ids, finding.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.ids) {
+				t.Errorf("findings %v, want %v", ids, tt.ids)
+			}
+			if !reflect.DeepEqual(report.SummaryStats, tt.stats) {
+				t.Errorf("stats %+v, want %+v", report.SummaryStats, tt.stats)
+			}
+			for _, rule := range []string{"SQLI", "SECRET", "MD5", "TODO"} {
+				if got, want := report.RuleCoverage[rule], tt.coverage[rule]; got != want {
+					t.Errorf("coverage of %s = %d, want %d", rule, got, want)
+				}
+			}
+			if len(report.ByFile) == 0 && len(tt.ids) > 0 {
+				t.Error("per-file summary is empty")
+			}
+		})
+	}
+}
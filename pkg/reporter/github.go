@@ -0,0 +1,66 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// githubMessageEscaper escapes workflow command messages
+var githubMessageEscaper = strings.NewReplacer(
+	"%", "%25",
+	"\r", "%0D",
+	"\n", "%0A",
+)
+
+// githubPropertyEscaper escapes workflow command property values, which
+// additionally may not contain the property delimiters
+var githubPropertyEscaper = strings.NewReplacer(
+	"%", "%25",
+	"\r", "%0D",
+	"\n", "%0A",
+	":", "%3A",
+	",", "%2C",
+)
+
+// generateGitHub writes findings to stdout as GitHub Actions workflow
+// commands so they appear as inline annotations
+func (r *Reporter) generateGitHub(report Report, _ string) error {
+	return writeGitHub(os.Stdout, report)
+}
+
+// writeGitHub writes one annotation per finding
+func writeGitHub(w io.Writer, report Report) error {
+	for _, finding := range report.Findings {
+		properties := "file=" + githubPropertyEscaper.Replace(finding.Location)
+		if finding.Line > 0 {
+			properties += fmt.Sprintf(",line=%d", finding.Line)
+		}
+		if finding.Column > 0 {
+			properties += fmt.Sprintf(",col=%d", finding.Column)
+		}
+		properties += ",title=" + githubPropertyEscaper.Replace(fmt.Sprintf("%s: %s", finding.ID, finding.Title))
+
+		message := fmt.Sprintf("[%s] %s", finding.Severity, finding.Description)
+		if finding.Remediation != "" {
+			message += "\n" + finding.Remediation
+		}
+
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", githubCommand(finding.Severity), properties, githubMessageEscaper.Replace(message)); err != nil {
+			return fmt.Errorf("failed to write annotation: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// githubCommand maps a severity to the workflow command for its annotation
+func githubCommand(severity models.Severity) string {
+	if severity.AtLeast(High) {
+		return "error"
+	}
+	return "warning"
+}
@@ -0,0 +1,41 @@
+package reporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+func TestGitHubAnnotations(t *testing.T) {
+	report := Report{Findings: []models.Finding{
+		{ID: "F1", Title: "SQL injection", Description: "100% tainted\r\nquery", Remediation: "Use parameters", Severity: High, Location: "src/db,v2:x.go", Line: 12, Column: 4},
+		{ID: "F2", Title: "Note: a, b", Description: "d", Severity: Low, Location: "app.py"},
+	}}
+
+	var b strings.Builder
+	if err := writeGitHub(&b, report); err != nil {
+		t.Fatalf("writeGitHub: %v", err)
+	}
+
+	want := "::error file=src/db%2Cv2%3Ax.go,line=12,col=4,title=F1%3A SQL injection::[HIGH] 100%25 tainted%0D%0Aquery%0AUse parameters\n" +
+		"::warning file=app.py,title=F2%3A Note%3A a%2C b::[LOW] d\n"
+	if got := b.String(); got != want {
+		t.Errorf("annotations:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGitHubCommand(t *testing.T) {
+	tests := map[models.Severity]string{
+		Critical: "error",
+		High:     "error",
+		Medium:   "warning",
+		Low:      "warning",
+		Info:     "warning",
+	}
+	for severity, want := range tests {
+		if got := githubCommand(severity); got != want {
+			t.Errorf("githubCommand(%s) = %s, want %s", severity, got, want)
+		}
+	}
+}
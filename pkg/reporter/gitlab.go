@@ -0,0 +1,97 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// gitlabSeverities maps finding severities to the GitLab Code Quality scale
+var gitlabSeverities = map[models.Severity]string{
+	Critical: "blocker",
+	High:     "major",
+	Medium:   "minor",
+	Low:      "minor",
+	Info:     "info",
+}
+
+// gitlabIssue is a single entry of a GitLab Code Quality report
+type gitlabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitlabLocation `json:"location"`
+}
+
+// gitlabLocation points to the file and line of an issue
+type gitlabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitlabLines `json:"lines"`
+}
+
+// gitlabLines holds the first line of an issue
+type gitlabLines struct {
+	Begin int `json:"begin"`
+}
+
+// generateGitLab creates a GitLab Code Quality report
+func (r *Reporter) generateGitLab(report Report, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %v", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(buildGitLab(report)); err != nil {
+		return fmt.Errorf("failed to encode report: %v", err)
+	}
+
+	return nil
+}
+
+// buildGitLab converts report findings into Code Quality issues
+func buildGitLab(report Report) []gitlabIssue {
+	// GitLab expects an array, even when there is nothing to report
+	issues := make([]gitlabIssue, 0, len(report.Findings))
+
+	for _, finding := range report.Findings {
+		// The fingerprint lets GitLab track an issue across pipelines
+		fingerprint := finding.Fingerprint
+		if fingerprint == "" {
+			fingerprint = models.ComputeFingerprint(finding)
+		}
+
+		checkName := finding.RuleID
+		if checkName == "" {
+			checkName = finding.ID
+		}
+
+		severity, ok := gitlabSeverities[finding.Severity]
+		if !ok {
+			severity = "info"
+		}
+
+		line := finding.Line
+		if line < 1 {
+			line = 1
+		}
+
+		issues = append(issues, gitlabIssue{
+			Description: fmt.Sprintf("%s: %s", finding.Title, finding.Description),
+			CheckName:   checkName,
+			Fingerprint: fingerprint,
+			Severity:    severity,
+			Location: gitlabLocation{
+				Path:  finding.Location,
+				Lines: gitlabLines{Begin: line},
+			},
+		})
+	}
+
+	return issues
+}
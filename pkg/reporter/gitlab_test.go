@@ -0,0 +1,77 @@
+package reporter
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+func TestGitLabReport(t *testing.T) {
+	findings := []models.Finding{
+		{ID: "F1", RuleID: "SQLI", Title: "SQL injection", Description: "query built from input", Severity: High, Location: "src/db.go", Line: 12, Fingerprint: "abc123"},
+		{ID: "F2", Title: "Config note", Description: "d", Severity: "BOGUS", Location: "app.yaml"},
+	}
+	r := testReporter(t, "gitlab")
+	report := r.Build(findings, Config{}, ".", testTime)
+	if err := r.Write(report); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	data, err := os.ReadFile(r.PathFor(report, "gitlab"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var issues []map[string]interface{}
+	if err := json.Unmarshal(data, &issues); err != nil {
+		t.Fatalf("report is not a JSON array: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2", len(issues))
+	}
+
+	sqli := issues[0]
+	location := sqli["location"].(map[string]interface{})
+	lines := location["lines"].(map[string]interface{})
+	if sqli["check_name"] != "SQLI" || sqli["fingerprint"] != "abc123" || sqli["severity"] != "major" ||
+		sqli["description"] != "SQL injection: query built from input" ||
+		location["path"] != "src/db.go" || lines["begin"] != 12.0 {
+		t.Errorf("unexpected issue %v", sqli)
+	}
+
+	note := issues[1]
+	if note["check_name"] != "F2" || note["severity"] != "info" || note["fingerprint"] == "" {
+		t.Errorf("unexpected issue %v", note)
+	}
+	if begin := note["location"].(map[string]interface{})["lines"].(map[string]interface{})["begin"]; begin != 1.0 {
+		t.Errorf("issue without a line begins at %v, want 1", begin)
+	}
+}
+
+func TestGitLabSeverities(t *testing.T) {
+	tests := map[models.Severity]string{
+		Critical: "blocker",
+		High:     "major",
+		Medium:   "minor",
+		Low:      "minor",
+		Info:     "info",
+		"BOGUS":  "info",
+	}
+	for severity, want := range tests {
+		issues := buildGitLab(Report{Findings: []models.Finding{{Severity: severity}}})
+		if got := issues[0].Severity; got != want {
+			t.Errorf("%s maps to %s, want %s", severity, got, want)
+		}
+	}
+}
+
+func TestGitLabEmptyReport(t *testing.T) {
+	data, err := json.Marshal(buildGitLab(Report{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "[]" {
+		t.Errorf("empty report encodes as %s, want []", data)
+	}
+}
@@ -0,0 +1,179 @@
+package reporter
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// collapseThreshold is the group size above which a category starts
+// collapsed in the HTML report
+const collapseThreshold = 50
+
+// htmlData is the data passed to HTML templates: the report plus its
+// findings grouped by category and by severity for the table of contents.
+// Both groupings share the same findings, so that a finding has one anchor
+// wherever it appears.
+type htmlData struct {
+	Report
+	Groups   []FindingGroup
+	Contents []SeverityGroup
+
+	anchors map[*models.Finding]string
+}
+
+// newHTMLData builds the template data for report
+func newHTMLData(report Report) htmlData {
+	findings := slices.Clone(report.Findings)
+	return htmlData{
+		Report:   report,
+		Groups:   groupFindings(findings),
+		Contents: groupBySeverity(findings),
+		anchors:  findingAnchors(findings),
+	}
+}
+
+// anchor returns the HTML id of one of the data's findings
+func (d htmlData) anchor(finding *models.Finding) string {
+	if anchor, ok := d.anchors[finding]; ok {
+		return anchor
+	}
+	return findingAnchor(finding)
+}
+
+// FindingGroup holds the findings of one category
+type FindingGroup struct {
+	Category  string
+	Findings  []*models.Finding
+	Collapsed bool
+}
+
+// SeverityGroup holds the findings of one severity
+type SeverityGroup struct {
+	Severity models.Severity
+	Findings []*models.Finding
+}
+
+// groupFindings groups findings by category, critical-first by the most
+// severe finding in each group and then by category name, with each group
+// ordered by severity, then location and line
+func groupFindings(findings []models.Finding) []FindingGroup {
+	byCategory := make(map[string][]*models.Finding)
+	for i := range findings {
+		category := findings[i].Category
+		if category == "" {
+			category = "Uncategorized"
+		}
+		byCategory[category] = append(byCategory[category], &findings[i])
+	}
+
+	groups := make([]FindingGroup, 0, len(byCategory))
+	for category, grouped := range byCategory {
+		sortFindings(grouped)
+
+		groups = append(groups, FindingGroup{
+			Category:  category,
+			Findings:  grouped,
+			Collapsed: len(grouped) > collapseThreshold,
+		})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if ra, rb := a.Findings[0].Severity.Rank(), b.Findings[0].Severity.Rank(); ra != rb {
+			return ra < rb
+		}
+		return a.Category < b.Category
+	})
+
+	return groups
+}
+
+// groupBySeverity groups findings by severity, most severe first with
+// unknown severities last in name order, each group ordered by location
+// and line
+func groupBySeverity(findings []models.Finding) []SeverityGroup {
+	bySeverity := make(map[models.Severity][]*models.Finding)
+	for i := range findings {
+		bySeverity[findings[i].Severity] = append(bySeverity[findings[i].Severity], &findings[i])
+	}
+
+	groups := make([]SeverityGroup, 0, len(bySeverity))
+	for severity, grouped := range bySeverity {
+		sortFindings(grouped)
+		groups = append(groups, SeverityGroup{Severity: severity, Findings: grouped})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i].Severity, groups[j].Severity
+		if ra, rb := a.Rank(), b.Rank(); ra != rb {
+			return ra < rb
+		}
+		return a < b
+	})
+
+	return groups
+}
+
+// sortFindings orders findings by severity, then location and line
+func sortFindings(findings []*models.Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if ra, rb := a.Severity.Rank(), b.Severity.Rank(); ra != rb {
+			return ra < rb
+		}
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		return a.Line < b.Line
+	})
+}
+
+// builtinSeverities are the severities styled by the built-in HTML template
+var builtinSeverities = map[models.Severity]bool{
+	Critical: true, High: true, Medium: true, Low: true, Info: true,
+}
+
+// customSeverities returns the registered severity levels beyond the
+// built-in five, most severe first
+func customSeverities() []models.SeverityLevel {
+	var levels []models.SeverityLevel
+	for _, level := range models.SeverityLevels() {
+		if !builtinSeverities[level.Severity] {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+// findingAnchor returns the HTML id of a finding, derived from its
+// fingerprint so links to it stay valid across scans
+func findingAnchor(finding *models.Finding) string {
+	key := finding.Fingerprint
+	if key == "" {
+		key = models.ComputeFingerprint(*finding)
+	}
+	return "finding-" + strings.ToLower(key)
+}
+
+// findingAnchors returns a unique HTML id for each of findings. Findings
+// sharing a fingerprint, such as the same match reported twice, are told
+// apart by their order in the report: the first keeps the plain anchor and
+// later ones get a numbered suffix.
+func findingAnchors(findings []models.Finding) map[*models.Finding]string {
+	anchors := make(map[*models.Finding]string, len(findings))
+	used := make(map[string]bool, len(findings))
+	for i := range findings {
+		base := findingAnchor(&findings[i])
+		anchor := base
+		for n := 2; used[anchor]; n++ {
+			anchor = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[anchor] = true
+		anchors[&findings[i]] = anchor
+	}
+	return anchors
+}
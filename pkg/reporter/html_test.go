@@ -0,0 +1,248 @@
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// renderHTML writes an HTML report of report with r and returns it
+func renderHTML(t *testing.T, r *Reporter, report Report) string {
+	t.Helper()
+	if err := r.Write(report); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	data, err := os.ReadFile(r.PathFor(report, "html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestCustomHTMLTemplate(t *testing.T) {
+	r := testReporter(t, "html")
+	r.TemplatePath = filepath.Join(t.TempDir(), "minimal.html")
+	template := `<h1>{{.ScanID}}: {{.SummaryStats.TotalFindings}}</h1>
+{{range .Groups}}<h2>{{.Category}}</h2>{{range .Findings}}<p id="{{findingAnchor .}}">{{toLowerCase (printf "%s" .Severity)}} {{.Title}}</p>{{end}}{{end}}`
+	if err := os.WriteFile(r.TemplatePath, []byte(template), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := renderHTML(t, r, r.Build(sampleFindings()[:2], Config{}, ".", testTime))
+	for _, want := range []string{"<h1>SCAN-TEST: 2</h1>", "<h2>Secrets</h2>", "critical Hardcoded secret", "high SQL injection"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("report does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestCustomHTMLTemplateErrors(t *testing.T) {
+	dir := t.TempDir()
+	broken := filepath.Join(dir, "broken.html")
+	if err := os.WriteFile(broken, []byte("{{.Missing"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{broken, filepath.Join(dir, "missing.html")} {
+		r := testReporter(t, "html")
+		r.TemplatePath = path
+		err := r.Write(r.Build(nil, Config{}, ".", testTime))
+		if err == nil || !strings.Contains(err.Error(), "HTML template") {
+			t.Errorf("template %s: err = %v, want a template error", filepath.Base(path), err)
+		}
+	}
+}
+
+func TestHTMLEscapesFindings(t *testing.T) {
+	findings := sampleFindings()[:1]
+	findings[0].Title = "<script>alert(1)</script>"
+	findings[0].CodeSnippet = `if a && b { render("<b>") }`
+	findings[0].Description = "Tom & Jerry"
+
+	r := testReporter(t, "html")
+	got := renderHTML(t, r, r.Build(findings, Config{}, ".", testTime))
+
+	for _, raw := range []string{"<script>alert(1)</script>", "a && b", "<b>", "Tom & Jerry"} {
+		if strings.Contains(got, raw) {
+			t.Errorf("report contains unescaped %q", raw)
+		}
+	}
+	for _, escaped := range []string{"&lt;script&gt;alert(1)&lt;/script&gt;", "a &amp;&amp; b", "Tom &amp; Jerry"} {
+		if !strings.Contains(got, escaped) {
+			t.Errorf("report does not contain escaped %q", escaped)
+		}
+	}
+}
+
+// groupSummary describes groups as "category:ID,ID"
+func groupSummary(groups []FindingGroup) []string {
+	var out []string
+	for _, group := range groups {
+		var ids []string
+		for _, finding := range group.Findings {
+			ids = append(ids, finding.ID)
+		}
+		out = append(out, group.Category+":"+strings.Join(ids, ","))
+	}
+	return out
+}
+
+func TestGroupFindings(t *testing.T) {
+	findings := []models.Finding{
+		{ID: "inj-low", Category: "Injection", Severity: Low, Location: "a.go", Line: 1},
+		{ID: "crypto-high-b", Category: "Crypto", Severity: High, Location: "b.go", Line: 1},
+		{ID: "inj-high", Category: "Injection", Severity: High, Location: "z.go", Line: 9},
+		{ID: "crypto-high-a2", Category: "Crypto", Severity: High, Location: "a.go", Line: 20},
+		{ID: "crypto-high-a1", Category: "Crypto", Severity: High, Location: "a.go", Line: 3},
+		{ID: "none", Severity: Critical, Location: "c.go", Line: 1},
+		{ID: "hygiene", Category: "Hygiene", Severity: Info, Location: "a.go", Line: 1},
+	}
+
+	got := groupSummary(groupFindings(findings))
+	want := []string{
+		"Uncategorized:none",
+		"Crypto:crypto-high-a1,crypto-high-a2,crypto-high-b",
+		"Injection:inj-high,inj-low",
+		"Hygiene:hygiene",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groups %v, want %v", got, want)
+	}
+}
+
+func TestGroupFindingsCollapsesLargeGroups(t *testing.T) {
+	var findings []models.Finding
+	for i := 0; i <= collapseThreshold; i++ {
+		findings = append(findings, models.Finding{ID: fmt.Sprint(i), Category: "Large", Severity: Low, Line: i})
+	}
+	for i := 0; i < collapseThreshold; i++ {
+		findings = append(findings, models.Finding{ID: fmt.Sprint(i), Category: "Small", Severity: Low, Line: i})
+	}
+
+	for _, group := range groupFindings(findings) {
+		if want := group.Category == "Large"; group.Collapsed != want {
+			t.Errorf("%s group of %d collapsed = %v, want %v", group.Category, len(group.Findings), group.Collapsed, want)
+		}
+	}
+}
+
+func TestGroupBySeverity(t *testing.T) {
+	findings := []models.Finding{
+		{ID: "low", Severity: Low},
+		{ID: "bogus", Severity: "BOGUS"},
+		{ID: "critical-b", Severity: Critical, Location: "b.go"},
+		{ID: "critical-a", Severity: Critical, Location: "a.go"},
+		{ID: "another", Severity: "ANOTHER"},
+	}
+
+	var got []string
+	for _, group := range groupBySeverity(findings) {
+		var ids []string
+		for _, finding := range group.Findings {
+			ids = append(ids, finding.ID)
+		}
+		got = append(got, string(group.Severity)+":"+strings.Join(ids, ","))
+	}
+	want := []string{"CRITICAL:critical-a,critical-b", "LOW:low", "ANOTHER:another", "BOGUS:bogus"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("severity groups %v, want %v", got, want)
+	}
+}
+
+// findingIDsPattern and findingLinksPattern match the finding cards and the
+// table of contents links of the built-in HTML report
+var (
+	findingIDsPattern   = regexp.MustCompile(`id="(finding-[^"]*)"`)
+	findingLinksPattern = regexp.MustCompile(`href="#(finding-[^"]*)"`)
+)
+
+func TestFindingAnchorsUnique(t *testing.T) {
+	findings := sampleFindings()
+	for i := range findings {
+		findings[i].Fingerprint = fmt.Sprintf("FP%d", i)
+	}
+	// The same match reported under two categories shares a fingerprint
+	duplicate := findings[0]
+	duplicate.ID, duplicate.Category = "F5", "Hygiene"
+	findings = append(findings, duplicate)
+
+	r := testReporter(t, "html")
+	report := r.Build(findings, Config{}, ".", testTime)
+	got := renderHTML(t, r, report)
+
+	var ids []string
+	seen := make(map[string]bool)
+	for _, match := range findingIDsPattern.FindAllStringSubmatch(got, -1) {
+		if seen[match[1]] {
+			t.Errorf("duplicate finding id %q", match[1])
+		}
+		seen[match[1]] = true
+		ids = append(ids, match[1])
+	}
+	if len(ids) != len(findings) {
+		t.Errorf("%d finding ids %v, want %d", len(ids), ids, len(findings))
+	}
+	for _, want := range []string{"finding-fp0", "finding-fp0-2"} {
+		if !seen[want] {
+			t.Errorf("finding ids %v, want %q", ids, want)
+		}
+	}
+
+	links := findingLinksPattern.FindAllStringSubmatch(got, -1)
+	if len(links) != len(findings) {
+		t.Errorf("%d table of contents links, want %d", len(links), len(findings))
+	}
+	for _, link := range links {
+		if !seen[link[1]] {
+			t.Errorf("link to #%s matches no finding", link[1])
+		}
+	}
+
+	// Grouping for the template leaves the report's findings in order
+	for i, finding := range report.Findings {
+		if finding.ID != findings[i].ID {
+			t.Errorf("report finding %d is %s after rendering, want %s", i, finding.ID, findings[i].ID)
+		}
+	}
+}
+
+func TestHTMLDataSharesFindings(t *testing.T) {
+	findings := sampleFindings()
+	for i := range findings {
+		findings[i].Fingerprint = "SAME"
+	}
+	data := newHTMLData(Report{Findings: findings})
+
+	if got, want := groupSummary(data.Groups), []string{
+		"Secrets:F2", "Injection:F1", "Crypto:F3", "Hygiene:F4",
+	}; !reflect.DeepEqual(got, want) {
+		t.Errorf("groups %v, want %v", got, want)
+	}
+
+	// Each finding has the same anchor in the contents as in its group
+	anchors := make(map[string]string)
+	for _, group := range data.Groups {
+		for _, finding := range group.Findings {
+			anchors[finding.ID] = data.anchor(finding)
+		}
+	}
+	var got []string
+	for _, group := range data.Contents {
+		for _, finding := range group.Findings {
+			if anchor := data.anchor(finding); anchor != anchors[finding.ID] {
+				t.Errorf("%s is %q in the contents and %q in its group", finding.ID, anchor, anchors[finding.ID])
+			}
+			got = append(got, finding.ID+"="+data.anchor(finding))
+		}
+	}
+	want := []string{"F2=finding-same-2", "F1=finding-same", "F3=finding-same-3", "F4=finding-same-4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("contents anchors %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,131 @@
+package reporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite groups the findings of a single category
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase represents a single finding
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+// junitFailure marks a finding that should fail the build
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// generateJUnit creates a JUnit XML report with one suite per category
+func (r *Reporter) generateJUnit(report Report, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(xml.Header); err != nil {
+		return fmt.Errorf("failed to write report: %v", err)
+	}
+
+	encoder := xml.NewEncoder(file)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(buildJUnit(report)); err != nil {
+		return fmt.Errorf("failed to encode report: %v", err)
+	}
+
+	return nil
+}
+
+// buildJUnit converts a report into JUnit test suites
+func buildJUnit(report Report) junitTestSuites {
+	seconds := "0"
+	if duration, err := time.ParseDuration(report.ScanDuration); err == nil {
+		seconds = fmt.Sprintf("%.3f", duration.Seconds())
+	}
+	timestamp := report.Timestamp.Format(time.RFC3339)
+
+	suitesByCategory := make(map[string]*junitTestSuite)
+	for _, finding := range report.Findings {
+		category := finding.Category
+		if category == "" {
+			category = "Uncategorized"
+		}
+
+		suite, ok := suitesByCategory[category]
+		if !ok {
+			suite = &junitTestSuite{Name: category, Time: "0", Timestamp: timestamp}
+			suitesByCategory[category] = suite
+		}
+
+		location := finding.Location
+		if finding.Line > 0 {
+			location = fmt.Sprintf("%s:%d", location, finding.Line)
+		}
+
+		testCase := junitTestCase{
+			Name:      fmt.Sprintf("%s: %s", finding.ID, finding.Title),
+			ClassName: location,
+		}
+		if finding.Severity != Info {
+			testCase.Failure = &junitFailure{
+				Message: finding.Description,
+				Type:    string(finding.Severity),
+				Text:    fmt.Sprintf("%s\nLocation: %s\n%s", finding.Description, location, finding.Remediation),
+			}
+			suite.Failures++
+		}
+
+		suite.Tests++
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	// A scan without findings still yields a valid, empty suite
+	if len(suitesByCategory) == 0 {
+		suitesByCategory["Security Scan"] = &junitTestSuite{Name: "Security Scan", Time: "0", Timestamp: timestamp}
+	}
+
+	categories := make([]string, 0, len(suitesByCategory))
+	for category := range suitesByCategory {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	root := junitTestSuites{
+		Name:     report.ScanID,
+		Tests:    report.SummaryStats.TotalFindings,
+		Failures: report.SummaryStats.TotalFindings - report.SummaryStats.InfoCount,
+		Time:     seconds,
+	}
+	for _, category := range categories {
+		root.Suites = append(root.Suites, *suitesByCategory[category])
+	}
+
+	return root
+}
@@ -0,0 +1,73 @@
+package reporter
+
+import (
+	"encoding/xml"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+func TestJUnitShape(t *testing.T) {
+	findings := []models.Finding{
+		{ID: "F1", Title: "SQL injection", Description: "query built from input", Severity: High, Category: "Injection", Location: "db.go", Line: 12},
+		{ID: "F2", Title: "Debug print", Description: "stray print", Severity: Info, Category: "Injection", Location: "db.go"},
+		{ID: "F3", Title: "Weak hash", Description: "md5 in use", Severity: Medium, Location: "hash.go", Line: 3},
+	}
+	r := testReporter(t, "junit")
+	report := r.Build(findings, Config{}, ".", testTime.Add(-2500*time.Millisecond))
+	if err := r.Write(report); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	data, err := os.ReadFile(r.PathFor(report, "junit"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), xml.Header) {
+		t.Error("report does not start with the XML header")
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal(data, &suites); err != nil {
+		t.Fatalf("report is not valid XML: %v", err)
+	}
+
+	if suites.Name != "SCAN-TEST" || suites.Tests != 3 || suites.Failures != 2 || suites.Time != "2.500" {
+		t.Errorf("testsuites = %q tests=%d failures=%d time=%s, want SCAN-TEST 3 2 2.500",
+			suites.Name, suites.Tests, suites.Failures, suites.Time)
+	}
+	if len(suites.Suites) != 2 {
+		t.Fatalf("got %d suites, want 2", len(suites.Suites))
+	}
+
+	injection, uncategorized := suites.Suites[0], suites.Suites[1]
+	if injection.Name != "Injection" || injection.Tests != 2 || injection.Failures != 1 {
+		t.Errorf("first suite = %q tests=%d failures=%d, want Injection 2 1", injection.Name, injection.Tests, injection.Failures)
+	}
+	if injection.Timestamp != testTime.Format(time.RFC3339) {
+		t.Errorf("suite timestamp %q, want %q", injection.Timestamp, testTime.Format(time.RFC3339))
+	}
+	if uncategorized.Name != "Uncategorized" || uncategorized.Tests != 1 || uncategorized.Failures != 1 {
+		t.Errorf("second suite = %q tests=%d failures=%d, want Uncategorized 1 1", uncategorized.Name, uncategorized.Tests, uncategorized.Failures)
+	}
+
+	sqli := injection.Cases[0]
+	if sqli.Name != "F1: SQL injection" || sqli.ClassName != "db.go:12" {
+		t.Errorf("case = %q %q, want \"F1: SQL injection\" \"db.go:12\"", sqli.Name, sqli.ClassName)
+	}
+	if sqli.Failure == nil || sqli.Failure.Type != string(High) || sqli.Failure.Message != "query built from input" {
+		t.Errorf("case failure = %+v, want a HIGH failure", sqli.Failure)
+	}
+	if debug := injection.Cases[1]; debug.Failure != nil {
+		t.Errorf("info finding %q has a failure", debug.Name)
+	}
+}
+
+func TestJUnitEmptyReport(t *testing.T) {
+	suites := buildJUnit(Report{Timestamp: testTime})
+	if suites.Tests != 0 || len(suites.Suites) != 1 || suites.Suites[0].Name != "Security Scan" {
+		t.Errorf("empty report gave %+v, want a single empty Security Scan suite", suites)
+	}
+}
@@ -0,0 +1,37 @@
+package reporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// languageFindings returns findings whose snippets have an inferred, an
+// explicit and no language
+func languageFindings() []models.Finding {
+	return []models.Finding{
+		{ID: "PY", Title: "t", Severity: High, Location: "app.py", Line: 1, CodeSnippet: "eval(data)"},
+		{ID: "SET", Title: "t", Severity: High, Location: "template.txt", Line: 1, CodeSnippet: "{{ user }}", Language: "jinja"},
+		{ID: "NONE", Title: "t", Severity: High, Location: "notes.txt", Line: 1, CodeSnippet: "password: x"},
+	}
+}
+
+func TestMarkdownSnippetLanguage(t *testing.T) {
+	got := renderMarkdown(testReporter(t).Build(languageFindings(), Config{}, ".", testTime))
+	for _, want := range []string{"```python\n  eval(data)", "```jinja\n  {{ user }}", "```\n  password: x"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("markdown lacks %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestHTMLSnippetLanguage(t *testing.T) {
+	r := testReporter(t, "html")
+	got := renderHTML(t, r, r.Build(languageFindings(), Config{}, ".", testTime))
+	for _, want := range []string{`<code class="language-python">eval(data)</code>`, `<code class="language-jinja">{{ user }}</code>`, `<code>password: x</code>`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML lacks %q", want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// snippetLanguage returns the code block language of a finding, inferring
+// it from the location for findings recorded without one
+func snippetLanguage(finding models.Finding) string {
+	if finding.Language != "" {
+		return finding.Language
+	}
+	return models.Language(finding.Location)
+}
+
+// generateMarkdown creates a Markdown report suitable for PR comments
+func (r *Reporter) generateMarkdown(report Report, path string) error {
+	if err := os.WriteFile(path, []byte(renderMarkdown(report)), 0644); err != nil {
+		return fmt.Errorf("failed to write report file: %v", err)
+	}
+	return nil
+}
+
+// renderDeltaMarkdown renders the changes since a previous scan
+func renderDeltaMarkdown(b *strings.Builder, delta *Delta) {
+	b.WriteString("\n## Changes Since Previous Scan\n\n")
+	b.WriteString("| Severity | New | Fixed | Persisting |\n")
+	b.WriteString("|----------|-----|-------|------------|\n")
+	for _, c := range delta.Counts {
+		fmt.Fprintf(b, "| %s | %d | %d | %d |\n", c.Severity, c.New, c.Fixed, c.Persisting)
+	}
+	fmt.Fprintf(b, "| **Total** | **%d** | **%d** | **%d** |\n", delta.NewCount, delta.FixedCount, delta.PersistingCount)
+
+	if len(delta.New) == 0 {
+		return
+	}
+	b.WriteString("\n### New Findings\n\n")
+	for _, finding := range delta.New {
+		location := finding.Location
+		if finding.Line > 0 {
+			location = fmt.Sprintf("%s:%d", location, finding.Line)
+		}
+		fmt.Fprintf(b, "- %s: **%s** in `%s`\n", finding.Severity, finding.Title, location)
+	}
+}
+
+// renderMarkdown renders the report with findings grouped by severity and
+// sorted by location so repeated runs produce minimal diffs
+func renderMarkdown(report Report) string {
+	var b strings.Builder
+
+	b.WriteString("# Security Scan Report\n\n")
+	fmt.Fprintf(&b, "**Target:** `%s`  \n", report.Target)
+	fmt.Fprintf(&b, "**Scan ID:** %s  \n", report.ScanID)
+	fmt.Fprintf(&b, "**Risk Score:** %.1f\n\n", report.RiskScore)
+
+	stats := report.SummaryStats
+	b.WriteString("| Severity | Count |\n")
+	b.WriteString("|----------|-------|\n")
+	fmt.Fprintf(&b, "| Critical | %d |\n", stats.CriticalCount)
+	fmt.Fprintf(&b, "| High | %d |\n", stats.HighCount)
+	fmt.Fprintf(&b, "| Medium | %d |\n", stats.MediumCount)
+	fmt.Fprintf(&b, "| Low | %d |\n", stats.LowCount)
+	fmt.Fprintf(&b, "| Info | %d |\n", stats.InfoCount)
+	for _, custom := range stats.Custom() {
+		fmt.Fprintf(&b, "| %s | %d |\n", custom.Severity, custom.Count)
+	}
+	if stats.UnknownCount > 0 {
+		fmt.Fprintf(&b, "| Unknown | %d |\n", stats.UnknownCount)
+	}
+	fmt.Fprintf(&b, "| **Total** | **%d** |\n", stats.TotalFindings)
+	if report.Suppressed > 0 {
+		fmt.Fprintf(&b, "\n%d finding(s) suppressed by inline comments.\n", report.Suppressed)
+	}
+	if report.Allowlisted > 0 {
+		fmt.Fprintf(&b, "\n%d finding(s) accepted by the allowlist.\n", report.Allowlisted)
+	}
+	if report.Delta != nil {
+		renderDeltaMarkdown(&b, report.Delta)
+	}
+
+	bySeverity := make(map[models.Severity][]models.Finding)
+	for _, finding := range report.Findings {
+		bySeverity[finding.Severity] = append(bySeverity[finding.Severity], finding)
+	}
+
+	for _, severity := range models.Severities() {
+		findings := bySeverity[severity]
+		if len(findings) == 0 {
+			continue
+		}
+
+		sort.SliceStable(findings, func(i, j int) bool {
+			if findings[i].Location != findings[j].Location {
+				return findings[i].Location < findings[j].Location
+			}
+			if findings[i].Line != findings[j].Line {
+				return findings[i].Line < findings[j].Line
+			}
+			return findings[i].ID < findings[j].ID
+		})
+
+		fmt.Fprintf(&b, "\n## %s (%d)\n\n", severity, len(findings))
+		for _, finding := range findings {
+			location := finding.Location
+			if finding.Line > 0 {
+				location = fmt.Sprintf("%s:%d", location, finding.Line)
+			}
+
+			fmt.Fprintf(&b, "- **%s** (`%s`) in `%s`\n", finding.Title, finding.ID, location)
+			if finding.Description != "" {
+				fmt.Fprintf(&b, "  %s\n", finding.Description)
+			}
+			if len(finding.Context) > 0 {
+				// Mark the matched line so it stands out in the block
+				fmt.Fprintf(&b, "\n  ```%s\n", snippetLanguage(finding))
+				for _, line := range finding.Context {
+					marker := " "
+					if line.Match {
+						marker = ">"
+					}
+					fmt.Fprintf(&b, "  %s %4d | %s\n", marker, line.Line, line.Text)
+				}
+				b.WriteString("  ```\n")
+			} else if finding.CodeSnippet != "" {
+				fmt.Fprintf(&b, "\n  ```%s\n", snippetLanguage(finding))
+				for _, line := range strings.Split(finding.CodeSnippet, "\n") {
+					fmt.Fprintf(&b, "  %s\n", line)
+				}
+				b.WriteString("  ```\n")
+			}
+			if finding.Remediation != "" {
+				fmt.Fprintf(&b, "\n  *Remediation:* %s\n", finding.Remediation)
+			}
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package reporter
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// update rewrites golden files with the current output
+var update = flag.Bool("update", false, "update golden files")
+
+// golden compares got with the named file in testdata, rewriting the file
+// instead when -update is set
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s; rerun with -update and review the diff\ngot:\n%s", path, got)
+	}
+}
+
+// sampleFindings is a small mix of severities, files and languages
+func sampleFindings() []models.Finding {
+	return []models.Finding{
+		{ID: "F1", RuleID: "SQLI", Title: "SQL injection", Description: "query built from input", Severity: High, Category: "Injection", Location: "src/db.go", Line: 12, CodeSnippet: `db.Query("SELECT " + name)`, Remediation: "Use parameterized queries"},
+		{ID: "F2", RuleID: "SECRET", Title: "Hardcoded secret", Description: "credential in source", Severity: Critical, Category: "Secrets", Location: "config.py", Line: 3, CodeSnippet: `token = "abc123"`},
+		{ID: "F3", RuleID: "MD5", Title: "Weak hash", Description: "md5 in use", Severity: Medium, Category: "Crypto", Location: "src/hash.go", Line: 7},
+		{ID: "F4", RuleID: "TODO", Title: "Security TODO", Description: "unresolved note", Severity: Info, Category: "Hygiene", Location: "src/db.go", Line: 2},
+	}
+}
+
+func TestMarkdownGolden(t *testing.T) {
+	r := testReporter(t, "md")
+	report := r.Build(sampleFindings(), Config{}, "./project", testTime)
+	golden(t, "report.md", renderMarkdown(report))
+}
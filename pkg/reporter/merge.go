@@ -0,0 +1,124 @@
+package reporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// MergeOptions controls how reports are combined
+type MergeOptions struct {
+	// Dedup keeps only the first finding with each fingerprint across the
+	// reports. Findings without a fingerprint are always kept.
+	Dedup bool
+}
+
+// Merge combines the reports of separate scans into one report, keeping
+// every finding. See MergeWith.
+func Merge(reports ...Report) Report {
+	return MergeWith(MergeOptions{}, reports...)
+}
+
+// MergeWith combines the reports of separate scans into one report. Findings
+// are concatenated in order and the statistics, rule coverage, per-file
+// summaries and risk score recomputed. The merged report takes the earliest
+// timestamp, the longest duration and the union of the rules used; the
+// remaining scanner configuration comes from the first report.
+func MergeWith(options MergeOptions, reports ...Report) Report {
+	if len(reports) == 0 {
+		return Report{Findings: []models.Finding{}}
+	}
+
+	merged := Report{
+		Timestamp:     reports[0].Timestamp,
+		ScannerConfig: reports[0].ScannerConfig,
+	}
+
+	var (
+		findings []models.Finding
+		seen     = make(map[string]bool)
+		rules    = make(map[string]bool)
+		targets  []string
+		scanIDs  []string
+		duration time.Duration
+		longest  string
+	)
+	for _, report := range reports {
+		for _, finding := range report.Findings {
+			// Reports written by older versions may hold aliased severities
+			finding.Severity = finding.Severity.Canonical()
+			if options.Dedup && finding.Fingerprint != "" {
+				if seen[finding.Fingerprint] {
+					continue
+				}
+				seen[finding.Fingerprint] = true
+			}
+			findings = append(findings, finding)
+		}
+
+		for _, ruleID := range report.ScannerConfig.RulesUsed {
+			rules[ruleID] = true
+		}
+		merged.ScannerConfig.AIEnabled = merged.ScannerConfig.AIEnabled || report.ScannerConfig.AIEnabled
+		if report.ScannerConfig.TimeoutSecs > merged.ScannerConfig.TimeoutSecs {
+			merged.ScannerConfig.TimeoutSecs = report.ScannerConfig.TimeoutSecs
+		}
+
+		if !report.Timestamp.IsZero() && (merged.Timestamp.IsZero() || report.Timestamp.Before(merged.Timestamp)) {
+			merged.Timestamp = report.Timestamp
+		}
+		if d, err := time.ParseDuration(report.ScanDuration); err == nil && (longest == "" || d > duration) {
+			duration, longest = d, report.ScanDuration
+		}
+
+		if report.Target != "" {
+			targets = append(targets, report.Target)
+		}
+		scanIDs = append(scanIDs, report.ScanID)
+		merged.Suppressed += report.Suppressed
+		merged.Allowlisted += report.Allowlisted
+
+		if report.Baseline != nil {
+			if merged.Baseline == nil {
+				merged.Baseline = &BaselineSummary{}
+			}
+			merged.Baseline.NewCount += report.Baseline.NewCount
+			merged.Baseline.FixedCount += report.Baseline.FixedCount
+			merged.Baseline.UnchangedCount += report.Baseline.UnchangedCount
+		}
+	}
+
+	if findings == nil {
+		findings = []models.Finding{}
+	}
+
+	merged.ScannerConfig.RulesUsed = make([]string, 0, len(rules))
+	for ruleID := range rules {
+		merged.ScannerConfig.RulesUsed = append(merged.ScannerConfig.RulesUsed, ruleID)
+	}
+	sort.Strings(merged.ScannerConfig.RulesUsed)
+
+	for _, finding := range findings {
+		merged.SummaryStats.add(finding)
+	}
+
+	merged.ScanID = mergedScanID(scanIDs)
+	merged.Target = strings.Join(targets, ", ")
+	merged.Findings = findings
+	merged.ScanDuration = longest
+	merged.RuleCoverage = ruleCoverage(findings, merged.ScannerConfig)
+	merged.ByFile = byFile(findings)
+	merged.RiskScore = riskScore(merged.SummaryStats, merged.ScannerConfig)
+
+	return merged
+}
+
+// mergedScanID derives the ID of a merged report from the IDs of its parts
+func mergedScanID(scanIDs []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(scanIDs, "\x00")))
+	return "SCAN-" + hex.EncodeToString(sum[:8])
+}
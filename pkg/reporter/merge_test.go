@@ -0,0 +1,91 @@
+package reporter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// mergeInputs returns two reports sharing a fingerprinted finding
+func mergeInputs(t *testing.T) (Report, Report) {
+	t.Helper()
+	r := testReporter(t)
+	first := r.Build([]models.Finding{
+		{ID: "A1", RuleID: "SQLI", Severity: High, Location: "a/db.go", Fingerprint: "fp-shared"},
+		{ID: "A2", RuleID: "MD5", Severity: Medium, Location: "a/hash.go"},
+	}, Config{RulesUsed: []string{"SQLI", "MD5"}, TimeoutSecs: 30}, "a", testTime)
+	first.ScanDuration = "2s"
+	first.Suppressed = 1
+
+	second := r.Build([]models.Finding{
+		{ID: "B1", RuleID: "SQLI", Severity: High, Location: "a/db.go", Fingerprint: "fp-shared"},
+		{ID: "B2", RuleID: "SECRET", Severity: Critical, Location: "b/config.py", Fingerprint: "fp-secret"},
+		{ID: "B3", RuleID: "MD5", Severity: Medium, Location: "b/hash.go"},
+	}, Config{RulesUsed: []string{"SECRET", "SQLI"}, TimeoutSecs: 60, AIEnabled: true}, "b", testTime)
+	second.Timestamp = testTime.Add(-time.Hour)
+	second.ScanDuration = "5s"
+	second.Allowlisted = 2
+	return first, second
+}
+
+// findingIDs returns the IDs of findings in order
+func findingIDs(findings []models.Finding) []string {
+	var ids []string
+	for _, finding := range findings {
+		ids = append(ids, finding.ID)
+	}
+	return ids
+}
+
+func TestMerge(t *testing.T) {
+	first, second := mergeInputs(t)
+	merged := Merge(first, second)
+
+	if got, want := findingIDs(merged.Findings), []string{"A1", "A2", "B1", "B2", "B3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("findings %v, want %v", got, want)
+	}
+	stats := merged.SummaryStats
+	if stats.TotalFindings != 5 || stats.CriticalCount != 1 || stats.HighCount != 2 || stats.MediumCount != 2 {
+		t.Errorf("stats %+v, want 5 findings: 1 critical, 2 high, 2 medium", stats)
+	}
+	if want := []string{"MD5", "SECRET", "SQLI"}; !reflect.DeepEqual(merged.ScannerConfig.RulesUsed, want) {
+		t.Errorf("rules used %v, want the union %v", merged.ScannerConfig.RulesUsed, want)
+	}
+	if !merged.ScannerConfig.AIEnabled || merged.ScannerConfig.TimeoutSecs != 60 {
+		t.Errorf("config %+v, want AI enabled and the longest timeout", merged.ScannerConfig)
+	}
+	if !merged.Timestamp.Equal(testTime.Add(-time.Hour)) || merged.ScanDuration != "5s" {
+		t.Errorf("timestamp %v and duration %s, want the earliest and longest", merged.Timestamp, merged.ScanDuration)
+	}
+	if merged.Target != "a, b" || merged.Suppressed != 1 || merged.Allowlisted != 2 {
+		t.Errorf("target %q, suppressed %d, allowlisted %d", merged.Target, merged.Suppressed, merged.Allowlisted)
+	}
+	if len(merged.ByFile) != 4 {
+		t.Errorf("got %d files, want 4", len(merged.ByFile))
+	}
+	if merged.ScanID == first.ScanID || merged.ScanID != Merge(first, second).ScanID {
+		t.Errorf("scan ID %q, want a stable ID of its own", merged.ScanID)
+	}
+}
+
+func TestMergeDedup(t *testing.T) {
+	first, second := mergeInputs(t)
+	merged := MergeWith(MergeOptions{Dedup: true}, first, second)
+
+	// B1 repeats A1's fingerprint; the unfingerprinted MD5 findings stay
+	if got, want := findingIDs(merged.Findings), []string{"A1", "A2", "B2", "B3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("findings %v, want %v", got, want)
+	}
+	if merged.SummaryStats.TotalFindings != 4 || merged.SummaryStats.HighCount != 1 {
+		t.Errorf("stats %+v, want counts after deduplication", merged.SummaryStats)
+	}
+}
+
+func TestMergeNothing(t *testing.T) {
+	merged := Merge()
+	if merged.Findings == nil || len(merged.Findings) != 0 {
+		t.Errorf("findings %v, want an empty list", merged.Findings)
+	}
+}
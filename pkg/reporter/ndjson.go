@@ -0,0 +1,124 @@
+package reporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// ndjsonHeader is the first line of an NDJSON report
+type ndjsonHeader struct {
+	ScanID        string           `json:"scanId"`
+	Timestamp     time.Time        `json:"timestamp"`
+	Target        string           `json:"target"`
+	SummaryStats  Stats            `json:"summaryStats"`
+	RiskScore     float64          `json:"riskScore"`
+	ScanDuration  string           `json:"scanDuration"`
+	ScannerConfig Config           `json:"scannerConfig"`
+	Baseline      *BaselineSummary `json:"baseline,omitempty"`
+	Delta         *Delta           `json:"delta,omitempty"`
+	Suppressed    int              `json:"suppressed"`
+	Allowlisted   int              `json:"allowlisted"`
+}
+
+// newNDJSONHeader extracts the header fields of a report
+func newNDJSONHeader(report Report) ndjsonHeader {
+	return ndjsonHeader{
+		ScanID:        report.ScanID,
+		Timestamp:     report.Timestamp,
+		Target:        report.Target,
+		SummaryStats:  report.SummaryStats,
+		RiskScore:     report.RiskScore,
+		ScanDuration:  report.ScanDuration,
+		ScannerConfig: report.ScannerConfig,
+		Baseline:      report.Baseline,
+		Delta:         report.Delta,
+		Suppressed:    report.Suppressed,
+		Allowlisted:   report.Allowlisted,
+	}
+}
+
+// generateNDJSON creates a JSON Lines report: a header line followed by one
+// line per finding
+func (r *Reporter) generateNDJSON(report Report, path string) error {
+	file, err := createReportFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %v", err)
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(newNDJSONHeader(report)); err != nil {
+		return fmt.Errorf("failed to encode report: %v", err)
+	}
+	for _, finding := range report.Findings {
+		if err := encoder.Encode(finding); err != nil {
+			return fmt.Errorf("failed to encode finding: %v", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write report: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write report: %v", err)
+	}
+	return nil
+}
+
+// streamNDJSON writes a JSON Lines report from a channel. Findings are
+// spooled to a temporary file until the header statistics are known.
+func (r *Reporter) streamNDJSON(path string, report Report, findings <-chan models.Finding, duration time.Time) error {
+	spool, err := os.CreateTemp(filepath.Dir(path), ".findings-*.ndjson")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	w := bufio.NewWriter(spool)
+	encoder := json.NewEncoder(w)
+	for finding := range findings {
+		finding.Severity = finding.Severity.Canonical()
+		if !r.keep(finding) {
+			continue
+		}
+		finding = r.redact(finding)
+		if err := encoder.Encode(finding); err != nil {
+			return fmt.Errorf("failed to encode finding: %v", err)
+		}
+		report.count(finding)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write temporary file: %v", err)
+	}
+	report.finish(r.now().Sub(duration))
+
+	file, err := createReportFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %v", err)
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(newNDJSONHeader(report)); err != nil {
+		return fmt.Errorf("failed to encode report: %v", err)
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read temporary file: %v", err)
+	}
+	if _, err := io.Copy(file, spool); err != nil {
+		return fmt.Errorf("failed to write report: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write report: %v", err)
+	}
+
+	return nil
+}
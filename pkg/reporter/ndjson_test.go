@@ -0,0 +1,87 @@
+package reporter
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// readNDJSON decodes an NDJSON report into its header and findings
+func readNDJSON(t *testing.T, path string) (ndjsonHeader, []models.Finding) {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	var header ndjsonHeader
+	if err := decoder.Decode(&header); err != nil {
+		t.Fatalf("decoding header: %v", err)
+	}
+	var findings []models.Finding
+	for {
+		var finding models.Finding
+		err := decoder.Decode(&finding)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("decoding finding %d: %v", len(findings)+1, err)
+		}
+		findings = append(findings, finding)
+	}
+	return header, findings
+}
+
+func TestNDJSONReadsBack(t *testing.T) {
+	start := testTime.Add(-3 * time.Second)
+	findings := sampleFindings()
+
+	tests := []struct {
+		name     string
+		generate func(r *Reporter) error
+	}{
+		{"generate", func(r *Reporter) error {
+			return r.Generate(findings, Config{}, ".", start)
+		}},
+		{"stream", func(r *Reporter) error {
+			return r.GenerateStream(send(findings), Config{}, ".", start)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testReporter(t, "ndjson")
+			if err := tt.generate(r); err != nil {
+				t.Fatal(err)
+			}
+
+			header, got := readNDJSON(t, r.BasePath+".ndjson")
+			if header.ScanID != "SCAN-TEST" || !header.Timestamp.Equal(testTime) {
+				t.Errorf("header %s at %v, want SCAN-TEST at %v", header.ScanID, header.Timestamp, testTime)
+			}
+			if header.ScanDuration != "3s" {
+				t.Errorf("scan duration %q, want 3s", header.ScanDuration)
+			}
+			if header.SummaryStats.TotalFindings != len(findings) {
+				t.Errorf("header counts %d findings, want %d", header.SummaryStats.TotalFindings, len(findings))
+			}
+			if len(got) != len(findings) {
+				t.Fatalf("read %d finding lines, want %d", len(got), len(findings))
+			}
+			for i, finding := range got {
+				if finding.ID != findings[i].ID || finding.Location != findings[i].Location || finding.Line != findings[i].Line {
+					t.Errorf("line %d is %s at %s:%d, want %s at %s:%d", i+2,
+						finding.ID, finding.Location, finding.Line,
+						findings[i].ID, findings[i].Location, findings[i].Line)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package reporter
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// RedactMode selects which findings have secret values masked in reports
+type RedactMode int
+
+const (
+	// RedactNone leaves snippets intact
+	RedactNone RedactMode = iota
+	// RedactSecrets masks values in the snippets of secret findings
+	RedactSecrets
+	// RedactAll masks values in the snippets of every finding
+	RedactAll
+)
+
+// secretCategory is the category of findings reporting hardcoded secrets
+const secretCategory = "secret"
+
+var (
+	// quotedValue matches string literals long enough to hold a secret
+	quotedValue = regexp.MustCompile("([\"'`])([^\"'`]{6,})([\"'`])")
+	// assignedValue matches unquoted values assigned with = or :
+	assignedValue = regexp.MustCompile(`([=:]\s*)([^\s"'` + "`" + `,;)]{6,})`)
+	// tokenValue matches long unbroken tokens such as keys and hashes
+	tokenValue = regexp.MustCompile(`[A-Za-z0-9+/_\-]{16,}={0,2}`)
+)
+
+// redact returns the finding with secret values masked in its snippet and
+// context, according to the reporter's mode
+func (r *Reporter) redact(finding models.Finding) models.Finding {
+	switch r.Redact {
+	case RedactSecrets:
+		if !strings.EqualFold(finding.Category, secretCategory) {
+			return finding
+		}
+	case RedactAll:
+	default:
+		return finding
+	}
+
+	finding.CodeSnippet = MaskSecrets(finding.CodeSnippet)
+	if len(finding.Context) > 0 {
+		context := make([]models.ContextLine, len(finding.Context))
+		for i, line := range finding.Context {
+			line.Text = MaskSecrets(line.Text)
+			context[i] = line
+		}
+		finding.Context = context
+	}
+	return finding
+}
+
+// redactAll applies the reporter's redaction to findings, returning a copy
+// when anything is redacted
+func (r *Reporter) redactAll(findings []models.Finding) []models.Finding {
+	if r.Redact == RedactNone {
+		return findings
+	}
+
+	redacted := make([]models.Finding, len(findings))
+	for i, finding := range findings {
+		redacted[i] = r.redact(finding)
+	}
+	return redacted
+}
+
+// MaskSecrets masks the values that may be secrets in a line of code:
+// string literals, assigned values and long tokens. Values of 12 characters
+// or more keep their first and last 4 characters; shorter values are masked
+// entirely.
+func MaskSecrets(s string) string {
+	s = quotedValue.ReplaceAllStringFunc(s, func(m string) string {
+		parts := quotedValue.FindStringSubmatch(m)
+		if parts[1] != parts[3] {
+			return m
+		}
+		return parts[1] + mask(parts[2]) + parts[3]
+	})
+	s = assignedValue.ReplaceAllStringFunc(s, func(m string) string {
+		parts := assignedValue.FindStringSubmatch(m)
+		return parts[1] + mask(parts[2])
+	})
+	return tokenValue.ReplaceAllStringFunc(s, mask)
+}
+
+// mask hides the middle of a value, leaving masked values untouched
+func mask(value string) string {
+	if strings.Contains(value, "***") {
+		return value
+	}
+	if len(value) < 12 {
+		return "***"
+	}
+	return value[:4] + "***" + value[len(value)-4:]
+}
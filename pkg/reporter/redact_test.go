@@ -0,0 +1,54 @@
+package reporter
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+func TestEveryFormatMasksSecrets(t *testing.T) {
+	const secret = "sk_live_0123456789abcdef"
+	findings := []models.Finding{{
+		ID: "F1", RuleID: "KEY", Title: "API key", Severity: High, Category: "Secret",
+		Location: "app.go", Line: 3, CodeSnippet: `key := "` + secret + `"`,
+		Context: []models.ContextLine{{Line: 3, Text: `key := "` + secret + `"`}},
+	}}
+
+	// Each format gets its own reporter since json and gitlab share an
+	// extension
+	outputs := make(map[string]string)
+	var report Report
+	for _, format := range []string{"json", "html", "junit", "md", "gitlab", "ndjson"} {
+		r := testReporter(t, format)
+		r.Redact = RedactSecrets
+		report = r.Build(findings, Config{}, ".", testTime)
+		if err := r.Write(report); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(r.PathFor(report, format))
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs[format] = string(data)
+	}
+	for format, write := range map[string]func(io.Writer, Report) error{"text": writeText, "github": writeGitHub} {
+		var buf bytes.Buffer
+		if err := write(&buf, report); err != nil {
+			t.Fatal(err)
+		}
+		outputs[format] = buf.String()
+	}
+
+	for format, output := range outputs {
+		if strings.Contains(output, secret) {
+			t.Errorf("%s report contains the unmasked secret", format)
+		}
+	}
+	if !strings.Contains(outputs["json"], "sk_l***cdef") {
+		t.Errorf("json report lacks the masked secret:\n%s", outputs["json"])
+	}
+}
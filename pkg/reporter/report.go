@@ -1,10 +1,17 @@
 package reporter
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/SofNam/devsecops-ai/pkg/models"
@@ -14,11 +21,11 @@ import (
 type Severity string
 
 const (
-	Critical models.Severity = "CRITICAL"
-	High     models.Severity = "HIGH"
-	Medium   models.Severity = "MEDIUM"
-	Low      models.Severity = "LOW"
-	Info     models.Severity = "INFO"
+	Critical = models.SeverityCritical
+	High     = models.SeverityHigh
+	Medium   = models.SeverityMedium
+	Low      = models.SeverityLow
+	Info     = models.SeverityInfo
 )
 
 // Finding represents a security finding
@@ -41,8 +48,32 @@ type Report struct {
 	Target        string           `json:"target"`
 	Findings      []models.Finding `json:"findings"`
 	SummaryStats  Stats            `json:"summaryStats"`
+	RuleCoverage  map[string]int   `json:"ruleCoverage"`
+	ByFile        []FileSummary    `json:"byFile"`
+	RiskScore     float64          `json:"riskScore"`
 	ScanDuration  string           `json:"scanDuration"`
 	ScannerConfig Config           `json:"scannerConfig"`
+	Baseline      *BaselineSummary `json:"baseline,omitempty"`
+	Delta         *Delta           `json:"delta,omitempty"`
+	Suppressed    int              `json:"suppressed"`
+	Allowlisted   int              `json:"allowlisted"`
+
+	// files accumulates ByFile while findings are streamed
+	files fileTally
+}
+
+// FileSummary counts the findings in one file
+type FileSummary struct {
+	Path        string          `json:"path"`
+	Count       int             `json:"count"`
+	MaxSeverity models.Severity `json:"maxSeverity"`
+}
+
+// BaselineSummary represents the comparison against a baseline report
+type BaselineSummary struct {
+	NewCount       int `json:"newCount"`
+	FixedCount     int `json:"fixedCount"`
+	UnchangedCount int `json:"unchangedCount"`
 }
 
 // Stats represents statistical information about the findings
@@ -53,6 +84,28 @@ type Stats struct {
 	MediumCount   int `json:"mediumCount"`
 	LowCount      int `json:"lowCount"`
 	InfoCount     int `json:"infoCount"`
+	// UnknownCount counts findings whose severity is not recognized
+	UnknownCount int `json:"unknownCount"`
+	// CustomCounts counts findings of severities registered beyond the
+	// built-in five
+	CustomCounts map[models.Severity]int `json:"customCounts,omitempty"`
+}
+
+// SeverityCount is the number of findings at one severity
+type SeverityCount struct {
+	Severity models.Severity
+	Count    int
+}
+
+// Custom returns the counts of registered severities, most severe first
+func (s Stats) Custom() []SeverityCount {
+	var counts []SeverityCount
+	for _, severity := range models.Severities() {
+		if count, ok := s.CustomCounts[severity]; ok {
+			counts = append(counts, SeverityCount{Severity: severity, Count: count})
+		}
+	}
+	return counts
 }
 
 // Config represents scanner configuration
@@ -62,33 +115,138 @@ type Config struct {
 	ScanType    string   `json:"scanType"`
 	AIEnabled   bool     `json:"aiEnabled"`
 	TimeoutSecs int      `json:"timeoutSecs"`
+
+	// RiskWeights weights each severity's count in the risk score,
+	// defaulting to DefaultRiskWeights
+	RiskWeights map[models.Severity]float64 `json:"riskWeights,omitempty"`
+	// RiskCap caps the risk score when positive
+	RiskCap float64 `json:"riskCap,omitempty"`
+}
+
+// DefaultRiskWeights are the per-finding risk contributions of each severity
+var DefaultRiskWeights = map[models.Severity]float64{
+	Critical: 10,
+	High:     5,
+	Medium:   2,
+	Low:      1,
+	Info:     0,
 }
 
 // Reporter handles report generation
 type Reporter struct {
-	OutputFormat string
-	OutputPath   string
+	// Formats are the report formats to generate, each written to BasePath
+	// with the format's file extension
+	Formats     []string
+	BasePath    string
+	Baseline    *BaselineSummary
+	Delta       *Delta
+	Suppressed  int
+	Allowlisted int
+
+	// TemplatePath, when set, is an html/template file used for HTML
+	// reports instead of the built-in template
+	TemplatePath string
+
+	// Filters drop findings before the report and its statistics are built
+	Filters []FilterFunc
+
+	// Redact masks secret values in snippets and context before output
+	Redact RedactMode
+
+	// Compress gzips JSON and NDJSON reports, adding ".gz" to their paths
+	Compress bool
+
+	// Clock supplies the times recorded in reports, defaulting to time.Now.
+	// Pinning it, along with ScanIDFunc, makes reports of identical input
+	// byte-identical.
+	Clock func() time.Time
+
+	// ScanIDFunc, when set, supplies the report ScanID instead of the
+	// timestamp-based default
+	ScanIDFunc func() string
 }
 
-// NewReporter creates a new reporter instance
-func New(format, path string) *Reporter {
+// New creates a reporter generating each format to basePath plus the
+// format's file extension
+func New(formats []string, basePath string) *Reporter {
 	return &Reporter{
-		OutputFormat: format,
-		OutputPath:   path,
+		Formats:  formats,
+		BasePath: basePath,
 	}
 }
 
 // Generate creates a report in the specified format
 func (r *Reporter) Generate(findings []models.Finding, config Config, target string, duration time.Time) error {
-	report := r.createReport(findings, config, target, duration)
+	return r.Write(r.Build(findings, config, target, duration))
+}
+
+// Build assembles the report for findings, applying the reporter filters,
+// without writing it
+func (r *Reporter) Build(findings []models.Finding, config Config, target string, duration time.Time) Report {
+	// Canonical severities let filters, stats and every format compare
+	// them directly
+	findings = models.NormalizeSeverities(findings)
+	return r.createReport(r.redactAll(r.filter(findings)), config, target, duration)
+}
+
+// Write outputs a built report in every format concurrently. A failing
+// format does not stop the others; the returned error joins the failures,
+// each naming its format.
+func (r *Reporter) Write(report Report) error {
+	errs := make([]error, len(r.Formats))
+
+	var wg sync.WaitGroup
+	for i, format := range r.Formats {
+		wg.Add(1)
+		go func(i int, format string) {
+			defer wg.Done()
+			if err := r.writeFormat(report, format); err != nil {
+				errs[i] = fmt.Errorf("%s report: %w", format, err)
+			}
+		}(i, format)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// writeFormat outputs a built report in a single format
+func (r *Reporter) writeFormat(report Report, format string) error {
+	path := r.PathFor(report, format)
 
-	switch r.OutputFormat {
+	switch format {
 	case "json":
-		return r.generateJSON(report)
+		return r.generateJSON(report, path)
 	case "html":
-		return r.generateHTML(report)
+		return r.generateHTML(report, path)
+	case "junit":
+		return r.generateJUnit(report, path)
+	case "md":
+		return r.generateMarkdown(report, path)
+	case "gitlab":
+		return r.generateGitLab(report, path)
+	case "github":
+		return r.generateGitHub(report, path)
+	case "ndjson":
+		return r.generateNDJSON(report, path)
+	case "text":
+		return r.generateText(report, path)
 	default:
-		return fmt.Errorf("unsupported format: %s", r.OutputFormat)
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// FileExtension returns the file extension used for reports in the given format
+func FileExtension(format string) string {
+	switch format {
+	case "junit":
+		return "xml"
+	case "gitlab":
+		return "json"
+	case "text":
+		return "txt"
+	default:
+		return format
 	}
 }
 
@@ -97,13 +255,48 @@ func (r *Reporter) createReport(findings []models.Finding, config Config, target
 	stats := r.calculateStats(findings)
 
 	return Report{
-		ScanID:        fmt.Sprintf("SCAN-%d", time.Now().Unix()),
-		Timestamp:     time.Now(),
+		ScanID:        r.scanID(),
+		Timestamp:     r.now(),
 		Target:        target,
 		Findings:      findings,
 		SummaryStats:  stats,
-		ScanDuration:  time.Since(duration).String(),
+		RiskScore:     riskScore(stats, config),
+		RuleCoverage:  ruleCoverage(findings, config),
+		ByFile:        byFile(findings),
+		ScanDuration:  r.now().Sub(duration).String(),
 		ScannerConfig: config,
+		Baseline:      r.Baseline,
+		Delta:         r.Delta,
+		Suppressed:    r.Suppressed,
+		Allowlisted:   r.Allowlisted,
+	}
+}
+
+// scanID returns the ScanID for a new report
+func (r *Reporter) scanID() string {
+	if r.ScanIDFunc != nil {
+		return r.ScanIDFunc()
+	}
+	return fmt.Sprintf("SCAN-%d", r.now().Unix())
+}
+
+// now returns the current time from the reporter's clock
+func (r *Reporter) now() time.Time {
+	if r.Clock != nil {
+		return r.Clock()
+	}
+	return time.Now()
+}
+
+// DeterministicScanID returns a ScanIDFunc deriving the ID from the target
+// path and a seed such as a git commit, so identical inputs share an ID
+func DeterministicScanID(target, seed string) func() string {
+	return func() string {
+		if abs, err := filepath.Abs(target); err == nil {
+			target = abs
+		}
+		sum := sha256.Sum256([]byte(target + "\x00" + seed))
+		return "SCAN-" + hex.EncodeToString(sum[:8])
 	}
 }
 
@@ -112,27 +305,139 @@ func (r *Reporter) calculateStats(findings []models.Finding) Stats {
 	stats := Stats{}
 
 	for _, finding := range findings {
-		stats.TotalFindings++
-		switch finding.Severity {
-		case Critical:
-			stats.CriticalCount++
-		case High:
-			stats.HighCount++
-		case Medium:
-			stats.MediumCount++
-		case Low:
-			stats.LowCount++
-		case Info:
-			stats.InfoCount++
-		}
+		stats.add(finding)
 	}
 
 	return stats
 }
 
+// add counts a single finding by its canonical severity
+func (s *Stats) add(finding models.Finding) {
+	s.TotalFindings++
+
+	severity := finding.Severity
+	if !severity.Valid() {
+		s.UnknownCount++
+		return
+	}
+
+	switch severity {
+	case Critical:
+		s.CriticalCount++
+	case High:
+		s.HighCount++
+	case Medium:
+		s.MediumCount++
+	case Low:
+		s.LowCount++
+	case Info:
+		s.InfoCount++
+	default:
+		if s.CustomCounts == nil {
+			s.CustomCounts = make(map[models.Severity]int)
+		}
+		s.CustomCounts[severity]++
+	}
+}
+
+// ruleCoverage counts the findings produced by each rule, including zero
+// counts for rules in use that never fired
+func ruleCoverage(findings []models.Finding, config Config) map[string]int {
+	coverage := make(map[string]int, len(config.RulesUsed))
+	for _, ruleID := range config.RulesUsed {
+		coverage[ruleID] = 0
+	}
+	for _, finding := range findings {
+		if finding.RuleID != "" {
+			coverage[finding.RuleID]++
+		}
+	}
+	return coverage
+}
+
+// fileTally accumulates per-file summaries keyed by path
+type fileTally map[string]*FileSummary
+
+// add counts a finding against its file, keeping the most severe severity
+func (t fileTally) add(finding models.Finding) {
+	severity := finding.Severity
+
+	summary, ok := t[finding.Location]
+	if !ok {
+		t[finding.Location] = &FileSummary{Path: finding.Location, Count: 1, MaxSeverity: severity}
+		return
+	}
+	summary.Count++
+	if severity.Rank() < summary.MaxSeverity.Rank() {
+		summary.MaxSeverity = severity
+	}
+}
+
+// summaries returns the file summaries, worst first: by count, then by
+// severity, then by path
+func (t fileTally) summaries() []FileSummary {
+	summaries := make([]FileSummary, 0, len(t))
+	for _, summary := range t {
+		summaries = append(summaries, *summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if ra, rb := a.MaxSeverity.Rank(), b.MaxSeverity.Rank(); ra != rb {
+			return ra < rb
+		}
+		return a.Path < b.Path
+	})
+	return summaries
+}
+
+// byFile summarizes findings per file, worst files first
+func byFile(findings []models.Finding) []FileSummary {
+	tally := make(fileTally)
+	for _, finding := range findings {
+		tally.add(finding)
+	}
+	return tally.summaries()
+}
+
+// riskScore sums the severity counts weighted by the configured weights
+func riskScore(stats Stats, config Config) float64 {
+	weights := config.RiskWeights
+	if weights == nil {
+		weights = DefaultRiskWeights
+	}
+
+	score := float64(stats.CriticalCount)*weights[Critical] +
+		float64(stats.HighCount)*weights[High] +
+		float64(stats.MediumCount)*weights[Medium] +
+		float64(stats.LowCount)*weights[Low] +
+		float64(stats.InfoCount)*weights[Info]
+	for severity, count := range stats.CustomCounts {
+		score += float64(count) * weights[severity]
+	}
+
+	if config.RiskCap > 0 && score > config.RiskCap {
+		score = config.RiskCap
+	}
+	return score
+}
+
+// ExceedsThreshold reports whether any finding is at or above the threshold severity
+func ExceedsThreshold(findings []models.Finding, threshold models.Severity) bool {
+	for _, finding := range findings {
+		if finding.Severity.AtLeast(threshold) {
+			return true
+		}
+	}
+	return false
+}
+
 // generateJSON creates a JSON report
-func (r *Reporter) generateJSON(report Report) error {
-	file, err := os.Create(r.OutputPath)
+func (r *Reporter) generateJSON(report Report, path string) error {
+	file, err := createReportFile(path)
 	if err != nil {
 		return fmt.Errorf("failed to create report file: %v", err)
 	}
@@ -144,29 +449,68 @@ func (r *Reporter) generateJSON(report Report) error {
 		return fmt.Errorf("failed to encode report: %v", err)
 	}
 
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write report: %v", err)
+	}
 	return nil
 }
 
 // generateHTML creates an HTML report
-func (r *Reporter) generateHTML(report Report) error {
-	tmpl, err := template.New("report").Parse(htmlTemplate)
+func (r *Reporter) generateHTML(report Report, path string) error {
+	tmpl, err := r.htmlTemplate()
 	if err != nil {
-		return fmt.Errorf("failed to parse HTML template: %v", err)
+		return err
 	}
 
-	file, err := os.Create(r.OutputPath)
+	file, err := os.Create(path)
 	if err != nil {
 		return fmt.Errorf("failed to create report file: %v", err)
 	}
 	defer file.Close()
 
-	if err := tmpl.Execute(file, report); err != nil {
+	// Anchors are unique within a report, so findingAnchor is bound to the
+	// anchors of this one
+	data := newHTMLData(report)
+	tmpl.Funcs(template.FuncMap{"findingAnchor": data.anchor})
+
+	if err := tmpl.Execute(file, data); err != nil {
 		return fmt.Errorf("failed to generate HTML report: %v", err)
 	}
 
 	return nil
 }
 
+// templateFuncs are available to both the built-in and custom templates
+var templateFuncs = template.FuncMap{
+	"toLowerCase":      strings.ToLower,
+	"snippetLanguage":  snippetLanguage,
+	"findingAnchor":    findingAnchor,
+	"customSeverities": customSeverities,
+}
+
+// htmlTemplate parses the custom template if configured, otherwise the
+// built-in one
+func (r *Reporter) htmlTemplate() (*template.Template, error) {
+	if r.TemplatePath == "" {
+		tmpl, err := template.New("report").Funcs(templateFuncs).Parse(htmlTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse HTML template: %v", err)
+		}
+		return tmpl, nil
+	}
+
+	data, err := os.ReadFile(r.TemplatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HTML template: %v", err)
+	}
+
+	tmpl, err := template.New(filepath.Base(r.TemplatePath)).Funcs(templateFuncs).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML template %s: %v", r.TemplatePath, err)
+	}
+	return tmpl, nil
+}
+
 // HTML template for report generation
 const htmlTemplate = `
 <!DOCTYPE html>
@@ -196,6 +540,9 @@ const htmlTemplate = `
         .medium { border-left: 5px solid #ffc107; }
         .low { border-left: 5px solid #28a745; }
         .info { border-left: 5px solid #17a2b8; }
+        {{range customSeverities}}
+        .{{.Severity | printf "%s" | toLowerCase}} { border-left: 5px solid {{.Color}}; }
+        {{end}}
         .stats {
             display: grid;
             grid-template-columns: repeat(auto-fit, minmax(150px, 1fr));
@@ -208,12 +555,46 @@ const htmlTemplate = `
             border-radius: 5px;
             text-align: center;
         }
+        code .match {
+            background-color: #fff3cd;
+            font-weight: bold;
+        }
+        .group summary {
+            cursor: pointer;
+            font-size: 1.2em;
+            font-weight: bold;
+            margin: 10px 0;
+        }
+        .coverage {
+            border-collapse: collapse;
+            margin-bottom: 20px;
+        }
+        .coverage th, .coverage td {
+            border: 1px solid #ddd;
+            padding: 5px 10px;
+            text-align: left;
+        }
+        .dormant { color: #999; }
+        .metadata {
+            display: grid;
+            grid-template-columns: max-content auto;
+            gap: 2px 10px;
+        }
+        .metadata dt { font-weight: bold; }
+        .metadata dd { margin: 0; }
+        .toc ul {
+            list-style: none;
+            padding-left: 20px;
+        }
         code {
             background-color: #f8f9fa;
             padding: 10px;
             display: block;
             border-radius: 5px;
             margin: 10px 0;
+            font-family: monospace;
+            white-space: pre;
+            overflow-x: auto;
         }
     </style>
 </head>
@@ -224,9 +605,41 @@ const htmlTemplate = `
         <p>Target: {{.Target}}</p>
         <p>Timestamp: {{.Timestamp}}</p>
         <p>Duration: {{.ScanDuration}}</p>
+        {{if .Suppressed}}
+        <p>Suppressed: {{.Suppressed}}</p>
+        {{end}}
+        {{if .Allowlisted}}
+        <p>Allowlisted: {{.Allowlisted}}</p>
+        {{end}}
+        {{if .Baseline}}
+        <p>Baseline: {{.Baseline.NewCount}} new, {{.Baseline.FixedCount}} fixed, {{.Baseline.UnchangedCount}} unchanged</p>
+        {{end}}
     </div>
 
+    {{if .Delta}}
+    <h2>Changes Since Previous Scan</h2>
+    <table class="coverage">
+        <tr><th>Severity</th><th>New</th><th>Fixed</th><th>Persisting</th></tr>
+        {{range .Delta.Counts}}
+        <tr><td class="{{.Severity | printf "%s" | toLowerCase}}">{{.Severity}}</td><td>{{.New}}</td><td>{{.Fixed}}</td><td>{{.Persisting}}</td></tr>
+        {{end}}
+        <tr><th>Total</th><th>{{.Delta.NewCount}}</th><th>{{.Delta.FixedCount}}</th><th>{{.Delta.PersistingCount}}</th></tr>
+    </table>
+    {{if .Delta.New}}
+    <h3>New Findings</h3>
+    <ul>
+        {{range .Delta.New}}
+        <li>{{.Severity}}: {{.Title}} at {{.Location}}{{if gt .Line 0}}:{{.Line}}{{end}}</li>
+        {{end}}
+    </ul>
+    {{end}}
+    {{end}}
+
     <div class="stats">
+        <div class="stat-item">
+            <h3>Risk Score</h3>
+            <p>{{printf "%.1f" .RiskScore}}</p>
+        </div>
         <div class="stat-item">
             <h3>Total</h3>
             <p>{{.SummaryStats.TotalFindings}}</p>
@@ -251,24 +664,126 @@ const htmlTemplate = `
             <h3>Info</h3>
             <p>{{.SummaryStats.InfoCount}}</p>
         </div>
+        {{range .SummaryStats.Custom}}
+        <div class="stat-item">
+            <h3>{{.Severity}}</h3>
+            <p>{{.Count}}</p>
+        </div>
+        {{end}}
+        {{if .SummaryStats.UnknownCount}}
+        <div class="stat-item">
+            <h3>Unknown</h3>
+            <p>{{.SummaryStats.UnknownCount}}</p>
+        </div>
+        {{end}}
     </div>
 
-    <h2>Findings</h2>
-    {{range .Findings}}
-    <div class="finding {{.Severity | printf "%s" | toLowerCase}}">
-        <h3>{{.Title}}</h3>
-        <p><strong>Severity:</strong> {{.Severity}}</p>
-        <p><strong>Category:</strong> {{.Category}}</p>
-        <p><strong>Location:</strong> {{.Location}}</p>
-        <p>{{.Description}}</p>
-        {{if .CodeSnippet}}
-        <code>{{.CodeSnippet}}</code>
+    {{if .RuleCoverage}}
+    <h2>Rule Coverage</h2>
+    <table class="coverage">
+        <tr><th>Rule</th><th>Findings</th></tr>
+        {{range $rule, $count := .RuleCoverage}}
+        <tr{{if eq $count 0}} class="dormant"{{end}}><td>{{$rule}}</td><td>{{$count}}</td></tr>
         {{end}}
-        {{if .Remediation}}
-        <p><strong>Remediation:</strong> {{.Remediation}}</p>
+    </table>
+    {{end}}
+
+    {{if .ByFile}}
+    <h2>Files</h2>
+    <table class="coverage">
+        <tr><th>File</th><th>Findings</th><th>Max Severity</th></tr>
+        {{range .ByFile}}
+        <tr><td>{{.Path}}</td><td>{{.Count}}</td><td class="{{.MaxSeverity | printf "%s" | toLowerCase}}">{{.MaxSeverity}}</td></tr>
         {{end}}
-    </div>
+    </table>
     {{end}}
+
+    {{if .Contents}}
+    <h2>Contents</h2>
+    <nav class="toc">
+        {{range .Contents}}
+        <details>
+            <summary class="{{.Severity | printf "%s" | toLowerCase}}">{{if .Severity}}{{.Severity}}{{else}}UNKNOWN{{end}} ({{len .Findings}})</summary>
+            <ul>
+                {{range .Findings}}
+                <li><a href="#{{findingAnchor .}}">{{.Title}}</a> &mdash; {{.Location}}{{if gt .Line 0}}:{{.Line}}{{end}}</li>
+                {{end}}
+            </ul>
+        </details>
+        {{end}}
+    </nav>
+    {{end}}
+
+    <h2>Findings</h2>
+    <p class="filter">
+        <label for="severity-filter">Severity:</label>
+        <select id="severity-filter" onchange="filterSeverity(this.value)">
+            <option value="">All</option>
+            <option value="critical">Critical</option>
+            <option value="high">High</option>
+            <option value="medium">Medium</option>
+            <option value="low">Low</option>
+            <option value="info">Info</option>
+            {{range customSeverities}}
+            <option value="{{.Severity | printf "%s" | toLowerCase}}">{{.Severity}}</option>
+            {{end}}
+        </select>
+    </p>
+    {{range .Groups}}
+    <details class="group"{{if not .Collapsed}} open{{end}}>
+        <summary>{{.Category}} ({{len .Findings}})</summary>
+        {{range .Findings}}
+        <div class="finding {{.Severity | printf "%s" | toLowerCase}}" id="{{findingAnchor .}}" data-severity="{{.Severity | printf "%s" | toLowerCase}}">
+            <h3>{{.Title}}</h3>
+            <p><strong>Severity:</strong> {{.Severity}}</p>
+            <p><strong>Category:</strong> {{.Category}}</p>
+            {{if .CWE}}
+            <p><strong>CWE:</strong> {{.CWE}}</p>
+            {{end}}
+            {{if .OWASP}}
+            <p><strong>OWASP:</strong> {{.OWASP}}</p>
+            {{end}}
+            {{if .Metadata}}
+            <dl class="metadata">
+                {{range $key, $value := .Metadata}}
+                <dt>{{$key}}</dt><dd>{{$value}}</dd>
+                {{end}}
+            </dl>
+            {{end}}
+            <p><strong>Location:</strong> {{.Location}}{{if gt .Line 0}}:{{.Line}}{{end}}</p>
+            <p>{{.Description}}</p>
+            {{if .Context}}
+            <code{{with snippetLanguage .}} class="language-{{.}}"{{end}}>{{range .Context}}<span{{if .Match}} class="match"{{end}}>{{printf "%4d" .Line}}  {{.Text}}</span>
+{{end}}</code>
+            {{else if .CodeSnippet}}
+            <code{{with snippetLanguage .}} class="language-{{.}}"{{end}}>{{.CodeSnippet}}</code>
+            {{end}}
+            {{if .Remediation}}
+            <p><strong>Remediation:</strong> {{.Remediation}}</p>
+            {{end}}
+        </div>
+        {{end}}
+    </details>
+    {{end}}
+
+    <script>
+        function filterSeverity(severity) {
+            document.querySelectorAll('.finding').forEach(function (el) {
+                el.style.display = !severity || el.dataset.severity === severity ? '' : 'none';
+            });
+        }
+
+        // Open a collapsed group when a link targets one of its findings
+        function revealTarget() {
+            var target = location.hash && document.getElementById(location.hash.slice(1));
+            if (target && target.closest('details')) {
+                target.closest('details').open = true;
+                target.scrollIntoView();
+            }
+        }
+        window.addEventListener('hashchange', revealTarget);
+        revealTarget();
+    </script>
 </body>
 </html>
 `
@@ -0,0 +1,347 @@
+package reporter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// withSeverities returns one finding per severity
+func withSeverities(severities ...models.Severity) []models.Finding {
+	findings := make([]models.Finding, len(severities))
+	for i, severity := range severities {
+		findings[i] = models.Finding{ID: string(severity), Severity: severity}
+	}
+	return findings
+}
+
+// testTime is the instant reported by testReporter's clock
+var testTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// testReporter returns a reporter writing formats beside a temporary base
+// path, with a pinned clock and scan ID so its output is reproducible
+func testReporter(t *testing.T, formats ...string) *Reporter {
+	t.Helper()
+	r := New(formats, filepath.Join(t.TempDir(), "report"))
+	r.Clock = func() time.Time { return testTime }
+	r.ScanIDFunc = func() string { return "SCAN-TEST" }
+	return r
+}
+
+func TestExceedsThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		findings  []models.Finding
+		threshold models.Severity
+		want      bool
+	}{
+		{"no findings", nil, High, false},
+		{"just below", withSeverities(Medium, Low), High, false},
+		{"at threshold", withSeverities(Low, High), High, true},
+		{"above threshold", withSeverities(Critical), High, true},
+		{"critical threshold with high", withSeverities(High), Critical, false},
+		{"critical threshold with critical", withSeverities(Critical), Critical, true},
+		{"info threshold", withSeverities(Info), Info, true},
+		{"unknown severity", withSeverities("BOGUS"), Info, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExceedsThreshold(tt.findings, tt.threshold); got != tt.want {
+				t.Errorf("ExceedsThreshold = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildNormalizesSeverities(t *testing.T) {
+	r := testReporter(t)
+	r.Filters = []FilterFunc{MinSeverity(High)}
+	report := r.Build(withSeverities("crit", "high", "warning", "low", "BOGUS"), Config{}, ".", testTime)
+
+	var got []string
+	for _, finding := range report.Findings {
+		got = append(got, finding.ID+"="+string(finding.Severity))
+	}
+	if want := "crit=CRITICAL,high=HIGH"; strings.Join(got, ",") != want {
+		t.Errorf("findings %v, want %s", got, want)
+	}
+
+	if !ExceedsThreshold(report.Findings, Critical) {
+		t.Error("an aliased CRITICAL finding does not reach a CRITICAL threshold")
+	}
+	warning := testReporter(t).Build(withSeverities("warning"), Config{}, ".", testTime).Findings
+	if !ExceedsThreshold(warning, Medium) || ExceedsThreshold(warning, High) {
+		t.Error("an aliased MEDIUM finding is not gated as MEDIUM")
+	}
+}
+
+func TestDeterministicScanID(t *testing.T) {
+	dir := t.TempDir()
+	id := DeterministicScanID(dir, "abc123")()
+
+	if again := DeterministicScanID(dir, "abc123")(); again != id {
+		t.Errorf("same inputs gave %s and %s", id, again)
+	}
+	if !strings.HasPrefix(id, "SCAN-") || len(id) != len("SCAN-")+16 {
+		t.Errorf("scan ID %q, want SCAN- and 16 hex digits", id)
+	}
+	if other := DeterministicScanID(dir, "def456")(); other == id {
+		t.Error("different seeds gave the same ID")
+	}
+	if other := DeterministicScanID(t.TempDir(), "abc123")(); other == id {
+		t.Error("different targets gave the same ID")
+	}
+
+	// A relative path names the same target as its absolute form
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if DeterministicScanID(".", "abc123")() != DeterministicScanID(wd, "abc123")() {
+		t.Error("relative and absolute target paths gave different IDs")
+	}
+}
+
+func TestScanIDFuncUsedInReport(t *testing.T) {
+	r := New([]string{"json"}, filepath.Join(t.TempDir(), "report"))
+	r.ScanIDFunc = DeterministicScanID(".", "seed")
+	first := r.Build(nil, Config{}, ".", time.Now()).ScanID
+	if second := r.Build(nil, Config{}, ".", time.Now()).ScanID; first != second {
+		t.Errorf("reports got scan IDs %s and %s", first, second)
+	}
+}
+
+func TestRiskScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		findings []models.Finding
+		config   Config
+		want     float64
+	}{
+		{"no findings", nil, Config{}, 0},
+		{"all info", withSeverities(Info, Info, Info), Config{}, 0},
+		{"default weights", withSeverities(Critical, High, High, Medium, Low, Info), Config{}, 10 + 5 + 5 + 2 + 1},
+		{"custom weights", withSeverities(Critical, Low, Low), Config{RiskWeights: map[models.Severity]float64{Critical: 100, Low: 0.5}}, 101},
+		{"capped", withSeverities(Critical, Critical, Critical), Config{RiskCap: 25}, 25},
+		{"below cap", withSeverities(High), Config{RiskCap: 25}, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := testReporter(t).Build(tt.findings, tt.config, ".", testTime)
+			if report.RiskScore != tt.want {
+				t.Errorf("risk score %v, want %v", report.RiskScore, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleCoverage(t *testing.T) {
+	findings := []models.Finding{
+		{RuleID: "SQLI"}, {RuleID: "SQLI"}, {RuleID: "SQLI"},
+		{RuleID: "EXTERNAL"},
+		{ID: "no-rule"},
+	}
+	got := ruleCoverage(findings, Config{RulesUsed: []string{"SQLI", "XSS"}})
+
+	want := map[string]int{"SQLI": 3, "XSS": 0, "EXTERNAL": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("coverage %v, want %v", got, want)
+	}
+}
+
+func TestStatsCountsAliasedSeverities(t *testing.T) {
+	findings := withSeverities("critical", "Crit", "high", "HIGH", "med", "warning", "low", "informational", "info", "severe", "")
+	stats := testReporter(t).Build(findings, Config{}, ".", testTime).SummaryStats
+
+	want := Stats{
+		TotalFindings: 11,
+		CriticalCount: 2,
+		HighCount:     2,
+		MediumCount:   2,
+		LowCount:      1,
+		InfoCount:     2,
+		UnknownCount:  2,
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("stats %+v, want %+v", stats, want)
+	}
+
+	sum := stats.CriticalCount + stats.HighCount + stats.MediumCount + stats.LowCount + stats.InfoCount + stats.UnknownCount
+	for _, custom := range stats.Custom() {
+		sum += custom.Count
+	}
+	if sum != stats.TotalFindings {
+		t.Errorf("severity counts sum to %d, total is %d", sum, stats.TotalFindings)
+	}
+}
+
+func TestCustomSeverity(t *testing.T) {
+	// Registration is process-wide; registering the same level again in
+	// later runs has no effect
+	blocker := models.Severity("BLOCKER")
+	if err := models.RegisterSeverity(models.SeverityLevel{Severity: blocker, Rank: -1, Color: "#6f42c1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	findings := []models.Finding{
+		{ID: "high", Severity: High, Category: "Injection", Location: "a.go"},
+		{ID: "blocker-b", Severity: blocker, Category: "Injection", Location: "b.go"},
+		{ID: "critical", Severity: Critical, Category: "Secrets", Location: "a.go"},
+		{ID: "blocker-a", Severity: blocker, Category: "Crypto", Location: "a.go"},
+		{ID: "alias", Severity: "blocker", Category: "Crypto", Location: "c.go"},
+	}
+	r := testReporter(t, "json")
+	report := r.Build(findings, Config{}, ".", testTime)
+
+	stats := report.SummaryStats
+	if stats.CriticalCount != 1 || stats.HighCount != 1 || stats.UnknownCount != 0 {
+		t.Errorf("stats %+v, want one critical and one high", stats)
+	}
+	if got, want := stats.Custom(), []SeverityCount{{Severity: blocker, Count: 3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("custom counts %v, want %v", got, want)
+	}
+
+	var contents []string
+	for _, group := range groupBySeverity(findings[:4]) {
+		for _, finding := range group.Findings {
+			contents = append(contents, finding.ID)
+		}
+	}
+	if want := []string{"blocker-a", "blocker-b", "critical", "high"}; !reflect.DeepEqual(contents, want) {
+		t.Errorf("findings by severity %v, want %v", contents, want)
+	}
+	if got, want := groupSummary(groupFindings(findings[:4])), []string{
+		"Crypto:blocker-a", "Injection:blocker-b,high", "Secrets:critical",
+	}; !reflect.DeepEqual(got, want) {
+		t.Errorf("groups %v, want %v", got, want)
+	}
+	if got := byFile(findings)[0]; got.Path != "a.go" || got.MaxSeverity != blocker {
+		t.Errorf("worst file %+v, want a.go at %s", got, blocker)
+	}
+	if !ExceedsThreshold(findings[1:2], Critical) {
+		t.Error("a BLOCKER finding does not exceed a CRITICAL threshold")
+	}
+
+	// The report carries the counts, and the HTML styles the level
+	data, err := json.Marshal(report.SummaryStats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"customCounts":{"BLOCKER":3}`) {
+		t.Errorf("stats JSON %s does not count BLOCKER", data)
+	}
+	html := renderHTML(t, testReporter(t, "html"), report)
+	if !strings.Contains(html, "#6f42c1") {
+		t.Error("HTML report does not style BLOCKER findings")
+	}
+}
+
+func TestByFile(t *testing.T) {
+	at := func(location string, severity models.Severity) models.Finding {
+		return models.Finding{Location: location, Severity: severity}
+	}
+	findings := []models.Finding{
+		at("b.go", Low),
+		at("a.go", "medium"),
+		at("c.go", Low),
+		at("b.go", "crit"),
+		at("a.go", Low),
+		at("d.go", High),
+		at("c.go", Low),
+		at("e.go", High),
+		at("f.go", "BOGUS"),
+	}
+
+	want := []FileSummary{
+		{Path: "b.go", Count: 2, MaxSeverity: Critical},
+		{Path: "a.go", Count: 2, MaxSeverity: Medium},
+		{Path: "c.go", Count: 2, MaxSeverity: Low},
+		{Path: "d.go", Count: 1, MaxSeverity: High},
+		{Path: "e.go", Count: 1, MaxSeverity: High},
+		{Path: "f.go", Count: 1, MaxSeverity: "BOGUS"},
+	}
+	if got := testReporter(t).Build(findings, Config{}, ".", testTime).ByFile; !reflect.DeepEqual(got, want) {
+		t.Errorf("byFile =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// Streamed reports tally files the same way
+	r := testReporter(t, "json")
+	if err := r.GenerateStream(send(findings), Config{}, ".", testTime); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(r.BasePath + ".json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var streamed Report
+	if err := json.Unmarshal(data, &streamed); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(streamed.ByFile, want) {
+		t.Errorf("streamed byFile =\n%+v\nwant\n%+v", streamed.ByFile, want)
+	}
+}
+
+func TestWriteReportsEachFailedFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		formats []string
+		setup   func(r *Reporter)
+		failed  string
+		written []string
+	}{
+		{
+			name:    "unsupported format",
+			formats: []string{"json", "pdf"},
+			failed:  "pdf report: unsupported format: pdf",
+			written: []string{"json"},
+		},
+		{
+			name:    "broken template",
+			formats: []string{"html", "md"},
+			setup: func(r *Reporter) {
+				r.TemplatePath = filepath.Join(filepath.Dir(r.BasePath), "missing.html")
+			},
+			failed:  "html report:",
+			written: []string{"md"},
+		},
+		{
+			name:    "unwritable path",
+			formats: []string{"junit", "json"},
+			setup: func(r *Reporter) {
+				if err := os.Mkdir(r.BasePath+".json", 0o755); err != nil {
+					t.Fatal(err)
+				}
+			},
+			failed:  "json report:",
+			written: []string{"xml"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testReporter(t, tt.formats...)
+			if tt.setup != nil {
+				tt.setup(r)
+			}
+
+			err := r.Write(r.Build(sampleFindings(), Config{}, ".", testTime))
+			if err == nil || !strings.Contains(err.Error(), tt.failed) {
+				t.Fatalf("Write error %v, want %q", err, tt.failed)
+			}
+			if strings.Count(err.Error(), " report:") != 1 {
+				t.Errorf("Write error %q, want only the failed format reported", err)
+			}
+			for _, ext := range tt.written {
+				if info, err := os.Stat(r.BasePath + "." + ext); err != nil || info.Size() == 0 {
+					t.Errorf("%s report not written beside the failure: %v", ext, err)
+				}
+			}
+		})
+	}
+}
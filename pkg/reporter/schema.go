@@ -0,0 +1,111 @@
+package reporter
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// schemaURI identifies the JSON Schema dialect of the report schema
+const schemaURI = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	severityType = reflect.TypeOf(models.Severity(""))
+)
+
+// Schema returns a JSON Schema describing the JSON report format. It is
+// derived from the report types so it cannot drift from the encoder.
+func Schema() ([]byte, error) {
+	defs := make(map[string]interface{})
+	root := schemaForStruct(reflect.TypeOf(Report{}), defs)
+	root["$schema"] = schemaURI
+	root["title"] = "Security Scan Report"
+	root["$defs"] = defs
+
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// schemaFor returns the schema of a type, adding named structs to defs
+func schemaFor(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == severityType:
+		return map[string]interface{}{"type": "string", "enum": models.Severities()}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return map[string]interface{}{
+			"anyOf": []interface{}{schemaFor(t.Elem(), defs), map[string]interface{}{"type": "null"}},
+		}
+	case reflect.Struct:
+		if _, ok := defs[t.Name()]; !ok {
+			// Reserve the name first so recursive types terminate
+			defs[t.Name()] = nil
+			defs[t.Name()] = schemaForStruct(t, defs)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+	case reflect.Slice, reflect.Array:
+		// Nil slices encode as null
+		return map[string]interface{}{
+			"type":  []string{"array", "null"},
+			"items": schemaFor(t.Elem(), defs),
+		}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 []string{"object", "null"},
+			"additionalProperties": schemaFor(t.Elem(), defs),
+		}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// schemaForStruct describes a struct as an object whose required
+// properties are the fields encoded without omitempty
+func schemaForStruct(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = schemaFor(field.Type, defs)
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
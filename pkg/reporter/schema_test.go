@@ -0,0 +1,208 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// validate checks value against the subset of JSON Schema that Schema
+// emits, returning the first violation
+func validate(schema map[string]interface{}, defs map[string]interface{}, value interface{}, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		def, ok := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: unresolved $ref %s", path, ref)
+		}
+		return validate(def, defs, value, path)
+	}
+
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		for _, option := range anyOf {
+			if validate(option.(map[string]interface{}), defs, value, path) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %v matches no anyOf option", path, value)
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range enum {
+			found = found || allowed == value
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", path, value, enum)
+		}
+	}
+
+	if types, ok := schema["type"]; ok && !hasType(types, value) {
+		return fmt.Errorf("%s: %v (%T) does not have type %v", path, value, value, types)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		for _, name := range asList(schema["required"]) {
+			if _, ok := v[name.(string)]; !ok {
+				return fmt.Errorf("%s: missing required property %s", path, name)
+			}
+		}
+		for name, item := range v {
+			property, ok := properties[name].(map[string]interface{})
+			if !ok {
+				switch extra := schema["additionalProperties"].(type) {
+				case bool:
+					if !extra {
+						return fmt.Errorf("%s: unexpected property %s", path, name)
+					}
+					continue
+				case map[string]interface{}:
+					property = extra
+				default:
+					continue
+				}
+			}
+			if err := validate(property, defs, item, path+"."+name); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := validate(items, defs, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// hasType reports whether value has one of the JSON types
+func hasType(types interface{}, value interface{}) bool {
+	for _, t := range asList(types) {
+		switch t {
+		case "null":
+			if value == nil {
+				return true
+			}
+		case "object":
+			if _, ok := value.(map[string]interface{}); ok {
+				return true
+			}
+		case "array":
+			if _, ok := value.([]interface{}); ok {
+				return true
+			}
+		case "string":
+			if _, ok := value.(string); ok {
+				return true
+			}
+		case "boolean":
+			if _, ok := value.(bool); ok {
+				return true
+			}
+		case "number":
+			if _, ok := value.(float64); ok {
+				return true
+			}
+		case "integer":
+			if n, ok := value.(float64); ok && n == float64(int64(n)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// asList returns a JSON array as a slice, or a single value as a
+// one-element slice
+func asList(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	default:
+		return []interface{}{v}
+	}
+}
+
+// decodeJSON decodes data into a generic value
+func decodeJSON(t *testing.T, data []byte) interface{} {
+	t.Helper()
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+func TestSampleReportMatchesSchema(t *testing.T) {
+	data, err := Schema()
+	if err != nil {
+		t.Fatalf("Schema: %v", err)
+	}
+	schema := decodeJSON(t, data).(map[string]interface{})
+	defs := schema["$defs"].(map[string]interface{})
+
+	findings := sampleFindings()
+	findings[0].Labels = []models.CategoryScore{{Category: "Injection", Score: 0.9}}
+	findings[0].Metadata = map[string]string{"reference": "https://example.com"}
+
+	tests := []struct {
+		name     string
+		findings []models.Finding
+		reporter func(r *Reporter)
+	}{
+		{"findings", findings, func(r *Reporter) {}},
+		{"empty", nil, func(r *Reporter) {}},
+		{"baseline and delta", findings, func(r *Reporter) {
+			r.Baseline = &BaselineSummary{NewCount: 1}
+			r.Delta = Compare(findings[:2], findings)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testReporter(t, "json")
+			tt.reporter(r)
+			report := r.Build(tt.findings, Config{Version: "v1.0.0", RulesUsed: []string{"SQLI"}}, ".", testTime)
+			if err := r.Write(report); err != nil {
+				t.Fatal(err)
+			}
+			written, err := os.ReadFile(r.PathFor(report, "json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := validate(schema, defs, decodeJSON(t, written), "$"); err != nil {
+				t.Errorf("report does not match the schema: %v", err)
+			}
+		})
+	}
+}
+
+func TestSchemaRejectsUnknownProperty(t *testing.T) {
+	data, err := Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := decodeJSON(t, data).(map[string]interface{})
+
+	r := testReporter(t)
+	encoded, err := json.Marshal(r.Build(nil, Config{}, ".", testTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := decodeJSON(t, encoded).(map[string]interface{})
+	report["unexpected"] = true
+
+	if validate(schema, schema["$defs"].(map[string]interface{}), report, "$") == nil {
+		t.Error("schema accepted a report with an unknown property")
+	}
+}
@@ -0,0 +1,53 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// Status is a compact, machine-readable outcome of a scan for CI scripts
+type Status struct {
+	Total     int             `json:"total"`
+	Critical  int             `json:"critical"`
+	High      int             `json:"high"`
+	Medium    int             `json:"medium"`
+	Low       int             `json:"low"`
+	Info      int             `json:"info"`
+	Unknown   int             `json:"unknown"`
+	RiskScore float64         `json:"riskScore"`
+	FailOn    models.Severity `json:"failOn,omitempty"`
+	Passed    bool            `json:"passed"`
+}
+
+// NewStatus summarizes a report. passed is the outcome of the failure gate
+// at threshold, which is empty when no gate is configured.
+func NewStatus(report Report, threshold models.Severity, passed bool) Status {
+	stats := report.SummaryStats
+	return Status{
+		Total:     stats.TotalFindings,
+		Critical:  stats.CriticalCount,
+		High:      stats.HighCount,
+		Medium:    stats.MediumCount,
+		Low:       stats.LowCount,
+		Info:      stats.InfoCount,
+		Unknown:   stats.UnknownCount,
+		RiskScore: report.RiskScore,
+		FailOn:    threshold,
+		Passed:    passed,
+	}
+}
+
+// WriteStatus writes the status as JSON to path
+func WriteStatus(path string, status Status) error {
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode status: %v", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write status file: %v", err)
+	}
+	return nil
+}
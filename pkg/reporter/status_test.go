@@ -0,0 +1,44 @@
+package reporter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStatusMatchesReportStats(t *testing.T) {
+	report := testReporter(t).Build(sampleFindings(), Config{}, ".", testTime)
+	path := filepath.Join(t.TempDir(), "status.json")
+	if err := WriteStatus(path, NewStatus(report, High, false)); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Status
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := report.SummaryStats
+	want := Status{
+		Total:     stats.TotalFindings,
+		Critical:  stats.CriticalCount,
+		High:      stats.HighCount,
+		Medium:    stats.MediumCount,
+		Low:       stats.LowCount,
+		Info:      stats.InfoCount,
+		Unknown:   stats.UnknownCount,
+		RiskScore: report.RiskScore,
+		FailOn:    High,
+	}
+	if got != want {
+		t.Errorf("status %+v, want %+v", got, want)
+	}
+	if got.Total != 4 || got.Critical != 1 || got.High != 1 || got.Medium != 1 || got.Info != 1 {
+		t.Errorf("status counts %+v do not match the sample findings", got)
+	}
+}
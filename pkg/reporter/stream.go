@@ -0,0 +1,149 @@
+package reporter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// findingsField is where findings are spliced into the encoded report
+const findingsField = "\n  \"findings\": null"
+
+// GenerateStream creates a report from findings received on a channel. A
+// single JSON or NDJSON format is written incrementally, so memory use stays
+// flat regardless of the number of findings; other formats, and multiple
+// formats, collect findings first.
+func (r *Reporter) GenerateStream(findings <-chan models.Finding, config Config, target string, duration time.Time) error {
+	format := ""
+	if len(r.Formats) == 1 {
+		format = r.Formats[0]
+	}
+
+	report := r.createReport(nil, config, target, duration)
+
+	switch format {
+	case "json":
+	case "ndjson":
+		return r.streamNDJSON(r.PathFor(report, format), report, findings, duration)
+	default:
+		var collected []models.Finding
+		for finding := range findings {
+			collected = append(collected, finding)
+		}
+		return r.Generate(collected, config, target, duration)
+	}
+
+	file, err := createReportFile(r.PathFor(report, format))
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %v", err)
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	if err := r.streamJSON(w, report, findings, duration); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write report: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write report: %v", err)
+	}
+
+	return nil
+}
+
+// streamJSON writes output identical to generateJSON: the header of report,
+// the findings array one element at a time, then the summary fields
+func (r *Reporter) streamJSON(w *bufio.Writer, report Report, findings <-chan models.Finding, duration time.Time) error {
+	head, _, err := splitReport(report)
+	if err != nil {
+		return err
+	}
+	w.Write(head)
+	w.WriteString("\n  \"findings\": ")
+
+	count := 0
+	for finding := range findings {
+		finding.Severity = finding.Severity.Canonical()
+		if !r.keep(finding) {
+			continue
+		}
+		finding = r.redact(finding)
+
+		data, err := json.MarshalIndent(finding, "    ", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode finding: %v", err)
+		}
+
+		if count == 0 {
+			w.WriteString("[\n    ")
+		} else {
+			w.WriteString(",\n    ")
+		}
+		w.Write(data)
+
+		report.count(finding)
+		count++
+	}
+
+	if count == 0 {
+		w.WriteString("null")
+	} else {
+		w.WriteString("\n  ]")
+	}
+
+	report.finish(r.now().Sub(duration))
+
+	_, tail, err := splitReport(report)
+	if err != nil {
+		return err
+	}
+	w.Write(tail)
+
+	return nil
+}
+
+// count adds a streamed finding to the report statistics
+func (report *Report) count(finding models.Finding) {
+	report.SummaryStats.add(finding)
+	if report.files == nil {
+		report.files = make(fileTally)
+	}
+	report.files.add(finding)
+	if finding.RuleID != "" {
+		report.RuleCoverage[finding.RuleID]++
+	}
+}
+
+// finish computes the summary fields of a streamed report that took elapsed
+func (report *Report) finish(elapsed time.Duration) {
+	report.RiskScore = riskScore(report.SummaryStats, report.ScannerConfig)
+	report.ByFile = report.files.summaries()
+	report.ScanDuration = elapsed.String()
+}
+
+// splitReport encodes a report without findings and returns the parts
+// before and after the findings field
+func splitReport(report Report) ([]byte, []byte, error) {
+	report.Findings = nil
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode report: %v", err)
+	}
+
+	data := buf.Bytes()
+	idx := bytes.Index(data, []byte(findingsField))
+	if idx < 0 {
+		return nil, nil, fmt.Errorf("failed to locate findings in encoded report")
+	}
+
+	return data[:idx], data[idx+len(findingsField):], nil
+}
@@ -0,0 +1,56 @@
+package reporter
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// send returns a closed channel holding findings
+func send(findings []models.Finding) <-chan models.Finding {
+	ch := make(chan models.Finding, len(findings))
+	for _, finding := range findings {
+		ch <- finding
+	}
+	close(ch)
+	return ch
+}
+
+func TestStreamMatchesGenerateJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		findings []models.Finding
+	}{
+		{"findings", sampleFindings()},
+		{"no findings", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := testTime.Add(-3 * time.Second)
+
+			streamed := testReporter(t, "json")
+			if err := streamed.GenerateStream(send(tt.findings), Config{}, ".", start); err != nil {
+				t.Fatalf("GenerateStream: %v", err)
+			}
+			generated := testReporter(t, "json")
+			if err := generated.Generate(tt.findings, Config{}, ".", start); err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+
+			got, err := os.ReadFile(streamed.BasePath + ".json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := os.ReadFile(generated.BasePath + ".json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("streamed report differs from generated report\nstreamed:\n%s\ngenerated:\n%s", got, want)
+			}
+		})
+	}
+}
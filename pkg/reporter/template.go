@@ -0,0 +1,58 @@
+package reporter
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// unsafeNameChars matches runs of characters not kept in file names built
+// from placeholders
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// PathFor returns the file a format is written to for a report, expanding
+// placeholders in BasePath:
+//
+//	{target}  the base name of the scanned target, made file name safe
+//	{date}    the report date, 2006-01-02
+//	{time}    the report time of day in UTC, 150405
+//	{scanid}  the report ScanID, made file name safe
+func (r *Reporter) PathFor(report Report, format string) string {
+	timestamp := report.Timestamp.UTC()
+	expanded := strings.NewReplacer(
+		"{target}", sanitizeName(targetName(report.Target)),
+		"{date}", timestamp.Format("2006-01-02"),
+		"{time}", timestamp.Format("150405"),
+		"{scanid}", sanitizeName(report.ScanID),
+	).Replace(r.BasePath)
+
+	path := expanded + "." + FileExtension(format)
+	if r.Compress && compressible(format) {
+		path += gzipExtension
+	}
+	return path
+}
+
+// targetName returns the base name of a target path, resolving "." and
+// other relative paths against the working directory
+func targetName(target string) string {
+	if abs, err := filepath.Abs(target); err == nil {
+		target = abs
+	}
+	return filepath.Base(target)
+}
+
+// sanitizeName makes s safe to use as a single file name component: path
+// separators, ".." and other special characters are replaced by
+// underscores
+func sanitizeName(s string) string {
+	s = unsafeNameChars.ReplaceAllString(s, "_")
+	for strings.Contains(s, "..") {
+		s = strings.ReplaceAll(s, "..", "_")
+	}
+	s = strings.Trim(s, "._")
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
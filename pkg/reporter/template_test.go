@@ -0,0 +1,57 @@
+package reporter
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPathFor(t *testing.T) {
+	// 13:04:05 UTC, reported in a zone two hours ahead
+	timestamp := time.Date(2024, 3, 1, 15, 4, 5, 0, time.FixedZone("CEST", 2*3600))
+	report := Report{Target: "/work/my app", ScanID: "SCAN-../x", Timestamp: timestamp}
+
+	tests := []struct {
+		base     string
+		format   string
+		compress bool
+		want     string
+	}{
+		{"out/report", "json", false, "out/report.json"},
+		{"out/{target}-{date}-{time}", "html", false, "out/my_app-2024-03-01-130405.html"},
+		{"out/{scanid}/report", "junit", false, "out/SCAN-__x/report.xml"},
+		{"out/{target}", "json", true, "out/my_app.json.gz"},
+		{"out/{target}", "md", true, "out/my_app.md"},
+		{"out/{unknown}", "text", false, "out/{unknown}.txt"},
+	}
+	for _, tt := range tests {
+		r := New(nil, filepath.FromSlash(tt.base))
+		r.Compress = tt.compress
+		if got := filepath.ToSlash(r.PathFor(report, tt.format)); got != tt.want {
+			t.Errorf("PathFor(%q, %s) = %q, want %q", tt.base, tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"project", "project"},
+		{"my project", "my_project"},
+		{"a/b\\c", "a_b_c"},
+		{"../../etc", "etc"},
+		{"a..b", "a_b"},
+		{"v1.2-rc_3", "v1.2-rc_3"},
+		{".hidden", "hidden"},
+		{"", "unknown"},
+		{"///", "unknown"},
+		{"..", "unknown"},
+	}
+	for _, tt := range tests {
+		if got := sanitizeName(tt.input); got != tt.want {
+			t.Errorf("sanitizeName(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
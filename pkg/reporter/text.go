@@ -0,0 +1,61 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// generateText writes findings to stdout one line each, in the
+// file:line:column form understood by editors and terminals
+func (r *Reporter) generateText(report Report, _ string) error {
+	return writeText(os.Stdout, report)
+}
+
+// writeText writes one line per finding followed by a summary line, and a
+// line summarizing the changes since a previous scan when compared. Nothing
+// else is written when there are no findings.
+func writeText(w io.Writer, report Report) error {
+	if delta := report.Delta; delta != nil {
+		if _, err := fmt.Fprintf(w, "Since previous scan: %d new, %d fixed, %d persisting\n",
+			delta.NewCount, delta.FixedCount, delta.PersistingCount); err != nil {
+			return fmt.Errorf("failed to write summary: %v", err)
+		}
+	}
+
+	for _, finding := range report.Findings {
+		position := finding.Location
+		if finding.Line > 0 {
+			position += fmt.Sprintf(":%d", finding.Line)
+			if finding.Column > 0 {
+				position += fmt.Sprintf(":%d", finding.Column)
+			}
+		}
+
+		id := finding.RuleID
+		if id == "" {
+			id = finding.ID
+		}
+
+		if _, err := fmt.Fprintf(w, "%s: %s [%s] %s\n", position, finding.Severity, id, finding.Title); err != nil {
+			return fmt.Errorf("failed to write finding: %v", err)
+		}
+	}
+
+	stats := report.SummaryStats
+	if stats.TotalFindings == 0 {
+		return nil
+	}
+
+	summary := fmt.Sprintf("%d finding(s): %d critical, %d high, %d medium, %d low, %d info",
+		stats.TotalFindings, stats.CriticalCount, stats.HighCount, stats.MediumCount, stats.LowCount, stats.InfoCount)
+	for _, custom := range stats.Custom() {
+		summary += fmt.Sprintf(", %d %s", custom.Count, strings.ToLower(string(custom.Severity)))
+	}
+	if _, err := fmt.Fprintln(w, summary); err != nil {
+		return fmt.Errorf("failed to write summary: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package sbom
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// Document represents a CycloneDX 1.5 bill of materials
+type Document struct {
+	BOMFormat    string      `json:"bomFormat"`
+	SpecVersion  string      `json:"specVersion"`
+	SerialNumber string      `json:"serialNumber"`
+	Version      int         `json:"version"`
+	Metadata     Metadata    `json:"metadata"`
+	Components   []Component `json:"components"`
+}
+
+// Metadata describes when and by what the BOM was produced
+type Metadata struct {
+	Timestamp string `json:"timestamp"`
+	Tools     Tools  `json:"tools"`
+}
+
+// Tools lists the tools that produced the BOM
+type Tools struct {
+	Components []Component `json:"components"`
+}
+
+// Component represents a single software component
+type Component struct {
+	Type    string `json:"type"`
+	BOMRef  string `json:"bom-ref,omitempty"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	PURL    string `json:"purl,omitempty"`
+	Scope   string `json:"scope,omitempty"`
+}
+
+// PURL returns the package URL for a dependency
+func PURL(dep models.Dependency) string {
+	return fmt.Sprintf("pkg:%s/%s@%s", dep.Type, dep.Name, dep.Version)
+}
+
+// Generate builds a CycloneDX document listing the given dependencies.
+// Duplicate components are listed once.
+func Generate(deps []models.Dependency, toolVersion string) Document {
+	doc := Document{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: serialNumber(),
+		Version:      1,
+		Metadata: Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Tools: Tools{Components: []Component{{
+				Type:    "application",
+				Name:    "devsecops-ai",
+				Version: toolVersion,
+			}}},
+		},
+		Components: []Component{},
+	}
+
+	seen := make(map[string]bool)
+	for _, dep := range deps {
+		purl := PURL(dep)
+		if seen[purl] {
+			continue
+		}
+		seen[purl] = true
+
+		scope := "required"
+		if dep.Indirect {
+			scope = "optional"
+		}
+
+		doc.Components = append(doc.Components, Component{
+			Type:    "library",
+			BOMRef:  purl,
+			Name:    dep.Name,
+			Version: dep.Version,
+			PURL:    purl,
+			Scope:   scope,
+		})
+	}
+
+	sort.Slice(doc.Components, func(i, j int) bool {
+		return doc.Components[i].PURL < doc.Components[j].PURL
+	})
+
+	return doc
+}
+
+// Write generates a CycloneDX document and writes it to path as JSON
+func Write(path string, deps []models.Dependency, toolVersion string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create SBOM file: %v", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(Generate(deps, toolVersion)); err != nil {
+		return fmt.Errorf("failed to encode SBOM: %v", err)
+	}
+
+	return nil
+}
+
+// serialNumber returns a random RFC 4122 version 4 UUID URN
+func serialNumber() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+	"github.com/SofNam/devsecops-ai/pkg/version"
+)
+
+// Advisory describes a known vulnerability affecting a range of module versions
+type Advisory struct {
+	ID         string `json:"id"`
+	Summary    string `json:"summary"`
+	Introduced string `json:"introduced"`
+	Fixed      string `json:"fixed"`
+}
+
+// Affects reports whether the version falls within the advisory range.
+// Introduced is inclusive and Fixed exclusive; either may be empty.
+func (a Advisory) Affects(v string) bool {
+	if a.Introduced != "" && version.Compare(v, a.Introduced) < 0 {
+		return false
+	}
+	if a.Fixed != "" && version.Compare(v, a.Fixed) >= 0 {
+		return false
+	}
+	return true
+}
+
+// loadAdvisories reads a JSON file mapping module paths to advisories
+func loadAdvisories(path string) (map[string][]Advisory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var advisories map[string][]Advisory
+	if err := json.Unmarshal(data, &advisories); err != nil {
+		return nil, err
+	}
+
+	return advisories, nil
+}
+
+// checkDependencies flags dependencies with versions covered by an
+// advisory, stamping findings with now
+func checkDependencies(deps []models.Dependency, advisories map[string][]Advisory, now time.Time) []models.Finding {
+	var findings []models.Finding
+
+	for _, dep := range deps {
+		for _, advisory := range advisories[dep.Name] {
+			if !advisory.Affects(dep.Version) {
+				continue
+			}
+
+			remediation := "Upgrade to a version that is not affected"
+			if advisory.Fixed != "" {
+				remediation = fmt.Sprintf("Upgrade %s to %s or later", dep.Name, advisory.Fixed)
+			}
+
+			finding := models.Finding{
+				ID:          advisory.ID,
+				RuleID:      advisory.ID,
+				Title:       fmt.Sprintf("Vulnerable dependency %s", dep.Name),
+				Description: fmt.Sprintf("%s %s is affected by %s: %s", dep.Name, dep.Version, advisory.ID, advisory.Summary),
+				Severity:    models.SeverityHigh,
+				Category:    "Dependency",
+				Location:    dep.Location,
+				Line:        dep.Line,
+				CodeSnippet: fmt.Sprintf("%s %s", dep.Name, dep.Version),
+				Timestamp:   now,
+				Remediation: remediation,
+				Confidence:  1.0,
+			}
+			finding.Fingerprint = models.ComputeFingerprint(finding)
+
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,129 @@
+package scanner
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// Analyzer inspects a single file and reports findings. Analyzers are
+// called concurrently for different files and must be safe for that.
+type Analyzer interface {
+	// Name identifies the analyzer in errors and diagnostics
+	Name() string
+	// CanHandle reports whether the analyzer applies to the file
+	CanHandle(path string) bool
+	// Analyze returns the findings for the file content
+	Analyze(path string, content []byte) ([]models.Finding, error)
+}
+
+// Register adds an analyzer to the scanner's registry
+func (s *Scanner) Register(analyzer Analyzer) {
+	s.analyzers = append(s.analyzers, analyzer)
+}
+
+// SetAnalyzers replaces the registry, e.g. to drop built-in analyzers
+func (s *Scanner) SetAnalyzers(analyzers ...Analyzer) {
+	s.analyzers = analyzers
+}
+
+// Analyzers returns the registered analyzers in the order they run
+func (s *Scanner) Analyzers() []Analyzer {
+	return s.analyzers
+}
+
+// defaultAnalyzers returns the built-in analyzers, which read the rules and
+// advisories the scanner loads at the start of each scan
+func (s *Scanner) defaultAnalyzers() []Analyzer {
+	return []Analyzer{
+		&ruleAnalyzer{scanner: s},
+		&secretAnalyzer{config: s.config},
+		&profileAnalyzer{scanner: s},
+		&dependencyAnalyzer{scanner: s},
+		dockerfileAnalyzer{config: s.config},
+		yamlAnalyzer{config: s.config},
+	}
+}
+
+// ruleAnalyzer matches the loaded pattern rules line by line
+type ruleAnalyzer struct {
+	scanner *Scanner
+}
+
+func (a *ruleAnalyzer) Name() string { return "rules" }
+
+func (a *ruleAnalyzer) CanHandle(path string) bool { return true }
+
+func (a *ruleAnalyzer) Analyze(path string, content []byte) ([]models.Finding, error) {
+	var findings []models.Finding
+
+	// Match each rule line by line so findings carry a precise position
+	for i, line := range strings.Split(string(content), "\n") {
+		for j := range a.scanner.rules {
+			rule := &a.scanner.rules[j]
+			if !a.scanner.ruleSelected(rule) || !a.scanner.ruleEnabled(rule.ID, path) {
+				continue
+			}
+
+			match := rule.Match(line)
+			if match == nil {
+				continue
+			}
+
+			finding := models.Finding{
+				ID:          rule.ID,
+				RuleID:      rule.ID,
+				Title:       rule.Name,
+				Description: rule.Description,
+				Severity:    models.Severity(rule.Severity),
+				Category:    rule.Category,
+				Location:    path,
+				Line:        i + 1,
+				Column:      match[0] + 1,
+				CodeSnippet: snippet(line),
+				Timestamp:   a.scanner.config.now(),
+				Confidence:  1.0,
+				CWE:         rule.CWE,
+				OWASP:       rule.OWASP,
+				Tags:        slices.Clone(rule.Tags),
+				Metadata:    maps.Clone(rule.Metadata),
+			}
+			finding.Fingerprint = models.ComputeFingerprint(finding)
+			a.scanner.logger.Debugf("Rule %s matched %s:%d:%d", rule.ID, path, finding.Line, finding.Column)
+
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings, nil
+}
+
+// dependencyAnalyzer collects go.mod requirements and checks them against
+// the loaded advisories
+type dependencyAnalyzer struct {
+	scanner *Scanner
+}
+
+func (a *dependencyAnalyzer) Name() string { return "dependencies" }
+
+// uncached opts go.mod files out of the result cache, since findings depend
+// on the advisories and the scan records the dependencies
+func (a *dependencyAnalyzer) uncached() {}
+
+func (a *dependencyAnalyzer) CanHandle(path string) bool {
+	return filepath.Base(path) == "go.mod"
+}
+
+func (a *dependencyAnalyzer) Analyze(path string, content []byte) ([]models.Finding, error) {
+	deps := parseGoMod(path, content)
+
+	s := a.scanner
+	s.mu.Lock()
+	s.dependencies = append(s.dependencies, deps...)
+	s.mu.Unlock()
+
+	return checkDependencies(deps, s.advisories, s.config.now()), nil
+}
@@ -0,0 +1,66 @@
+package scanner
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// extAnalyzer reports one finding per file with the given extension
+type extAnalyzer struct {
+	name, ext string
+}
+
+func (a extAnalyzer) Name() string { return a.name }
+
+func (a extAnalyzer) CanHandle(path string) bool { return filepath.Ext(path) == a.ext }
+
+func (a extAnalyzer) Analyze(path string, content []byte) ([]models.Finding, error) {
+	return []models.Finding{{
+		ID:       strings.ToUpper(a.name),
+		RuleID:   strings.ToUpper(a.name),
+		Severity: models.SeverityLow,
+		Location: path,
+		Line:     1,
+	}}, nil
+}
+
+func TestCustomAnalyzers(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"app.py":  "password = 'x'\n",
+		"main.go": "package main\n",
+		"notes":   "nothing to see\n",
+	})
+	s := newTestScanner(t, dir, testRules, Config{})
+	s.Register(extAnalyzer{name: "python", ext: ".py"})
+	s.Register(extAnalyzer{name: "golang", ext: ".go"})
+
+	findings := scan(t, s)
+	if got, want := ruleIDs(findings), []string{"GOLANG", "PASSWORD", "PYTHON"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("rules %v, want %v", got, want)
+	}
+	for _, finding := range findings {
+		if finding.RuleID == "PYTHON" && filepath.Base(finding.Location) != "app.py" {
+			t.Errorf("python analyzer ran on %s", finding.Location)
+		}
+		if finding.RuleID == "GOLANG" && filepath.Base(finding.Location) != "main.go" {
+			t.Errorf("golang analyzer ran on %s", finding.Location)
+		}
+	}
+}
+
+func TestSetAnalyzersReplacesBuiltins(t *testing.T) {
+	dir := writeTree(t, map[string]string{"app.py": "password = 'x'\n"})
+	s := newTestScanner(t, dir, testRules, Config{})
+	s.SetAnalyzers(extAnalyzer{name: "python", ext: ".py"}, extAnalyzer{name: "script", ext: ".py"})
+
+	if got, want := ruleIDs(scan(t, s)), []string{"PYTHON", "SCRIPT"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("rules %v, want %v", got, want)
+	}
+	if len(s.Analyzers()) != 2 {
+		t.Errorf("got %d analyzers, want 2", len(s.Analyzers()))
+	}
+}
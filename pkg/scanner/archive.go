@@ -0,0 +1,181 @@
+package scanner
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// defaultMaxArchiveBytes bounds the decompressed content read from one
+// archive when Config.MaxArchiveBytes is unset
+const defaultMaxArchiveBytes = 100 << 20
+
+// archiveSeparator joins an archive path and an entry name in locations
+const archiveSeparator = "!"
+
+// errArchiveLimit is returned when an archive expands beyond the limit
+var errArchiveLimit = errors.New("decompressed content exceeds size limit")
+
+// archiveFormat returns the archive format of a file by name: "zip", "tar"
+// or "tgz", or "" when the file is not an archive
+func archiveFormat(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return "zip"
+	case strings.HasSuffix(lower, ".tar"):
+		return "tar"
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return "tgz"
+	default:
+		return ""
+	}
+}
+
+// analyzeArchive analyzes the regular files inside an archive in memory.
+// Entries are reported as "archive!entry/path"; archives nested inside are
+// not opened. Reading stops once the decompressed size limit is reached.
+func (s *Scanner) analyzeArchive(archivePath string, content []byte) ([]models.Finding, error) {
+	limit := s.config.MaxArchiveBytes
+	if limit <= 0 {
+		limit = defaultMaxArchiveBytes
+	}
+
+	var findings []models.Finding
+	err := readArchive(archiveFormat(archivePath), content, limit, func(name string, data []byte) error {
+		entryPath := archivePath + archiveSeparator + name
+
+		if archiveFormat(name) != "" {
+			s.skip(entryPath, "nested archive")
+			return nil
+		}
+		if s.config.MaxFileSizeBytes > 0 && int64(len(data)) > s.config.MaxFileSizeBytes {
+			s.skip(entryPath, fmt.Sprintf("size %d bytes exceeds limit of %d", len(data), s.config.MaxFileSizeBytes))
+			return nil
+		}
+
+		entryFindings, err := s.analyzeSource(entryPath, data)
+		if err != nil {
+			return err
+		}
+		findings = append(findings, entryFindings...)
+		return nil
+	})
+
+	if errors.Is(err, errArchiveLimit) {
+		s.skip(archivePath, fmt.Sprintf("remaining entries skipped: %v (limit %d bytes)", err, limit))
+		return findings, nil
+	}
+	if err != nil {
+		// A corrupt archive should not stop the scan
+		s.skip(archivePath, err.Error())
+		return findings, nil
+	}
+
+	return findings, nil
+}
+
+// readArchive calls fn with the name and content of each regular file in
+// an archive, reading at most limit decompressed bytes in total
+func readArchive(format string, content []byte, limit int64, fn func(name string, data []byte) error) error {
+	switch format {
+	case "zip":
+		return readZip(content, limit, fn)
+	case "tgz":
+		gz, err := gzip.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return fmt.Errorf("invalid gzip stream: %v", err)
+		}
+		defer gz.Close()
+		return readTar(gz, limit, fn)
+	case "tar":
+		return readTar(bytes.NewReader(content), limit, fn)
+	default:
+		return fmt.Errorf("unsupported archive format %q", format)
+	}
+}
+
+// readZip reads the regular files of a zip archive
+func readZip(content []byte, limit int64, fn func(name string, data []byte) error) error {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return fmt.Errorf("invalid zip archive: %v", err)
+	}
+
+	for _, entry := range archive.File {
+		if !entry.Mode().IsRegular() {
+			continue
+		}
+
+		rc, err := entry.Open()
+		if err != nil {
+			return fmt.Errorf("reading %s: %v", entry.Name, err)
+		}
+		data, err := readEntry(rc, &limit)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+
+		if err := fn(entryName(entry.Name), data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readTar reads the regular files of a tar stream
+func readTar(r io.Reader, limit int64, fn func(name string, data []byte) error) error {
+	archive := tar.NewReader(r)
+
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid tar archive: %v", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		data, err := readEntry(archive, &limit)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(entryName(header.Name), data); err != nil {
+			return err
+		}
+	}
+}
+
+// readEntry reads an entry, charging its size against the remaining limit
+func readEntry(r io.Reader, limit *int64) ([]byte, error) {
+	// Read one byte past the limit to detect oversized content
+	data, err := io.ReadAll(io.LimitReader(r, *limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > *limit {
+		return nil, errArchiveLimit
+	}
+	*limit -= int64(len(data))
+	return data, nil
+}
+
+// entryName cleans an entry name for use in locations
+func entryName(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
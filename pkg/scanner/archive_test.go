@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// writeZip creates a zip archive at path holding entries, keyed by name
+func writeZip(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	w := zip.NewWriter(file)
+	for name, content := range entries {
+		entry, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := entry.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestZipEntriesScanned(t *testing.T) {
+	root := t.TempDir()
+	archive := filepath.Join(root, "bundle.zip")
+	writeZip(t, archive, map[string]string{
+		"src/app.py":     "password = 'x'\n",
+		"./lib/util.py":  "eval(data)\n",
+		"../escape.py":   "password = 'x'\n",
+		"docs/":          "",
+		"vendor/dep.zip": "password = 'x'\n",
+	})
+
+	s := newTestScanner(t, root, testRules, Config{ScanArchives: true})
+	findings := scan(t, s)
+
+	var got []string
+	for _, finding := range findings {
+		got = append(got, finding.Location)
+	}
+	sort.Strings(got)
+	want := []string{archive + "!escape.py", archive + "!lib/util.py", archive + "!src/app.py"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("locations %v, want %v", got, want)
+	}
+
+	skipped := s.Skipped()
+	if len(skipped) != 1 || skipped[0].Path != archive+"!vendor/dep.zip" || skipped[0].Reason != "nested archive" {
+		t.Errorf("skipped %+v, want the nested archive", skipped)
+	}
+
+	if findings := scan(t, newTestScanner(t, root, testRules, Config{})); len(findings) != 0 {
+		t.Errorf("got %d findings with archive scanning off, want none", len(findings))
+	}
+}
@@ -0,0 +1,155 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/SofNam/devsecops-ai/pkg/ai"
+	"github.com/SofNam/devsecops-ai/pkg/models"
+	"github.com/SofNam/devsecops-ai/pkg/version"
+)
+
+// cacheEntry is the stored result of analyzing one file
+type cacheEntry struct {
+	Findings   []models.Finding `json:"findings"`
+	Suppressed []models.Finding `json:"suppressed,omitempty"`
+}
+
+// resultCache stores per-file analysis results on disk, keyed by the file's
+// path and content and by a hash of every setting that affects analysis
+type resultCache struct {
+	dir      string
+	settings string
+}
+
+// cacheSettings are the inputs, besides file content, that determine the
+// findings of a file
+type cacheSettings struct {
+	Version                string    `json:"version"`
+	Analyzers              []string  `json:"analyzers"`
+	Rules                  []ai.Rule `json:"rules"`
+	Profiles               []Profile `json:"profiles"`
+	Tags                   []string  `json:"tags"`
+	SecretEntropyThreshold float64   `json:"secretEntropyThreshold"`
+	SecretMinLength        int       `json:"secretMinLength"`
+	SecretMaxLength        int       `json:"secretMaxLength"`
+	SnippetContextLines    int       `json:"snippetContextLines"`
+}
+
+// openCache prepares the result cache when a cache directory is configured.
+// It must run after the rules are loaded so that editing the rule set
+// invalidates previously cached results.
+func (s *Scanner) openCache() error {
+	s.cache = nil
+	if s.config.CacheDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(s.config.CacheDir, 0o755); err != nil {
+		return fmt.Errorf("creating cache directory: %v", err)
+	}
+
+	settings := cacheSettings{
+		Version:                version.GetVersion().Version,
+		Rules:                  s.rules,
+		Profiles:               s.config.Profiles,
+		Tags:                   s.config.Tags,
+		SecretEntropyThreshold: s.config.SecretEntropyThreshold,
+		SecretMinLength:        s.config.SecretMinLength,
+		SecretMaxLength:        s.config.SecretMaxLength,
+		SnippetContextLines:    s.config.SnippetContextLines,
+	}
+	for _, analyzer := range s.analyzers {
+		settings.Analyzers = append(settings.Analyzers, analyzer.Name())
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("encoding cache settings: %v", err)
+	}
+	sum := sha256.Sum256(data)
+
+	s.cache = &resultCache{
+		dir:      s.config.CacheDir,
+		settings: hex.EncodeToString(sum[:]),
+	}
+	return nil
+}
+
+// key returns the cache key for a file's content
+func (c *resultCache) key(path string, content []byte) string {
+	h := sha256.New()
+	h.Write([]byte(c.settings))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// path returns the file holding the entry for key
+func (c *resultCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// load returns the cached entry for key, if present and readable
+func (c *resultCache) load(key string) (cacheEntry, bool) {
+	var entry cacheEntry
+
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return entry, false
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return entry, false
+	}
+	return entry, true
+}
+
+// store writes the entry for key, replacing it atomically
+func (c *resultCache) store(key string, entry cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// uncachedAnalyzer is implemented by analyzers whose results depend on state
+// outside the file, such as loaded advisories, or that record state on the
+// scanner; files they handle are always analyzed
+type uncachedAnalyzer interface {
+	uncached()
+}
+
+// cacheable reports whether results for path may be served from the cache
+func (s *Scanner) cacheable(path string) bool {
+	if s.cache == nil {
+		return false
+	}
+	for _, analyzer := range s.analyzers {
+		if _, ok := analyzer.(uncachedAnalyzer); ok && analyzer.CanHandle(path) {
+			return false
+		}
+	}
+	return true
+}
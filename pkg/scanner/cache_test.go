@@ -0,0 +1,98 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// spyAnalyzer records the files it analyzes
+type spyAnalyzer struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (a *spyAnalyzer) Name() string { return "spy" }
+
+func (a *spyAnalyzer) CanHandle(path string) bool { return true }
+
+func (a *spyAnalyzer) Analyze(path string, content []byte) ([]models.Finding, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.calls = append(a.calls, filepath.Base(path))
+	return nil, nil
+}
+
+// analyzed returns the sorted base names of the files analyzed so far and
+// resets the record
+func (a *spyAnalyzer) analyzed() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	calls := a.calls
+	a.calls = nil
+	sort.Strings(calls)
+	return calls
+}
+
+func TestCacheHitsAndBusts(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"a.py": "password = 'x'\n",
+		"b.py": "eval(data)\n",
+	})
+	cacheDir := t.TempDir()
+
+	// cachedScan scans with a fresh scanner sharing the cache directory
+	spy := &spyAnalyzer{}
+	cachedScan := func(rules string, config Config) []models.Finding {
+		t.Helper()
+		config.CacheDir = cacheDir
+		s := newTestScanner(t, dir, rules, config)
+		s.Register(spy)
+		return scan(t, s)
+	}
+
+	first := cachedScan(testRules, Config{})
+	if got := spy.analyzed(); !reflect.DeepEqual(got, []string{"a.py", "b.py"}) {
+		t.Fatalf("first scan analyzed %v, want both files", got)
+	}
+
+	// Unchanged files and settings are served from the cache
+	second := cachedScan(testRules, Config{})
+	if got := spy.analyzed(); len(got) != 0 {
+		t.Errorf("second scan analyzed %v, want none", got)
+	}
+	if !reflect.DeepEqual(ruleIDs(second), ruleIDs(first)) || !reflect.DeepEqual(fingerprints(second), fingerprints(first)) {
+		t.Errorf("cached findings %v differ from %v", ruleIDs(second), ruleIDs(first))
+	}
+
+	// Editing a file busts its entry only
+	if err := os.WriteFile(filepath.Join(dir, "b.py"), []byte("eval(other)\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cachedScan(testRules, Config{})
+	if got := spy.analyzed(); !reflect.DeepEqual(got, []string{"b.py"}) {
+		t.Errorf("after editing b.py analyzed %v, want [b.py]", got)
+	}
+
+	// Changing the rule set or the settings busts every entry
+	busting := []struct {
+		name   string
+		rules  string
+		config Config
+	}{
+		{"rules", `[{"id": "EVAL", "name": "Eval", "pattern": "eval\\(", "severity": "high", "category": "Injection", "description": "d"}]`, Config{}},
+		{"tags", testRules, Config{Tags: []string{"pci"}}},
+		{"context lines", testRules, Config{SnippetContextLines: 5}},
+	}
+	for _, tt := range busting {
+		cachedScan(tt.rules, tt.config)
+		if got := spy.analyzed(); !reflect.DeepEqual(got, []string{"a.py", "b.py"}) {
+			t.Errorf("after changing %s analyzed %v, want both files", tt.name, got)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package scanner
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// dockerfileCheck describes an issue detected in a Dockerfile
+type dockerfileCheck struct {
+	id          string
+	title       string
+	description string
+	severity    models.Severity
+	remediation string
+}
+
+var (
+	checkUnpinnedBase = dockerfileCheck{
+		id:          "DOCKER-001",
+		title:       "Base Image Not Pinned By Digest",
+		description: "FROM references an image without a digest, so builds may pull different content over time",
+		severity:    models.SeverityMedium,
+		remediation: "Pin the base image with an immutable digest, e.g. image:tag@sha256:<digest>",
+	}
+	checkRootUser = dockerfileCheck{
+		id:          "DOCKER-002",
+		title:       "Container Runs As Root",
+		description: "USER root makes the container process run with root privileges",
+		severity:    models.SeverityHigh,
+		remediation: "Create an unprivileged user and switch to it with USER",
+	}
+	checkMissingUser = dockerfileCheck{
+		id:          "DOCKER-003",
+		title:       "Missing USER Instruction",
+		description: "The image never sets USER, so the container runs as root by default",
+		severity:    models.SeverityMedium,
+		remediation: "Add a USER instruction with an unprivileged user after installing packages",
+	}
+	checkRemoteAdd = dockerfileCheck{
+		id:          "DOCKER-004",
+		title:       "ADD With Remote URL",
+		description: "ADD downloads remote content without integrity verification",
+		severity:    models.SeverityMedium,
+		remediation: "Download with curl or wget and verify a checksum, or use COPY for local files",
+	}
+	checkAptRecommends = dockerfileCheck{
+		id:          "DOCKER-005",
+		title:       "apt-get Install Without --no-install-recommends",
+		description: "apt-get install pulls in recommended packages, enlarging the attack surface",
+		severity:    models.SeverityLow,
+		remediation: "Pass --no-install-recommends to apt-get install",
+	}
+)
+
+// isDockerfile reports whether the path names a Dockerfile
+func isDockerfile(path string) bool {
+	base := filepath.Base(path)
+	return base == "Dockerfile" || strings.HasSuffix(strings.ToLower(base), ".dockerfile")
+}
+
+// dockerInstruction is a logical Dockerfile line with continuations joined
+type dockerInstruction struct {
+	line    int
+	command string
+	args    string
+	raw     string
+}
+
+// parseDockerfile splits content into instructions, joining continuation lines
+func parseDockerfile(content []byte) []dockerInstruction {
+	var instructions []dockerInstruction
+
+	var current strings.Builder
+	start := 0
+	for i, raw := range strings.Split(string(content), "\n") {
+		line := strings.TrimSpace(raw)
+		if current.Len() == 0 {
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			start = i + 1
+		}
+
+		if strings.HasSuffix(line, "\\") {
+			current.WriteString(strings.TrimSuffix(line, "\\"))
+			current.WriteString(" ")
+			continue
+		}
+		current.WriteString(line)
+
+		text := current.String()
+		current.Reset()
+
+		fields := strings.SplitN(text, " ", 2)
+		instruction := dockerInstruction{
+			line:    start,
+			command: strings.ToUpper(fields[0]),
+			raw:     text,
+		}
+		if len(fields) > 1 {
+			instruction.args = strings.TrimSpace(fields[1])
+		}
+		instructions = append(instructions, instruction)
+	}
+
+	return instructions
+}
+
+// dockerfileAnalyzer flags insecure Dockerfile instructions
+type dockerfileAnalyzer struct {
+	config *Config
+}
+
+func (dockerfileAnalyzer) Name() string { return "dockerfile" }
+
+func (dockerfileAnalyzer) CanHandle(path string) bool { return isDockerfile(path) }
+
+func (a dockerfileAnalyzer) Analyze(path string, content []byte) ([]models.Finding, error) {
+	var findings []models.Finding
+	now := a.config.now()
+
+	report := func(check dockerfileCheck, instruction dockerInstruction) {
+		finding := models.Finding{
+			ID:          check.id,
+			RuleID:      check.id,
+			Title:       check.title,
+			Description: check.description,
+			Severity:    check.severity,
+			Category:    "Container",
+			Location:    path,
+			Line:        instruction.line,
+			CodeSnippet: snippet(instruction.raw),
+			Timestamp:   now,
+			Remediation: check.remediation,
+			Confidence:  1.0,
+		}
+		finding.Fingerprint = models.ComputeFingerprint(finding)
+		findings = append(findings, finding)
+	}
+
+	instructions := parseDockerfile(content)
+	stages := make(map[string]bool)
+	hasUser := false
+	var lastFrom dockerInstruction
+
+	for _, instruction := range instructions {
+		switch instruction.command {
+		case "FROM":
+			lastFrom = instruction
+			fields := strings.Fields(instruction.args)
+
+			// Skip flags such as --platform
+			image := ""
+			for i, field := range fields {
+				if strings.HasPrefix(field, "--") {
+					continue
+				}
+				image = field
+				if i+2 < len(fields) && strings.EqualFold(fields[i+1], "AS") {
+					stages[strings.ToLower(fields[i+2])] = true
+				}
+				break
+			}
+
+			if image != "" && image != "scratch" && !stages[strings.ToLower(image)] && !strings.Contains(image, "@sha256:") {
+				report(checkUnpinnedBase, instruction)
+			}
+
+		case "USER":
+			hasUser = true
+			user := strings.SplitN(instruction.args, ":", 2)[0]
+			if user == "root" || user == "0" {
+				report(checkRootUser, instruction)
+			}
+
+		case "ADD":
+			if strings.Contains(instruction.args, "http://") || strings.Contains(instruction.args, "https://") {
+				report(checkRemoteAdd, instruction)
+			}
+
+		case "RUN":
+			if strings.Contains(instruction.args, "apt-get install") && !strings.Contains(instruction.args, "--no-install-recommends") {
+				report(checkAptRecommends, instruction)
+			}
+		}
+	}
+
+	if !hasUser && lastFrom.command != "" {
+		report(checkMissingUser, lastFrom)
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// dockerRules returns the rule IDs and lines of a Dockerfile's findings
+func dockerRules(t *testing.T, content string) []string {
+	t.Helper()
+	findings, err := dockerfileAnalyzer{config: &Config{}}.Analyze("Dockerfile", []byte(content))
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	var got []string
+	for _, finding := range findings {
+		got = append(got, fmt.Sprintf("%s:%d", finding.RuleID, finding.Line))
+	}
+	return got
+}
+
+func TestDockerfileChecks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "clean",
+			content: "FROM golang:1.22@sha256:abc AS build\nRUN apt-get install -y --no-install-recommends git\nFROM scratch\nCOPY --from=build /app /app\nUSER 65532\n",
+		},
+		{
+			name:    "unpinned base",
+			content: "FROM --platform=linux/amd64 alpine:3.19\nUSER app\n",
+			want:    []string{"DOCKER-001:1"},
+		},
+		{
+			name:    "build stage reference",
+			content: "FROM alpine@sha256:abc AS base\nFROM base\nUSER app\n",
+		},
+		{
+			name:    "root user",
+			content: "FROM alpine@sha256:abc\nUSER root:root\n",
+			want:    []string{"DOCKER-002:2"},
+		},
+		{
+			name:    "missing user",
+			content: "FROM alpine@sha256:abc AS build\nFROM scratch\nCOPY --from=build /app /app\n",
+			want:    []string{"DOCKER-003:2"},
+		},
+		{
+			name:    "remote add",
+			content: "FROM alpine@sha256:abc\nADD https://example.com/tool.tar.gz /opt/\nUSER app\n",
+			want:    []string{"DOCKER-004:2"},
+		},
+		{
+			name:    "apt recommends across continuation",
+			content: "FROM debian@sha256:abc\n# install tooling\nRUN apt-get update && \\\n    apt-get install -y curl\nUSER app\n",
+			want:    []string{"DOCKER-005:3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dockerRules(t, tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findings %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDockerfile(t *testing.T) {
+	for path, want := range map[string]bool{
+		"Dockerfile":            true,
+		"build/Dockerfile":      true,
+		"api.Dockerfile":        true,
+		"deploy/web.dockerfile": true,
+		"Dockerfile.md":         false,
+		"docker-compose.yml":    false,
+	} {
+		if got := isDockerfile(path); got != want {
+			t.Errorf("isDockerfile(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
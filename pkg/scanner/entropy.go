@@ -0,0 +1,142 @@
+package scanner
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// secretRuleID identifies findings produced by entropy-based secret detection
+const secretRuleID = "SECRET-ENTROPY"
+
+// token is a candidate secret along with its byte offset in the line
+type token struct {
+	value  string
+	offset int
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
+
+// tokenize splits a line on whitespace and quote characters
+func tokenize(line string) []token {
+	var tokens []token
+
+	start := -1
+	for i, r := range line {
+		delimiter := r == ' ' || r == '\t' || r == '\r' || r == '"' || r == '\'' || r == '`'
+		if delimiter {
+			if start >= 0 {
+				tokens = append(tokens, token{value: line[start:i], offset: start})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{value: line[start:], offset: start})
+	}
+
+	return tokens
+}
+
+// looksLikePath reports whether a token is a file path or URL, which are
+// often long and varied but rarely secrets
+func looksLikePath(s string) bool {
+	if strings.Contains(s, "://") {
+		return true
+	}
+	for _, prefix := range []string{"/", "./", "../", "~/"} {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// secretAnalyzer flags high-entropy tokens that may be hardcoded secrets
+type secretAnalyzer struct {
+	config *Config
+}
+
+func (a *secretAnalyzer) Name() string { return "secrets" }
+
+func (a *secretAnalyzer) CanHandle(path string) bool {
+	return a.config.SecretEntropyThreshold > 0
+}
+
+func (a *secretAnalyzer) Analyze(path string, content []byte) ([]models.Finding, error) {
+	var findings []models.Finding
+	for i, line := range strings.Split(string(content), "\n") {
+		findings = append(findings, a.detectSecrets(path, i+1, line)...)
+	}
+	return findings, nil
+}
+
+// detectSecrets flags high-entropy tokens in a line
+func (a *secretAnalyzer) detectSecrets(path string, lineNum int, line string) []models.Finding {
+	var findings []models.Finding
+
+	for _, tok := range tokenize(line) {
+		length := len(tok.value)
+		if length < a.config.SecretMinLength {
+			continue
+		}
+		if a.config.SecretMaxLength > 0 && length > a.config.SecretMaxLength {
+			continue
+		}
+		if looksLikePath(tok.value) {
+			continue
+		}
+
+		entropy := shannonEntropy(tok.value)
+		if entropy < a.config.SecretEntropyThreshold {
+			continue
+		}
+
+		finding := models.Finding{
+			ID:          secretRuleID,
+			RuleID:      secretRuleID,
+			Title:       "High Entropy String",
+			Description: fmt.Sprintf("Possible hardcoded secret (entropy %.2f bits/char)", entropy),
+			Severity:    models.SeverityHigh,
+			Category:    "secret",
+			Location:    path,
+			Line:        lineNum,
+			Column:      tok.offset + 1,
+			CodeSnippet: snippet(line),
+			Timestamp:   a.config.now(),
+			Remediation: "Move the secret to a secrets manager or environment variable and rotate it",
+			Confidence:  1.0,
+		}
+		finding.Fingerprint = models.ComputeFingerprint(finding)
+
+		findings = append(findings, finding)
+	}
+
+	return findings
+}
@@ -0,0 +1,52 @@
+package scanner
+
+import (
+	"math"
+	"testing"
+)
+
+func TestShannonEntropy(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float64
+	}{
+		{"", 0},
+		{"aaaa", 0},
+		{"abab", 1},
+		{"abcd", 2},
+	}
+	for _, tt := range tests {
+		if got := shannonEntropy(tt.input); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("shannonEntropy(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestEntropySecretDetection(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"config.py": "" +
+			"# The quick brown fox jumps over the lazy dog again and again\n" +
+			"api_token = \"xK9#mQ2$vL8@pR5!wT3&nB7*jH4^fD6%\"\n" +
+			"docs = \"https://example.com/a/very/long/path/to/some/document\"\n",
+	})
+	s := newTestScanner(t, dir, "[]", Config{SecretEntropyThreshold: 4.5, SecretMinLength: 20})
+	findings := byRule(scan(t, s), secretRuleID)
+
+	if len(findings) != 1 {
+		t.Fatalf("got %d secret findings, want 1: %+v", len(findings), findings)
+	}
+	finding := findings[0]
+	if finding.Line != 2 || finding.Column != 14 {
+		t.Errorf("secret at %d:%d, want 2:14", finding.Line, finding.Column)
+	}
+	if finding.Category != "secret" || finding.Severity != "HIGH" {
+		t.Errorf("secret finding is %s %s, want HIGH secret", finding.Severity, finding.Category)
+	}
+}
+
+func TestEntropyDetectionDisabledByDefault(t *testing.T) {
+	dir := writeTree(t, map[string]string{"config.py": "api_token = \"xK9#mQ2$vL8@pR5!wT3&nB7*jH4^fD6%\"\n"})
+	if findings := byRule(scan(t, newTestScanner(t, dir, "[]", Config{})), secretRuleID); len(findings) != 0 {
+		t.Errorf("got %d secret findings with detection disabled", len(findings))
+	}
+}
@@ -0,0 +1,212 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/SofNam/devsecops-ai/pkg/logging"
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// externalOptionsEnv is the environment variable carrying an external
+// analyzer's JSON options
+const externalOptionsEnv = "DEVSECOPS_ANALYZER_OPTIONS"
+
+// defaultExternalTimeout bounds an external analyzer run per file
+const defaultExternalTimeout = 30 * time.Second
+
+// ExternalAnalyzer configures an executable that analyzes files. The file
+// path is passed as the last argument, after Args, and the JSON-encoded
+// Options in the DEVSECOPS_ANALYZER_OPTIONS environment variable. With
+// Stdin set the file content is also written to the tool's stdin. The tool
+// prints a JSON array of findings to stdout; an empty location defaults to
+// the file path and an omitted confidence to 1.
+type ExternalAnalyzer struct {
+	Name    string                 `json:"name" yaml:"name"`
+	Command string                 `json:"command" yaml:"command"`
+	Args    []string               `json:"args" yaml:"args"`
+	Options map[string]interface{} `json:"options" yaml:"options"`
+	Stdin   bool                   `json:"stdin" yaml:"stdin"`
+	// Extensions lists the file extensions (e.g. ".py") or exact file names
+	// the tool applies to; empty applies it to every file
+	Extensions []string `json:"extensions" yaml:"extensions"`
+	// Timeout is a duration such as "10s", defaulting to 30 seconds
+	Timeout string `json:"timeout" yaml:"timeout"`
+}
+
+// externalDocument is the on-disk layout of an external analyzers file
+type externalDocument struct {
+	Analyzers []ExternalAnalyzer `json:"analyzers" yaml:"analyzers"`
+}
+
+// LoadExternalAnalyzers reads external analyzer definitions from a JSON or
+// YAML file holding an "analyzers" list
+func LoadExternalAnalyzers(path string) ([]ExternalAnalyzer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading external analyzers: %v", err)
+	}
+
+	var doc externalDocument
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		err = json.Unmarshal(data, &doc)
+	} else {
+		err = yaml.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing external analyzers %s: %v", path, err)
+	}
+
+	for i, ext := range doc.Analyzers {
+		if ext.Command == "" {
+			return nil, fmt.Errorf("external analyzer #%d: command must not be empty", i+1)
+		}
+		if ext.Name == "" {
+			doc.Analyzers[i].Name = filepath.Base(ext.Command)
+		}
+		if ext.Timeout != "" {
+			if _, err := time.ParseDuration(ext.Timeout); err != nil {
+				return nil, fmt.Errorf("external analyzer %s: invalid timeout: %v", doc.Analyzers[i].Name, err)
+			}
+		}
+	}
+
+	return doc.Analyzers, nil
+}
+
+// RegisterExternal adds an external analyzer to the registry. Failures of
+// the tool are logged as warnings and do not stop the scan.
+func (s *Scanner) RegisterExternal(ext ExternalAnalyzer) error {
+	options, err := json.Marshal(ext.Options)
+	if err != nil {
+		return fmt.Errorf("external analyzer %s: encoding options: %v", ext.Name, err)
+	}
+
+	timeout := defaultExternalTimeout
+	if ext.Timeout != "" {
+		if timeout, err = time.ParseDuration(ext.Timeout); err != nil {
+			return fmt.Errorf("external analyzer %s: invalid timeout: %v", ext.Name, err)
+		}
+	}
+
+	s.Register(&externalAnalyzer{
+		config:  ext,
+		options: string(options),
+		timeout: timeout,
+		logger:  s.logger,
+		now:     s.config.now,
+	})
+	return nil
+}
+
+// externalAnalyzer runs an external tool over each file it handles
+type externalAnalyzer struct {
+	config  ExternalAnalyzer
+	options string
+	timeout time.Duration
+	logger  logging.Logger
+	now     func() time.Time
+}
+
+func (a *externalAnalyzer) Name() string { return "external:" + a.config.Name }
+
+func (a *externalAnalyzer) CanHandle(path string) bool {
+	return len(a.config.Extensions) == 0 || matchesExtension(path, a.config.Extensions)
+}
+
+// uncached opts files out of the result cache, since the tool may change
+// independently of the rule set
+func (a *externalAnalyzer) uncached() {}
+
+func (a *externalAnalyzer) Analyze(path string, content []byte) ([]models.Finding, error) {
+	findings, err := a.run(path, content)
+	if err != nil {
+		a.logger.Warnf("External analyzer %s failed on %s: %v", a.config.Name, path, err)
+		return nil, nil
+	}
+	return findings, nil
+}
+
+// run executes the tool for one file and parses its findings
+func (a *externalAnalyzer) run(path string, content []byte) ([]models.Finding, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+
+	args := append(append([]string(nil), a.config.Args...), path)
+	cmd := exec.CommandContext(ctx, a.config.Command, args...)
+	cmd.Env = append(os.Environ(), externalOptionsEnv+"="+a.options)
+	// Stop waiting for output held open by the tool's children after a kill
+	cmd.WaitDelay = time.Second
+	if a.config.Stdin {
+		cmd.Stdin = bytes.NewReader(content)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("timed out after %s", a.timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%v: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	var findings []models.Finding
+	// confidences records which findings state a confidence, since an
+	// omitted one decodes as zero
+	var confidences []struct {
+		Confidence *float64 `json:"confidence"`
+	}
+	if out := bytes.TrimSpace(stdout.Bytes()); len(out) > 0 {
+		if err := json.Unmarshal(out, &findings); err != nil {
+			return nil, fmt.Errorf("malformed output: %v", err)
+		}
+		if err := json.Unmarshal(out, &confidences); err != nil {
+			return nil, fmt.Errorf("malformed output: %v", err)
+		}
+	}
+
+	now := a.now()
+	for i := range findings {
+		f := &findings[i]
+		if f.Location == "" {
+			f.Location = path
+		}
+		if f.RuleID == "" {
+			f.RuleID = f.ID
+		}
+		if f.Category == "" {
+			f.Category = a.config.Name
+		}
+		if f.Timestamp.IsZero() {
+			f.Timestamp = now
+		}
+		// Like built-in rule matches, findings are certain unless the tool
+		// says otherwise, so the confidence threshold does not drop them
+		if confidences[i].Confidence == nil {
+			f.Confidence = 1.0
+		}
+		if f.Line > 0 && f.CodeSnippet == "" {
+			if lines := strings.Split(string(content), "\n"); f.Line <= len(lines) {
+				f.CodeSnippet = snippet(lines[f.Line-1])
+			}
+		}
+		f.Fingerprint = models.ComputeFingerprint(*f)
+	}
+
+	return findings, nil
+}
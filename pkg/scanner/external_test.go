@@ -0,0 +1,169 @@
+package scanner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeAnalyzerEnv makes the test binary act as an external analyzer
+const fakeAnalyzerEnv = "DEVSECOPS_FAKE_ANALYZER"
+
+// TestFakeAnalyzer is not a test: run by fakeAnalyzer, it behaves as the
+// external tool named by DEVSECOPS_FAKE_ANALYZER
+func TestFakeAnalyzer(t *testing.T) {
+	mode := os.Getenv(fakeAnalyzerEnv)
+	if mode == "" {
+		return
+	}
+	path := os.Args[len(os.Args)-1]
+
+	switch mode {
+	case "findings":
+		fmt.Printf(`[
+			{"id": "EXT-CERTAIN", "title": "Certain", "severity": "high", "line": 2},
+			{"id": "EXT-UNSURE", "title": "Unsure", "severity": "warn", "line": 1, "confidence": 0.4, "location": "elsewhere.py"}
+		]`)
+	case "echo":
+		stdin, _ := io.ReadAll(os.Stdin)
+		fmt.Printf(`[{"id": "ECHO", "description": %q, "title": %q}]`, strings.TrimSpace(string(stdin)), os.Getenv(externalOptionsEnv))
+	case "fail":
+		fmt.Fprintln(os.Stderr, "tool exploded on", filepath.Base(path))
+		os.Exit(3)
+	case "garbage":
+		fmt.Print("not json")
+	case "hang":
+		time.Sleep(time.Minute)
+	}
+	os.Exit(0)
+}
+
+// fakeAnalyzer returns an external analyzer running the test binary in mode
+func fakeAnalyzer(t *testing.T, mode string) ExternalAnalyzer {
+	t.Helper()
+	t.Setenv(fakeAnalyzerEnv, mode)
+	return ExternalAnalyzer{
+		Name:       "fake",
+		Command:    os.Args[0],
+		Args:       []string{"-test.run=^TestFakeAnalyzer$", "--"},
+		Extensions: []string{".py"},
+	}
+}
+
+// externalScan scans dir with only ext registered
+func externalScan(t *testing.T, dir string, ext ExternalAnalyzer) *Scanner {
+	t.Helper()
+	s := newTestScanner(t, dir, testRules, Config{})
+	s.SetAnalyzers()
+	if err := s.RegisterExternal(ext); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestExternalAnalyzerFindings(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"app.py":   "import os\neval(data)\n",
+		"main.go":  "package main\n",
+		"notes.md": "eval(data)\n",
+	})
+	findings := scan(t, externalScan(t, dir, fakeAnalyzer(t, "findings")))
+
+	certain := byRule(findings, "EXT-CERTAIN")
+	unsure := byRule(findings, "EXT-UNSURE")
+	if len(findings) != 2 || len(certain) != 1 || len(unsure) != 1 {
+		t.Fatalf("findings %v, want one of each rule from app.py only", ruleIDs(findings))
+	}
+
+	got := certain[0]
+	if got.Confidence != 1.0 {
+		t.Errorf("omitted confidence reported as %v, want 1", got.Confidence)
+	}
+	if got.Location != filepath.Join(dir, "app.py") || got.CodeSnippet != "eval(data)" {
+		t.Errorf("finding at %s with snippet %q, want app.py's line 2", got.Location, got.CodeSnippet)
+	}
+	if got.Severity != "HIGH" || got.Category != "fake" || got.Fingerprint == "" || got.Timestamp.IsZero() {
+		t.Errorf("finding %+v, want defaults filled in", got)
+	}
+
+	if unsure[0].Confidence != 0.4 || unsure[0].Location != "elsewhere.py" || unsure[0].Severity != "MEDIUM" {
+		t.Errorf("finding %+v, want the tool's confidence and location kept", unsure[0])
+	}
+}
+
+func TestExternalAnalyzerInput(t *testing.T) {
+	dir := writeTree(t, map[string]string{"app.py": "eval(data)\n"})
+	ext := fakeAnalyzer(t, "echo")
+	ext.Stdin = true
+	ext.Options = map[string]interface{}{"level": "strict"}
+
+	findings := scan(t, externalScan(t, dir, ext))
+	if len(findings) != 1 {
+		t.Fatalf("%d findings, want 1", len(findings))
+	}
+	if findings[0].Description != "eval(data)" || findings[0].Title != `{"level":"strict"}` {
+		t.Errorf("tool saw stdin %q and options %q", findings[0].Description, findings[0].Title)
+	}
+}
+
+func TestExternalAnalyzerFailures(t *testing.T) {
+	dir := writeTree(t, map[string]string{"app.py": "eval(data)\n"})
+
+	tests := []struct {
+		mode    string
+		timeout string
+		want    string
+	}{
+		{"fail", "", "exit status 3: tool exploded on app.py"},
+		{"garbage", "", "malformed output"},
+		{"hang", "100ms", "timed out after 100ms"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			ext := fakeAnalyzer(t, tt.mode)
+			ext.Timeout = tt.timeout
+			a := &externalAnalyzer{config: ext, options: "null", timeout: defaultExternalTimeout}
+			if tt.timeout != "" {
+				a.timeout, _ = time.ParseDuration(tt.timeout)
+			}
+
+			if _, err := a.run(filepath.Join(dir, "app.py"), []byte("eval(data)\n")); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("run error %v, want %q", err, tt.want)
+			}
+
+			// A failing tool does not fail the scan
+			if findings := scan(t, externalScan(t, dir, ext)); len(findings) != 0 {
+				t.Errorf("failed tool reported %v", ruleIDs(findings))
+			}
+		})
+	}
+}
+
+func TestLoadExternalAnalyzers(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"ok.yaml":      "analyzers:\n  - command: /usr/bin/semgrep\n    args: [--json]\n    timeout: 5s\n",
+		"nocmd.yaml":   "analyzers:\n  - name: empty\n",
+		"timeout.json": `{"analyzers": [{"command": "tool", "timeout": "soon"}]}`,
+	})
+
+	analyzers, err := LoadExternalAnalyzers(filepath.Join(dir, "ok.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(analyzers) != 1 || analyzers[0].Name != "semgrep" || analyzers[0].Args[0] != "--json" {
+		t.Errorf("analyzers %+v, want semgrep named after its command", analyzers)
+	}
+
+	for name, want := range map[string]string{
+		"nocmd.yaml":   "command must not be empty",
+		"timeout.json": "invalid timeout",
+	} {
+		if _, err := LoadExternalAnalyzers(filepath.Join(dir, name)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: error %v, want %q", name, err, want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package scanner
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestListFilesIncludeExclude(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"main.go":             "package main\n",
+		"app.py":              "password = 'x'\n",
+		"pkg/db/db.go":        "package db\n",
+		"pkg/db/db_test.go":   "package db\n",
+		"vendor/lib/lib.go":   "package lib\n",
+		"docs/guide.md":       "# Guide\n",
+		"testdata/sample.py":  "eval(data)\n",
+		"pkg/x/testdata/y.go": "package y\n",
+	})
+
+	tests := []struct {
+		name             string
+		include, exclude []string
+		want             []string
+	}{
+		{"everything", nil, nil, []string{
+			"app.py", "docs/guide.md", "main.go", "pkg/db/db.go", "pkg/db/db_test.go",
+			"pkg/x/testdata/y.go", "testdata/sample.py", "vendor/lib/lib.go",
+		}},
+		{"include go", []string{"**/*.go"}, nil, []string{
+			"main.go", "pkg/db/db.go", "pkg/db/db_test.go", "pkg/x/testdata/y.go", "vendor/lib/lib.go",
+		}},
+		{"exclude directories", nil, []string{"vendor/**", "**/testdata/**"}, []string{
+			"app.py", "docs/guide.md", "main.go", "pkg/db/db.go", "pkg/db/db_test.go",
+		}},
+		{"exclude wins over include", []string{"**/*.go"}, []string{"**/*_test.go", "vendor/**"}, []string{
+			"main.go", "pkg/db/db.go", "pkg/x/testdata/y.go",
+		}},
+		{"include top level only", []string{"*"}, nil, []string{"app.py", "main.go"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScanner(t, dir, testRules, Config{Include: tt.include, Exclude: tt.exclude})
+			files, err := s.ListFiles()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, file := range files {
+				rel, err := filepath.Rel(dir, file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, filepath.ToSlash(rel))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListFiles = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
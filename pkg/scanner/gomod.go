@@ -0,0 +1,54 @@
+package scanner
+
+import (
+	"strings"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// parseGoMod extracts the required modules from go.mod content
+func parseGoMod(path string, content []byte) []models.Dependency {
+	var deps []models.Dependency
+
+	inBlock := false
+	for i, raw := range strings.Split(string(content), "\n") {
+		line := strings.TrimSpace(raw)
+
+		indirect := false
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			indirect = strings.TrimSpace(line[idx+2:]) == "indirect"
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		switch {
+		case line == "":
+			continue
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case line == "require (":
+			inBlock = true
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "require"))
+		case !inBlock:
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		deps = append(deps, models.Dependency{
+			Name:     fields[0],
+			Version:  fields[1],
+			Type:     "golang",
+			Indirect: indirect,
+			Location: path,
+			Line:     i + 1,
+		})
+	}
+
+	return deps
+}
@@ -0,0 +1,103 @@
+package scanner
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/sbom"
+)
+
+// goModFixture requires modules through both require forms, with one
+// indirect dependency and one listed twice
+const goModFixture = `module example.com/app
+
+go 1.22
+
+require github.com/pkg/errors v0.9.1
+
+require (
+	gopkg.in/yaml.v2 v2.4.0
+	golang.org/x/text v0.3.7 // indirect
+	github.com/pkg/errors v0.9.1
+)
+`
+
+func TestGoModSBOM(t *testing.T) {
+	dir := writeTree(t, map[string]string{"go.mod": goModFixture})
+	s := newTestScanner(t, dir, "[]", Config{})
+	scan(t, s)
+
+	deps := s.Dependencies()
+	if len(deps) != 4 {
+		t.Fatalf("got %d dependencies, want 4: %+v", len(deps), deps)
+	}
+	if deps[2].Name != "golang.org/x/text" || !deps[2].Indirect || deps[2].Line != 9 {
+		t.Errorf("indirect dependency parsed as %+v", deps[2])
+	}
+
+	doc := sbom.Generate(deps, "test")
+	var purls, scopes []string
+	for _, component := range doc.Components {
+		purls = append(purls, component.PURL)
+		scopes = append(scopes, component.Scope)
+	}
+	wantPURLs := []string{
+		"pkg:golang/github.com/pkg/errors@v0.9.1",
+		"pkg:golang/golang.org/x/text@v0.3.7",
+		"pkg:golang/gopkg.in/yaml.v2@v2.4.0",
+	}
+	if !reflect.DeepEqual(purls, wantPURLs) {
+		t.Errorf("component PURLs %v, want %v", purls, wantPURLs)
+	}
+	if want := []string{"required", "optional", "required"}; !reflect.DeepEqual(scopes, want) {
+		t.Errorf("component scopes %v, want %v", scopes, want)
+	}
+	if doc.BOMFormat != "CycloneDX" || doc.SpecVersion != "1.5" {
+		t.Errorf("document is %s %s, want CycloneDX 1.5", doc.BOMFormat, doc.SpecVersion)
+	}
+}
+
+func TestGoModAdvisories(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"go.mod": goModFixture,
+		"advisories.json": `{
+			"gopkg.in/yaml.v2": [{"id": "GO-2022-0001", "summary": "denial of service", "introduced": "v2.0.0", "fixed": "v2.2.8"}],
+			"github.com/pkg/errors": [{"id": "GO-2022-0002", "summary": "panic", "fixed": "v0.9.0"}],
+			"golang.org/x/text": [{"id": "GO-2022-0003", "summary": "out of bounds read", "introduced": "v0.3.0", "fixed": "v0.3.8"}]
+		}`,
+	})
+	s := newTestScanner(t, dir, "[]", Config{AdvisoryPath: filepath.Join(dir, "advisories.json")})
+	findings := scan(t, s)
+
+	// yaml.v2 v2.4.0 and errors v0.9.1 are past their fixes; x/text is not
+	if len(findings) != 1 {
+		t.Fatalf("got %d findings, want 1: %+v", len(findings), findings)
+	}
+	finding := findings[0]
+	if finding.RuleID != "GO-2022-0003" || finding.Line != 9 || finding.Severity != "HIGH" {
+		t.Errorf("got %s at line %d (%s), want GO-2022-0003 at line 9 (HIGH)", finding.RuleID, finding.Line, finding.Severity)
+	}
+	if finding.Remediation != "Upgrade golang.org/x/text to v0.3.8 or later" {
+		t.Errorf("remediation %q", finding.Remediation)
+	}
+}
+
+func TestAdvisoryAffects(t *testing.T) {
+	advisory := Advisory{Introduced: "v1.2.0", Fixed: "v1.4.1"}
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"v1.1.9", false},
+		{"v1.2.0", true},
+		{"v1.4.0", true},
+		{"v1.4.1", false},
+		{"v2.0.0", false},
+	}
+	for _, tt := range tests {
+		if got := advisory.Affects(tt.version); got != tt.want {
+			t.Errorf("Affects(%s) = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+}
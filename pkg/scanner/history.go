@@ -0,0 +1,216 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// HistoryOptions bounds a scan of git history
+type HistoryOptions struct {
+	// Branch is the revision whose history is scanned, defaulting to HEAD
+	Branch string
+	// MaxCommits limits the scan to the most recent commits, zero for all
+	MaxCommits int
+}
+
+// Record and field separators of the commit header in git log output,
+// which cannot occur at the start of a diff line
+const (
+	commitMarker   = "\x1e"
+	commitFieldSep = "\x1f"
+)
+
+// historyCommit identifies the commit being parsed
+type historyCommit struct {
+	hash   string
+	author string
+}
+
+// addedLine is a line a commit added to a file
+type addedLine struct {
+	number int
+	text   string
+}
+
+// ScanHistory analyzes the lines added by each commit in the history of the
+// target's git repository, finding secrets that were committed and later
+// removed. Findings are located at "path@commit" with the commit's Commit
+// and Author set, and line numbers as of that commit. Path filters and
+// profiles apply as in Scan; merge commits are skipped.
+func (s *Scanner) ScanHistory(ctx context.Context, options HistoryOptions) ([]models.Finding, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git not available: %v", err)
+	}
+	if err := s.prepare(); err != nil {
+		return nil, err
+	}
+	if err := s.compileFilters(); err != nil {
+		return nil, err
+	}
+	if err := s.compileProfiles(); err != nil {
+		return nil, err
+	}
+
+	args := []string{"-C", s.config.TargetPath, "log", "--no-merges", "--no-color", "--no-ext-diff",
+		"--relative", "--unified=0", "--patch",
+		"--format=" + commitMarker + "%H" + commitFieldSep + "%an"}
+	if options.MaxCommits > 0 {
+		args = append(args, "-n", strconv.Itoa(options.MaxCommits))
+	}
+	branch := options.Branch
+	if branch == "" {
+		branch = "HEAD"
+	}
+	args = append(args, branch, "--")
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("running git log: %v", err)
+	}
+
+	findings, parseErr := s.parseHistory(stdout)
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("git log: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	return findings, nil
+}
+
+// parseHistory reads git log patch output, analyzing the lines each commit
+// added to each file
+func (s *Scanner) parseHistory(r io.Reader) ([]models.Finding, error) {
+	var (
+		findings []models.Finding
+		commit   historyCommit
+		path     string
+		added    []addedLine
+		next     int
+	)
+
+	flush := func() error {
+		if path != "" && len(added) > 0 {
+			fileFindings, err := s.analyzeCommitFile(commit, path, added)
+			if err != nil {
+				return err
+			}
+			findings = append(findings, fileFindings...)
+		}
+		path, added = "", nil
+		return nil
+	}
+
+	lines := bufio.NewScanner(r)
+	lines.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for lines.Scan() {
+		line := lines.Text()
+		switch {
+		case strings.HasPrefix(line, commitMarker):
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			hash, author, _ := strings.Cut(strings.TrimPrefix(line, commitMarker), commitFieldSep)
+			commit = historyCommit{hash: hash, author: author}
+		case strings.HasPrefix(line, "diff --git "):
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(line, "+++ "):
+			name := strings.TrimPrefix(line, "+++ ")
+			if name == "/dev/null" {
+				path = ""
+				continue
+			}
+			path = strings.TrimPrefix(unquoteGitPath(name), "b/")
+		case strings.HasPrefix(line, "@@ "):
+			next = hunkStart(line)
+		case strings.HasPrefix(line, "+") && path != "":
+			added = append(added, addedLine{number: next, text: line[1:]})
+			next++
+		}
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("reading git log: %v", err)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+// analyzeCommitFile analyzes the lines a commit added to a file, mapping
+// findings back to their lines in the committed file
+func (s *Scanner) analyzeCommitFile(commit historyCommit, rel string, added []addedLine) ([]models.Finding, error) {
+	path := filepath.Join(s.config.TargetPath, filepath.FromSlash(rel))
+	if s.filtered(path) || !s.inProfiles(path) {
+		return nil, nil
+	}
+
+	texts := make([]string, len(added))
+	for i, line := range added {
+		texts[i] = line.text
+	}
+
+	findings, err := s.analyzeSource(path, []byte(strings.Join(texts, "\n")))
+	if err != nil {
+		return nil, fmt.Errorf("analyzing %s at %s: %v", rel, commit.hash, err)
+	}
+
+	short := commit.hash
+	if len(short) > 12 {
+		short = short[:12]
+	}
+	for i := range findings {
+		finding := &findings[i]
+		if finding.Line >= 1 && finding.Line <= len(added) {
+			finding.Line = added[finding.Line-1].number
+		}
+		// Context lines of a diff are not contiguous in the file
+		finding.Context = nil
+		finding.Location = path + "@" + short
+		finding.Commit = commit.hash
+		finding.Author = commit.author
+		finding.Fingerprint = models.FingerprintRelative(*finding, s.config.TargetPath)
+	}
+	return findings, nil
+}
+
+// hunkStart returns the first new-file line number of a hunk header such
+// as "@@ -3,0 +4,2 @@"
+func hunkStart(header string) int {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0
+	}
+	start, _, _ := strings.Cut(strings.TrimPrefix(fields[2], "+"), ",")
+	n, err := strconv.Atoi(start)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// unquoteGitPath decodes a path git quoted because of special characters
+func unquoteGitPath(name string) string {
+	if strings.HasPrefix(name, `"`) {
+		if unquoted, err := strconv.Unquote(name); err == nil {
+			return unquoted
+		}
+	}
+	return name
+}
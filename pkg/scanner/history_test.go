@@ -0,0 +1,129 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// git runs a git command in dir as a fixed author, failing the test on
+// error
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Ada Lovelace", "GIT_AUTHOR_EMAIL=ada@example.com",
+		"GIT_COMMITTER_NAME=Ada Lovelace", "GIT_COMMITTER_EMAIL=ada@example.com",
+		"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// commit writes files into repo and commits them, returning the hash
+func commit(t *testing.T, repo string, files map[string]string) string {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(repo, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git(t, repo, "add", "-A")
+	git(t, repo, "commit", "-q", "-m", "change")
+	return git(t, repo, "rev-parse", "HEAD")
+}
+
+func TestScanHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	repo := t.TempDir()
+	git(t, repo, "init", "-q")
+	first := commit(t, repo, map[string]string{"app.py": "import os\npassword = 'x'\n"})
+	// The password is removed again, so only history still holds it
+	second := commit(t, repo, map[string]string{
+		"app.py":        "import os\nprint(1)\neval(data)\n",
+		"vendor/lib.py": "password = 'v'\n",
+	})
+
+	history := func(config Config, options HistoryOptions) []string {
+		t.Helper()
+		s := newTestScanner(t, repo, testRules, config)
+		findings, err := s.ScanHistory(context.Background(), options)
+		if err != nil {
+			t.Fatalf("ScanHistory: %v", err)
+		}
+		var got []string
+		for _, finding := range findings {
+			if finding.Author != "Ada Lovelace" {
+				t.Errorf("%s author %q, want Ada Lovelace", finding.RuleID, finding.Author)
+			}
+			location := strings.TrimSuffix(finding.Location, "@"+finding.Commit[:12])
+			if location == finding.Location {
+				t.Errorf("%s location %q lacks its commit", finding.RuleID, finding.Location)
+			}
+			rel, err := filepath.Rel(repo, location)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, fmt.Sprintf("%s %s %s %d", finding.RuleID, finding.Commit, filepath.ToSlash(rel), finding.Line))
+		}
+		sort.Strings(got)
+		return got
+	}
+
+	tests := []struct {
+		name    string
+		config  Config
+		options HistoryOptions
+		want    []string
+	}{
+		{"all commits", Config{}, HistoryOptions{}, []string{
+			"EVAL " + second + " app.py 3",
+			"PASSWORD " + first + " app.py 2",
+			"PASSWORD " + second + " vendor/lib.py 1",
+		}},
+		{"filtered paths", Config{Exclude: []string{"vendor/**"}}, HistoryOptions{}, []string{
+			"EVAL " + second + " app.py 3",
+			"PASSWORD " + first + " app.py 2",
+		}},
+		{"latest commit", Config{Exclude: []string{"vendor/**"}}, HistoryOptions{MaxCommits: 1}, []string{
+			"EVAL " + second + " app.py 3",
+		}},
+		{"earlier revision", Config{}, HistoryOptions{Branch: first}, []string{
+			"PASSWORD " + first + " app.py 2",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sort.Strings(tt.want)
+			if got := history(tt.config, tt.options); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findings\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanHistoryOutsideRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	s := newTestScanner(t, t.TempDir(), testRules, Config{})
+	if _, err := s.ScanHistory(context.Background(), HistoryOptions{}); err == nil {
+		t.Error("ScanHistory succeeded outside a git repository")
+	}
+}
@@ -0,0 +1,151 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// isYAML reports whether the path names a YAML file
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// yamlAnalyzer flags insecure Kubernetes settings in every document of a
+// YAML file. Files that are not valid YAML are ignored.
+type yamlAnalyzer struct {
+	config *Config
+}
+
+func (yamlAnalyzer) Name() string { return "yaml" }
+
+func (yamlAnalyzer) CanHandle(path string) bool { return isYAML(path) }
+
+func (a yamlAnalyzer) Analyze(path string, content []byte) ([]models.Finding, error) {
+	var findings []models.Finding
+	now := a.config.now()
+
+	report := func(id, title, description string, severity models.Severity, snippet, remediation string) {
+		finding := models.Finding{
+			ID:          id,
+			RuleID:      id,
+			Title:       title,
+			Description: description,
+			Severity:    severity,
+			Category:    "Kubernetes",
+			Location:    path,
+			CodeSnippet: snippet,
+			Timestamp:   now,
+			Remediation: remediation,
+			Confidence:  1.0,
+		}
+		finding.Fingerprint = models.ComputeFingerprint(finding)
+		findings = append(findings, finding)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var doc interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if err != io.EOF {
+				return findings, nil
+			}
+			break
+		}
+
+		walkYAML(doc, func(key string, value interface{}) {
+			switch key {
+			case "privileged":
+				if value == true {
+					report("K8S-001", "Privileged Container",
+						"A container runs in privileged mode with full access to the host",
+						models.SeverityHigh, "privileged: true",
+						"Remove privileged: true and grant only the capabilities required")
+				}
+
+			case "hostNetwork":
+				if value == true {
+					report("K8S-002", "Host Network Enabled",
+						"The pod shares the host network namespace",
+						models.SeverityHigh, "hostNetwork: true",
+						"Remove hostNetwork: true unless the workload strictly requires it")
+				}
+
+			case "containers", "initContainers":
+				containers, ok := value.([]interface{})
+				if !ok {
+					return
+				}
+				for _, item := range containers {
+					container, ok := item.(map[interface{}]interface{})
+					if !ok {
+						continue
+					}
+					name := fmt.Sprint(container["name"])
+
+					if !hasResourceLimits(container) {
+						report("K8S-003", "Container Without Resource Limits",
+							fmt.Sprintf("Container %s does not set resource limits", name),
+							models.SeverityMedium, "name: "+name,
+							"Set resources.limits for cpu and memory")
+					}
+
+					image, _ := container["image"].(string)
+					if image != "" && !isPinnedImage(image) {
+						report("K8S-004", "Container Image Not Pinned",
+							fmt.Sprintf("Container %s uses image %s without a pinned tag or digest, so imagePullPolicy may pull changed content", name, image),
+							models.SeverityMedium, "image: "+image,
+							"Pin the image to a specific version tag or digest")
+					}
+				}
+			}
+		})
+	}
+
+	return findings, nil
+}
+
+// walkYAML calls fn for every key in every mapping of a decoded YAML document
+func walkYAML(node interface{}, fn func(key string, value interface{})) {
+	switch n := node.(type) {
+	case map[interface{}]interface{}:
+		for k, v := range n {
+			fn(fmt.Sprint(k), v)
+			walkYAML(v, fn)
+		}
+	case []interface{}:
+		for _, item := range n {
+			walkYAML(item, fn)
+		}
+	}
+}
+
+// hasResourceLimits reports whether a container spec sets resources.limits
+func hasResourceLimits(container map[interface{}]interface{}) bool {
+	resources, ok := container["resources"].(map[interface{}]interface{})
+	if !ok {
+		return false
+	}
+	limits, ok := resources["limits"].(map[interface{}]interface{})
+	return ok && len(limits) > 0
+}
+
+// isPinnedImage reports whether an image reference has a digest or a
+// specific tag other than latest
+func isPinnedImage(image string) bool {
+	if strings.Contains(image, "@sha256:") {
+		return true
+	}
+
+	// Ignore a registry port when looking for the tag separator
+	name := image[strings.LastIndex(image, "/")+1:]
+	idx := strings.LastIndex(name, ":")
+	return idx >= 0 && name[idx+1:] != "latest"
+}
@@ -0,0 +1,99 @@
+package scanner
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// podSpec is a pod manifest with a single container, whose security
+// context is completed by the caller
+const podSpec = `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+    - name: web
+      image: nginx:1.25.3
+      resources:
+        limits:
+          cpu: 500m
+          memory: 128Mi
+      securityContext:
+        privileged: %s
+`
+
+// yamlRules returns the sorted rule IDs of a YAML file's findings
+func yamlRules(t *testing.T, content string) []string {
+	t.Helper()
+	findings, err := yamlAnalyzer{config: &Config{}}.Analyze("pod.yaml", []byte(content))
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	var rules []string
+	for _, finding := range findings {
+		rules = append(rules, finding.RuleID)
+	}
+	sort.Strings(rules)
+	return rules
+}
+
+func TestKubernetesPrivileged(t *testing.T) {
+	tests := []struct {
+		privileged string
+		want       int
+	}{
+		{"true", 1},
+		{"false", 0},
+	}
+	for _, tt := range tests {
+		rules := yamlRules(t, fmt.Sprintf(podSpec, tt.privileged))
+		if len(rules) != tt.want || (tt.want == 1 && rules[0] != "K8S-001") {
+			t.Errorf("privileged: %s gave %v, want %d K8S-001 finding(s)", tt.privileged, rules, tt.want)
+		}
+	}
+}
+
+func TestKubernetesMultiDocument(t *testing.T) {
+	content := `apiVersion: v1
+kind: Pod
+spec:
+  hostNetwork: true
+  containers:
+    - name: app
+      image: app:latest
+---
+apiVersion: v1
+kind: ConfigMap
+data:
+  privileged: "true"
+`
+	got := yamlRules(t, content)
+	want := []string{"K8S-002", "K8S-003", "K8S-004"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findings %v, want %v", got, want)
+	}
+}
+
+func TestKubernetesInvalidYAMLIgnored(t *testing.T) {
+	if rules := yamlRules(t, "key: [unterminated\n"); len(rules) != 0 {
+		t.Errorf("invalid YAML gave findings %v", rules)
+	}
+}
+
+func TestIsPinnedImage(t *testing.T) {
+	for image, want := range map[string]bool{
+		"nginx":                      false,
+		"nginx:latest":               false,
+		"nginx:1.25":                 true,
+		"registry:5000/nginx":        false,
+		"registry:5000/nginx:1.25":   true,
+		"nginx@sha256:0123456789abc": true,
+	} {
+		if got := isPinnedImage(image); got != want {
+			t.Errorf("isPinnedImage(%q) = %v, want %v", image, got, want)
+		}
+	}
+}
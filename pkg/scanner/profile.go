@@ -0,0 +1,263 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// Profile bundles the file extensions, rule subset and secret patterns that
+// suit a language. When profiles are active only files matching one of
+// their extensions are scanned.
+type Profile struct {
+	Name string `json:"name" yaml:"name"`
+	// Extensions lists the file extensions (e.g. ".go") or exact file
+	// names (e.g. "go.mod") the profile applies to
+	Extensions []string `json:"extensions" yaml:"extensions"`
+	// Rules restricts the loaded rules applied to matching files to these
+	// IDs; empty applies all loaded rules
+	Rules []string `json:"rules" yaml:"rules"`
+	// SecretPatterns are matched line by line in matching files
+	SecretPatterns []SecretPattern `json:"secretPatterns" yaml:"secretPatterns"`
+}
+
+// SecretPattern is a regular expression identifying a hardcoded secret
+type SecretPattern struct {
+	ID       string `json:"id" yaml:"id"`
+	Name     string `json:"name" yaml:"name"`
+	Pattern  string `json:"pattern" yaml:"pattern"`
+	Severity string `json:"severity" yaml:"severity"`
+
+	compiled *regexp.Regexp
+}
+
+// awsAccessKey is shared by the built-in profiles
+var awsAccessKey = SecretPattern{
+	ID:      "SECRET-AWS-KEY",
+	Name:    "AWS Access Key ID",
+	Pattern: `\b(AKIA|ASIA)[0-9A-Z]{16}\b`,
+}
+
+// builtinProfiles are selectable by name
+var builtinProfiles = map[string]Profile{
+	"go": {
+		Name:       "go",
+		Extensions: []string{".go", "go.mod"},
+		SecretPatterns: []SecretPattern{
+			awsAccessKey,
+			{
+				ID:      "GO-SECRET-001",
+				Name:    "Hardcoded Credential in Go Source",
+				Pattern: `(?i)(password|passwd|secret|token|api_?key)\w*\s*(:=|=|:)\s*"[^"\s]{8,}"`,
+			},
+		},
+	},
+	"python": {
+		Name:       "python",
+		Extensions: []string{".py"},
+		SecretPatterns: []SecretPattern{
+			awsAccessKey,
+			{
+				ID:      "PY-SECRET-001",
+				Name:    "Hardcoded Credential in Python Source",
+				Pattern: `(?i)(password|passwd|secret|token|api_?key)\w*\s*=\s*['"][^'"\s]{8,}['"]`,
+			},
+		},
+	},
+	"javascript": {
+		Name:       "javascript",
+		Extensions: []string{".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx"},
+		SecretPatterns: []SecretPattern{
+			awsAccessKey,
+			{
+				ID:      "JS-SECRET-001",
+				Name:    "Hardcoded Credential in JavaScript Source",
+				Pattern: `(?i)(password|passwd|secret|token|api_?key)\w*\s*[:=]\s*['"` + "`" + `][^'"` + "`" + `\s]{8,}['"` + "`" + `]`,
+			},
+		},
+	},
+}
+
+// BuiltinProfiles returns the names of the built-in profiles
+func BuiltinProfiles() []string {
+	names := make([]string, 0, len(builtinProfiles))
+	for name := range builtinProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadProfile returns the built-in profile with the given name, or reads a
+// custom profile from a YAML or JSON file when name is a path
+func LoadProfile(name string) (Profile, error) {
+	if profile, ok := builtinProfiles[name]; ok {
+		return profile, nil
+	}
+
+	data, err := os.ReadFile(name)
+	if os.IsNotExist(err) && !strings.ContainsAny(name, `/\.`) {
+		return Profile{}, fmt.Errorf("unknown profile %s (built-in profiles: %s)", name, strings.Join(BuiltinProfiles(), ", "))
+	}
+	if err != nil {
+		return Profile{}, fmt.Errorf("reading profile: %v", err)
+	}
+
+	var profile Profile
+	if strings.ToLower(filepath.Ext(name)) == ".json" {
+		err = json.Unmarshal(data, &profile)
+	} else {
+		err = yaml.Unmarshal(data, &profile)
+	}
+	if err != nil {
+		return Profile{}, fmt.Errorf("parsing profile %s: %v", name, err)
+	}
+	if profile.Name == "" {
+		profile.Name = strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	}
+
+	return profile, nil
+}
+
+// compileProfiles compiles the secret patterns of the configured profiles
+func (s *Scanner) compileProfiles() error {
+	s.profiles = make([]Profile, len(s.config.Profiles))
+	for i, profile := range s.config.Profiles {
+		patterns := make([]SecretPattern, len(profile.SecretPatterns))
+		for j, pattern := range profile.SecretPatterns {
+			compiled, err := regexp.Compile(pattern.Pattern)
+			if err != nil {
+				return fmt.Errorf("profile %s: secret pattern %s: %v", profile.Name, pattern.ID, err)
+			}
+			pattern.compiled = compiled
+			patterns[j] = pattern
+		}
+		profile.SecretPatterns = patterns
+		s.profiles[i] = profile
+	}
+	return nil
+}
+
+// matches reports whether the profile applies to the file
+func (p *Profile) matches(path string) bool {
+	return matchesExtension(path, p.Extensions)
+}
+
+// matchesExtension reports whether the file has one of the extensions,
+// compared case-insensitively, or is named exactly like one of them
+func matchesExtension(path string, extensions []string) bool {
+	base := filepath.Base(path)
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range extensions {
+		if strings.EqualFold(e, ext) || e == base {
+			return true
+		}
+	}
+	return false
+}
+
+// profilesFor returns the active profiles that apply to the file
+func (s *Scanner) profilesFor(path string) []*Profile {
+	var matched []*Profile
+	for i := range s.profiles {
+		if s.profiles[i].matches(path) {
+			matched = append(matched, &s.profiles[i])
+		}
+	}
+	return matched
+}
+
+// inProfiles reports whether the file is covered by the active profiles;
+// every file is covered when no profile is active
+func (s *Scanner) inProfiles(path string) bool {
+	return len(s.profiles) == 0 || len(s.profilesFor(path)) > 0
+}
+
+// ruleEnabled reports whether the rule applies to the file under the
+// active profiles' rule subsets
+func (s *Scanner) ruleEnabled(ruleID, path string) bool {
+	if len(s.profiles) == 0 {
+		return true
+	}
+
+	for _, profile := range s.profilesFor(path) {
+		if len(profile.Rules) == 0 {
+			return true
+		}
+		for _, id := range profile.Rules {
+			if id == ruleID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// profileAnalyzer matches the secret patterns of the active profiles
+type profileAnalyzer struct {
+	scanner *Scanner
+}
+
+func (a *profileAnalyzer) Name() string { return "profile" }
+
+func (a *profileAnalyzer) CanHandle(path string) bool {
+	return len(a.scanner.profilesFor(path)) > 0
+}
+
+func (a *profileAnalyzer) Analyze(path string, content []byte) ([]models.Finding, error) {
+	var findings []models.Finding
+
+	// Profiles can share patterns, report each once per line
+	seen := make(map[string]bool)
+
+	for i, line := range strings.Split(string(content), "\n") {
+		for _, profile := range a.scanner.profilesFor(path) {
+			for _, pattern := range profile.SecretPatterns {
+				match := pattern.compiled.FindStringIndex(line)
+				if match == nil {
+					continue
+				}
+
+				key := fmt.Sprintf("%s:%d", pattern.ID, i)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				severity, err := models.ParseSeverity(pattern.Severity)
+				if err != nil {
+					severity = models.SeverityHigh
+				}
+
+				finding := models.Finding{
+					ID:          pattern.ID,
+					RuleID:      pattern.ID,
+					Title:       pattern.Name,
+					Description: fmt.Sprintf("Possible hardcoded secret matching the %s profile", profile.Name),
+					Severity:    severity,
+					Category:    "secret",
+					Location:    path,
+					Line:        i + 1,
+					Column:      match[0] + 1,
+					CodeSnippet: snippet(line),
+					Timestamp:   a.scanner.config.now(),
+					Remediation: "Move the secret to a secrets manager or environment variable and rotate it",
+					Confidence:  0.9,
+					CWE:         "CWE-798",
+				}
+				finding.Fingerprint = models.ComputeFingerprint(finding)
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	return findings, nil
+}
@@ -1,61 +1,717 @@
 package scanner
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
+	"github.com/SofNam/devsecops-ai/pkg/ai"
+	"github.com/SofNam/devsecops-ai/pkg/glob"
+	"github.com/SofNam/devsecops-ai/pkg/logging"
 	"github.com/SofNam/devsecops-ai/pkg/models"
 )
 
 type Config struct {
 	TargetPath string
 	ModelPath  string
+
+	// RulesPath, when set, is a JSON or YAML rules file used instead of
+	// the rules in ModelPath
+	RulesPath string
+
+	// Rules, when non-nil, are the pattern rules used instead of loading
+	// them from RulesPath or ModelPath, such as a detector's current rules
+	Rules []ai.Rule
+
+	// AdvisoryPath points to a JSON file mapping module paths to known
+	// vulnerable version ranges, checked against go.mod requirements
+	AdvisoryPath string
+
+	// SecretEntropyThreshold enables entropy-based secret detection when
+	// positive; tokens at or above it (in bits per character) are flagged
+	SecretEntropyThreshold float64
+	// SecretMinLength and SecretMaxLength bound the candidate token length,
+	// a zero SecretMaxLength means no upper bound
+	SecretMinLength int
+	SecretMaxLength int
+
+	// ScanBinary analyzes files that look binary instead of skipping them
+	ScanBinary bool
+
+	// MaxFileSizeBytes skips files larger than this size, zero means no limit
+	MaxFileSizeBytes int64
+
+	// ScanArchives analyzes the files inside .zip, .tar and .tar.gz archives
+	// in memory, reporting them as "archive.zip!inner/path". Archives nested
+	// inside archives are not opened.
+	ScanArchives bool
+	// MaxArchiveBytes bounds the decompressed content read from a single
+	// archive, defaulting to 100 MiB
+	MaxArchiveBytes int64
+
+	// FollowSymlinks descends into symlinked directories, visiting each
+	// directory once so symlink cycles terminate. When false, symlinked
+	// directories are skipped.
+	FollowSymlinks bool
+
+	// Include and Exclude are slash-separated globs relative to TargetPath
+	// (* within a segment, ** across segments). When Include is non-empty
+	// only matching files are scanned; files and directories matching
+	// Exclude are never scanned.
+	Include []string
+	Exclude []string
+
+	// Profiles restrict the scan to their file extensions and apply their
+	// rule subsets and secret patterns; none scans every file with all rules
+	Profiles []Profile
+
+	// Tags restricts the pattern rules applied to those carrying one of
+	// these tags, compared case-insensitively; empty applies all rules
+	Tags []string
+
+	// ChangedFiles limits analysis to these paths when non-empty, for
+	// incremental scans. Relative paths are resolved against TargetPath
+	// and paths outside it are ignored.
+	ChangedFiles []string
+
+	// ModifiedSince, when non-zero, skips files last modified before it.
+	// Directories are always traversed.
+	ModifiedSince time.Time
+
+	// Workers is the number of files analyzed concurrently, defaulting to
+	// the number of CPUs
+	Workers int
+
+	// SnippetContextLines is the number of lines captured before and after
+	// each finding's line, defaulting to 2; a negative value disables context
+	SnippetContextLines int
+
+	// CacheDir, when set, persists each file's findings keyed by its content
+	// and the active rule set, so unchanged files are not re-analyzed
+	CacheDir string
+
+	// Logger receives diagnostics, defaulting to info level on stderr
+	Logger logging.Logger
+
+	// Progress, when set, is called after each file is analyzed with the
+	// number of files scanned so far and the total. Calls are serialized.
+	Progress func(path string, scanned, total int)
+
+	// Clock supplies the times recorded on findings, defaulting to
+	// time.Now. Pinning it makes findings of identical input identical.
+	Clock func() time.Time
+}
+
+// now returns the current time from the configured clock
+func (c *Config) now() time.Time {
+	if c.Clock != nil {
+		return c.Clock()
+	}
+	return time.Now()
+}
+
+// SkippedFile records a file that was not analyzed and why
+type SkippedFile struct {
+	Path   string
+	Reason string
 }
 
 type Scanner struct {
-	config *Config
+	config     *Config
+	rules      []ai.Rule
+	advisories map[string][]Advisory
+	mu         sync.Mutex
+	skipped    []SkippedFile
+
+	// suppressed holds findings dropped by inline suppression comments
+	suppressed []models.Finding
+
+	// dependencies holds the components declared in manifests found during the scan
+	dependencies []models.Dependency
+
+	// analyzers is the registry of analyzers applied to each file
+	analyzers []Analyzer
+
+	// include and exclude are the compiled path filters
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+
+	// profiles are the configured profiles with compiled patterns
+	profiles []Profile
+
+	// cache holds per-file results between scans, nil when disabled
+	cache *resultCache
+
+	logger logging.Logger
 }
 
 func New(config *Config) *Scanner {
-	return &Scanner{
+	s := &Scanner{
 		config: config,
+		logger: config.Logger,
+	}
+	if s.logger == nil {
+		s.logger = logging.Default()
 	}
+	s.analyzers = s.defaultAnalyzers()
+
+	return s
 }
 
 func (s *Scanner) Scan() ([]models.Finding, error) {
+	out, errc := s.ScanStream(context.Background())
+
 	var findings []models.Finding
+	for finding := range out {
+		findings = append(findings, finding)
+	}
+	if err := <-errc; err != nil {
+		return findings, err
+	}
+
+	// Files finish in any order; group findings by file for stable output
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Location < findings[j].Location
+	})
+
+	return findings, nil
+}
+
+// ScanStream scans the target and emits findings as they are discovered.
+// The findings channel is closed when the scan ends; the error channel then
+// yields at most one error and is closed. Cancelling ctx stops the scan.
+func (s *Scanner) ScanStream(ctx context.Context) (<-chan models.Finding, <-chan error) {
+	out := make(chan models.Finding)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(out)
+
+		if err := s.stream(ctx, out); err != nil {
+			errc <- err
+		}
+	}()
+
+	return out, errc
+}
 
-	// Walk through directory
-	err := filepath.Walk(s.config.TargetPath, func(path string, info os.FileInfo, err error) error {
+// ScanReader scans content read from r as if it were the file name, e.g.
+// for source piped from an editor. name selects the analyzers and is used
+// as the finding location.
+func (s *Scanner) ScanReader(name string, r io.Reader) ([]models.Finding, error) {
+	if err := s.prepare(); err != nil {
+		return nil, err
+	}
+	if err := s.compileProfiles(); err != nil {
+		return nil, err
+	}
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %v", name, err)
+	}
+
+	findings, err := s.analyzeContent(name, content)
+	if err != nil {
+		return nil, fmt.Errorf("analyzing %s: %v", name, err)
+	}
+	return findings, nil
+}
+
+// prepare resets the results of a previous scan and loads rules and
+// advisories
+func (s *Scanner) prepare() error {
+	s.skipped = nil
+	s.suppressed = nil
+	s.dependencies = nil
+
+	if err := s.loadRules(); err != nil {
+		return err
+	}
+	if err := s.openCache(); err != nil {
+		return err
+	}
+
+	if s.config.AdvisoryPath != "" {
+		advisories, err := loadAdvisories(s.config.AdvisoryPath)
 		if err != nil {
-			return err
+			return fmt.Errorf("loading advisories: %v", err)
+		}
+		s.advisories = advisories
+	}
+
+	return nil
+}
+
+// stream analyzes eligible files with a worker pool, sending findings to out
+func (s *Scanner) stream(ctx context.Context, out chan<- models.Finding) error {
+	if err := s.prepare(); err != nil {
+		return err
+	}
+
+	// Count eligible files up front so progress can report a total
+	paths, err := s.listFiles()
+	if err != nil {
+		return err
+	}
+
+	workers := s.config.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		progress sync.Mutex
+		scanned  int
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	jobs := make(chan string)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				findings, err := s.analyzeFile(path)
+				if err != nil {
+					fail(fmt.Errorf("analyzing %s: %v", path, err))
+					continue
+				}
+
+				if s.config.Progress != nil {
+					progress.Lock()
+					scanned++
+					s.config.Progress(path, scanned, len(paths))
+					progress.Unlock()
+				}
+
+				for _, finding := range findings {
+					select {
+					case out <- finding:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, path := range paths {
+		select {
+		case jobs <- path:
+		case <-ctx.Done():
+			break feed
 		}
+	}
+	close(jobs)
+	wg.Wait()
 
-		// Skip directories
-		if info.IsDir() {
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// ListFiles returns the files a scan would analyze, applying the path
+// filters, profiles, change set and size limit without running analyzers
+func (s *Scanner) ListFiles() ([]string, error) {
+	s.skipped = nil
+	return s.listFiles()
+}
+
+// listFiles prepares the file filters and collects the eligible files
+func (s *Scanner) listFiles() ([]string, error) {
+	if err := s.compileFilters(); err != nil {
+		return nil, err
+	}
+	if err := s.compileProfiles(); err != nil {
+		return nil, err
+	}
+	return s.collectFiles()
+}
+
+// compileFilters compiles the include and exclude globs
+func (s *Scanner) compileFilters() error {
+	include, err := glob.CompileAll(s.config.Include)
+	if err != nil {
+		return fmt.Errorf("invalid include pattern: %v", err)
+	}
+	exclude, err := glob.CompileAll(s.config.Exclude)
+	if err != nil {
+		return fmt.Errorf("invalid exclude pattern: %v", err)
+	}
+	s.include, s.exclude = include, exclude
+	return nil
+}
+
+// relPath returns path relative to the target in slash form
+func (s *Scanner) relPath(path string) string {
+	if path == s.config.TargetPath {
+		return filepath.Base(path)
+	}
+	rel, err := filepath.Rel(s.config.TargetPath, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// filtered reports whether the path filters exclude a file
+func (s *Scanner) filtered(path string) bool {
+	rel := s.relPath(path)
+	if glob.MatchAny(s.exclude, rel) {
+		return true
+	}
+	return len(s.include) > 0 && !glob.MatchAny(s.include, rel)
+}
+
+// excludedDir reports whether a directory and everything below it is
+// excluded, so the walk can skip it
+func (s *Scanner) excludedDir(path string) bool {
+	rel := s.relPath(path)
+	return glob.MatchAny(s.exclude, rel) || glob.MatchAny(s.exclude, rel+"/")
+}
+
+// collectFiles walks the target and returns the files eligible for analysis
+func (s *Scanner) collectFiles() ([]string, error) {
+	var paths []string
+
+	changed, err := s.changedFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.walkFiles(s.config.TargetPath, func(path string, info os.FileInfo) error {
+		// Skip files excluded by the path filters
+		if s.filtered(path) {
 			return nil
 		}
 
-		// Analyze file
-		fileFindings, err := s.analyzeFile(path)
-		if err != nil {
-			return fmt.Errorf("analyzing %s: %v", path, err)
+		// Skip files no active profile applies to; profiles apply to the
+		// entries of archives instead
+		if !s.inProfiles(path) && !(s.config.ScanArchives && archiveFormat(path) != "") {
+			return nil
+		}
+
+		// Skip files outside an incremental scan's change set
+		if changed != nil {
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return err
+			}
+			if !changed[abs] {
+				return nil
+			}
 		}
 
-		findings = append(findings, fileFindings...)
+		// Skip files unchanged since the last incremental scan
+		if !s.config.ModifiedSince.IsZero() && info.ModTime().Before(s.config.ModifiedSince) {
+			return nil
+		}
+
+		// Skip files above the size limit
+		if s.config.MaxFileSizeBytes > 0 && info.Size() > s.config.MaxFileSizeBytes {
+			s.skip(path, fmt.Sprintf("size %d bytes exceeds limit of %d", info.Size(), s.config.MaxFileSizeBytes))
+			return nil
+		}
+
+		paths = append(paths, path)
 		return nil
 	})
 
-	return findings, err
+	return paths, err
+}
+
+// changedFiles returns the absolute paths of the configured changed files
+// inside the target, or nil when the whole target should be scanned
+func (s *Scanner) changedFiles() (map[string]bool, error) {
+	if len(s.config.ChangedFiles) == 0 {
+		return nil, nil
+	}
+
+	root, err := filepath.Abs(s.config.TargetPath)
+	if err != nil {
+		return nil, fmt.Errorf("resolving target path: %v", err)
+	}
+
+	changed := make(map[string]bool, len(s.config.ChangedFiles))
+	for _, path := range s.config.ChangedFiles {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+		path = filepath.Clean(path)
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			s.logger.Warnf("Ignoring changed file outside target: %s", path)
+			continue
+		}
+		changed[path] = true
+	}
+
+	return changed, nil
+}
+
+// Skipped returns the files that were not analyzed during the last scan
+func (s *Scanner) Skipped() []SkippedFile {
+	return s.skipped
+}
+
+// Suppressed returns the findings dropped by inline suppression comments
+// during the last scan
+func (s *Scanner) Suppressed() []models.Finding {
+	return s.suppressed
+}
+
+// Dependencies returns the components declared in manifests found during the last scan
+func (s *Scanner) Dependencies() []models.Dependency {
+	return s.dependencies
+}
+
+// skip records a file that was not analyzed
+func (s *Scanner) skip(path, reason string) {
+	s.logger.Infof("Skipping %s: %s", path, reason)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.skipped = append(s.skipped, SkippedFile{Path: path, Reason: reason})
+}
+
+// loadRules loads the pattern rules from the model path, if present,
+// unless they were given in the configuration
+func (s *Scanner) loadRules() error {
+	if s.config.Rules != nil {
+		s.rules = s.config.Rules
+		return nil
+	}
+
+	// Invalid rules are reported by the detector; keep the valid ones
+	var rules []ai.Rule
+	var err error
+	if s.config.RulesPath != "" {
+		rules, err = ai.LoadRules(s.config.RulesPath)
+	} else {
+		rules, err = ai.LoadModelRules(s.config.ModelPath)
+	}
+	var validationErr *ai.ValidationError
+	if err != nil && !errors.As(err, &validationErr) && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("loading rules: %v", err)
+	}
+	s.rules = rules
+
+	return nil
+}
+
+// ruleSelected reports whether the rule carries one of the configured tags
+func (s *Scanner) ruleSelected(rule *ai.Rule) bool {
+	if len(s.config.Tags) == 0 {
+		return true
+	}
+	for _, tag := range rule.Tags {
+		for _, want := range s.config.Tags {
+			if strings.EqualFold(strings.TrimSpace(want), tag) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 func (s *Scanner) analyzeFile(path string) ([]models.Finding, error) {
-	// Implement file analysis logic here
-	// This could include:
-	// - Code pattern matching
-	// - AST analysis
-	// - Dependency checking
-	// - Configuration analysis
-	return nil, nil
+	start := time.Now()
+	defer func() {
+		s.logger.Debugf("Analyzed %s in %s", path, time.Since(start))
+	}()
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Sniff the head before reading the rest so large binaries are skipped
+	// without being loaded
+	head := make([]byte, binarySniffLen)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	head = head[:n]
+
+	archive := s.config.ScanArchives && archiveFormat(path) != ""
+	if !s.config.ScanBinary && !archive && isBinary(head) {
+		s.skip(path, "binary content")
+		return nil, nil
+	}
+
+	rest, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.analyzeContent(path, append(head, rest...))
+}
+
+// analyzeContent runs the applicable analyzers over a file's content, or
+// over the files inside it when it is an archive and archives are scanned
+func (s *Scanner) analyzeContent(path string, content []byte) ([]models.Finding, error) {
+	if s.config.ScanArchives && archiveFormat(path) != "" {
+		return s.analyzeArchive(path, content)
+	}
+	return s.analyzeSource(path, content)
+}
+
+// analyzeSource runs the applicable analyzers over a source file's content
+func (s *Scanner) analyzeSource(path string, content []byte) ([]models.Finding, error) {
+	if !s.config.ScanBinary && isBinary(content) {
+		s.skip(path, "binary content")
+		return nil, nil
+	}
+
+	var key string
+	if s.cacheable(path) {
+		key = s.cache.key(path, content)
+		if entry, ok := s.cache.load(key); ok {
+			s.logger.Debugf("Using cached results for %s", path)
+			s.recordSuppressed(entry.Suppressed)
+			return entry.Findings, nil
+		}
+	}
+
+	var findings []models.Finding
+	for _, analyzer := range s.analyzers {
+		if !analyzer.CanHandle(path) {
+			continue
+		}
+
+		analyzerFindings, err := analyzer.Analyze(path, content)
+		if err != nil {
+			return nil, fmt.Errorf("%s analyzer: %v", analyzer.Name(), err)
+		}
+		findings = append(findings, analyzerFindings...)
+	}
+	// Custom analyzers may report severities in any case or alias
+	findings = models.NormalizeSeverities(findings)
+
+	// Record the snippet language for syntax highlighting in reports
+	if language := models.Language(path); language != "" {
+		for i := range findings {
+			if findings[i].Language == "" {
+				findings[i].Language = language
+			}
+		}
+	}
+
+	// Fingerprint locations relative to the target so they do not depend
+	// on how the target was named
+	models.SetFingerprints(findings, s.config.TargetPath)
+
+	lines := strings.Split(string(content), "\n")
+	s.addContext(findings, lines)
+
+	kept, suppressed := applySuppressions(findings, lines)
+	s.recordSuppressed(suppressed)
+
+	if key != "" {
+		if err := s.cache.store(key, cacheEntry{Findings: kept, Suppressed: suppressed}); err != nil {
+			s.logger.Warnf("Failed to cache results for %s: %v", path, err)
+		}
+	}
+
+	return kept, nil
+}
+
+// addContext attaches the surrounding source lines to findings with a line
+func (s *Scanner) addContext(findings []models.Finding, lines []string) {
+	n := s.config.SnippetContextLines
+	if n == 0 {
+		n = 2
+	}
+	if n < 0 {
+		return
+	}
+
+	// A trailing newline does not start another line
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	for i := range findings {
+		if findings[i].Line > 0 {
+			findings[i].Context = contextLines(lines, findings[i].Line, n)
+		}
+	}
+}
+
+// contextLines returns up to n lines either side of the 1-based line,
+// clamped to the bounds of the file
+func contextLines(lines []string, line, n int) []models.ContextLine {
+	if line > len(lines) {
+		return nil
+	}
+
+	first := line - n
+	if first < 1 {
+		first = 1
+	}
+	last := line + n
+	if last > len(lines) {
+		last = len(lines)
+	}
+
+	context := make([]models.ContextLine, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		text := strings.TrimRight(lines[i-1], "\r")
+		if !utf8.ValidString(text) || strings.IndexByte(text, 0) >= 0 {
+			text = ""
+		}
+		context = append(context, models.ContextLine{Line: i, Text: text, Match: i == line})
+	}
+	return context
+}
+
+// binarySniffLen is how many leading bytes isBinary inspects
+const binarySniffLen = 512
+
+// isBinary reports whether content looks binary by checking its first
+// binarySniffLen bytes for a NUL byte
+func isBinary(content []byte) bool {
+	head := content
+	if len(head) > binarySniffLen {
+		head = head[:binarySniffLen]
+	}
+	return bytes.IndexByte(head, 0) >= 0
+}
+
+// snippet returns the line as a code snippet, or an empty string when the
+// line holds binary data that should not appear in reports
+func snippet(line string) string {
+	if !utf8.ValidString(line) || strings.IndexByte(line, 0) >= 0 {
+		return ""
+	}
+	return strings.TrimSpace(line)
 }
@@ -0,0 +1,412 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SofNam/devsecops-ai/pkg/logging"
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// quietLogger discards diagnostics in tests
+var quietLogger = logging.New(io.Discard, logging.LevelError)
+
+// testRules is a rules document with one rule per interesting pattern
+const testRules = `[
+	{"id": "PASSWORD", "name": "Hardcoded password", "pattern": "password\\s*=", "severity": "high", "category": "Secrets", "description": "d"},
+	{"id": "EVAL", "name": "Eval", "pattern": "eval\\(", "severity": "medium", "category": "Injection", "description": "d"}
+]`
+
+// writeTree creates files, keyed by slash-separated relative path, in a
+// temporary directory and returns the directory
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// newTestScanner returns a scanner of target using rules, with config
+// supplying any further options
+func newTestScanner(t *testing.T, target, rules string, config Config) *Scanner {
+	t.Helper()
+	config.TargetPath = target
+	config.ModelPath = writeTree(t, map[string]string{"rules.json": rules})
+	config.Logger = quietLogger
+	return New(&config)
+}
+
+// scan runs a scan, failing the test on error
+func scan(t *testing.T, s *Scanner) []models.Finding {
+	t.Helper()
+	findings, err := s.Scan()
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	return findings
+}
+
+// byRule returns the findings of a rule
+func byRule(findings []models.Finding, ruleID string) []models.Finding {
+	var matched []models.Finding
+	for _, finding := range findings {
+		if finding.RuleID == ruleID {
+			matched = append(matched, finding)
+		}
+	}
+	return matched
+}
+
+func TestColumnPointsAtMatchStart(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"app.py": "import os\n    db_password = 'x'\nresult = eval(data)\n",
+	})
+	findings := scan(t, newTestScanner(t, dir, testRules, Config{}))
+
+	tests := []struct {
+		rule         string
+		line, column int
+	}{
+		{"PASSWORD", 2, 8},
+		{"EVAL", 3, 10},
+	}
+	for _, tt := range tests {
+		matched := byRule(findings, tt.rule)
+		if len(matched) != 1 {
+			t.Fatalf("%s: got %d findings, want 1", tt.rule, len(matched))
+		}
+		if got := matched[0]; got.Line != tt.line || got.Column != tt.column {
+			t.Errorf("%s at %d:%d, want %d:%d", tt.rule, got.Line, got.Column, tt.line, tt.column)
+		}
+	}
+}
+
+// chdir changes the working directory for the rest of the test
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+// fingerprints returns the fingerprints of findings keyed by rule and line
+func fingerprints(findings []models.Finding) map[string]string {
+	keys := make(map[string]string, len(findings))
+	for _, finding := range findings {
+		keys[fmt.Sprintf("%s:%d", finding.RuleID, finding.Line)] = finding.Fingerprint
+	}
+	return keys
+}
+
+func TestFingerprintsIndependentOfTargetPath(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"src/app.py":     "password = 'x'\n",
+		"src/lib/run.py": "eval(data)\n",
+	})
+	chdir(t, root)
+
+	want := fingerprints(scan(t, newTestScanner(t, "src", testRules, Config{})))
+	if len(want) != 2 {
+		t.Fatalf("got %d findings, want 2", len(want))
+	}
+
+	for _, target := range []string{filepath.Join(root, "src"), "./src/"} {
+		got := fingerprints(scan(t, newTestScanner(t, target, testRules, Config{})))
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("scanning %s: fingerprints %v, want %v", target, got, want)
+		}
+	}
+
+	chdir(t, filepath.Join(root, "src"))
+	got := fingerprints(scan(t, newTestScanner(t, ".", testRules, Config{})))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scanning .: fingerprints %v, want %v", got, want)
+	}
+}
+
+// pngHeader is the start of a PNG image, which contains NUL bytes
+const pngHeader = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x02\x00\x00\x00"
+
+func TestBinaryFilesSkipped(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"logo.png": pngHeader + "password = 'x'\n",
+		"app.py":   "password = 'x'\n",
+	})
+	s := newTestScanner(t, dir, testRules, Config{})
+	findings := byRule(scan(t, s), "PASSWORD")
+
+	if len(findings) != 1 || filepath.Base(findings[0].Location) != "app.py" {
+		t.Fatalf("got findings %+v, want one in app.py", findings)
+	}
+	skipped := s.Skipped()
+	if len(skipped) != 1 || filepath.Base(skipped[0].Path) != "logo.png" || skipped[0].Reason != "binary content" {
+		t.Errorf("skipped %+v, want logo.png as binary content", skipped)
+	}
+}
+
+func TestFindingsPastSniffedHead(t *testing.T) {
+	padding := strings.Repeat("# filler\n", binarySniffLen/len("# filler\n")+1)
+	dir := writeTree(t, map[string]string{
+		"logo.png": pngHeader + padding + "password = 'x'\n",
+		"app.py":   padding + "password = 'x'\n",
+	})
+	s := newTestScanner(t, dir, testRules, Config{})
+	findings := byRule(scan(t, s), "PASSWORD")
+
+	if len(findings) != 1 || filepath.Base(findings[0].Location) != "app.py" {
+		t.Fatalf("got findings %+v, want one in app.py past the sniffed head", findings)
+	}
+	if skipped := s.Skipped(); len(skipped) != 1 || filepath.Base(skipped[0].Path) != "logo.png" {
+		t.Errorf("skipped %+v, want logo.png", skipped)
+	}
+}
+
+func TestBinaryFilesScannedWhenEnabled(t *testing.T) {
+	dir := writeTree(t, map[string]string{"logo.png": pngHeader + "\npassword = 'x'\n"})
+	s := newTestScanner(t, dir, testRules, Config{ScanBinary: true})
+	findings := byRule(scan(t, s), "PASSWORD")
+
+	if len(findings) != 1 {
+		t.Fatalf("got %d findings, want 1", len(findings))
+	}
+	if len(s.Skipped()) != 0 {
+		t.Errorf("skipped %+v, want none", s.Skipped())
+	}
+}
+
+func TestOversizedFilesSkipped(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"big.py":   "password = 'x'\n# " + strings.Repeat("padding ", 16) + "\n",
+		"small.py": "password = 'x'\n",
+	})
+	s := newTestScanner(t, dir, testRules, Config{MaxFileSizeBytes: 64})
+	findings := byRule(scan(t, s), "PASSWORD")
+
+	if len(findings) != 1 || filepath.Base(findings[0].Location) != "small.py" {
+		t.Fatalf("got findings %+v, want one in small.py", findings)
+	}
+	skipped := s.Skipped()
+	if len(skipped) != 1 || filepath.Base(skipped[0].Path) != "big.py" {
+		t.Fatalf("skipped %+v, want big.py", skipped)
+	}
+	if !strings.Contains(skipped[0].Reason, "exceeds limit of 64") {
+		t.Errorf("skip reason %q does not name the limit", skipped[0].Reason)
+	}
+}
+
+func TestProgressCalledPerFile(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"a.py":     "x = 1\n",
+		"b.py":     "password = 'x'\n",
+		"pkg/c.go": "package pkg\n",
+	})
+
+	var calls []int
+	total := 0
+	config := Config{
+		Workers: 2,
+		Progress: func(path string, scanned, n int) {
+			calls = append(calls, scanned)
+			total = n
+		},
+	}
+	scan(t, newTestScanner(t, dir, testRules, config))
+
+	if !reflect.DeepEqual(calls, []int{1, 2, 3}) {
+		t.Errorf("progress counts %v, want [1 2 3]", calls)
+	}
+	if total != 3 {
+		t.Errorf("progress total %d, want 3", total)
+	}
+}
+
+// ruleIDs returns the sorted rule IDs of findings
+func ruleIDs(findings []models.Finding) []string {
+	var ids []string
+	for _, finding := range findings {
+		ids = append(ids, finding.RuleID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// baseNames returns the sorted base names of the locations of findings
+func baseNames(findings []models.Finding) []string {
+	var names []string
+	for _, finding := range findings {
+		names = append(names, filepath.Base(finding.Location))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestChangedFilesOnly(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"outside.py":   "password = 'x'\n",
+		"src/a.py":     "password = 'x'\n",
+		"src/b.py":     "password = 'x'\n",
+		"src/pkg/c.py": "password = 'x'\n",
+		"src/pkg/d.py": "password = 'x'\n",
+	})
+	target := filepath.Join(root, "src")
+	config := Config{ChangedFiles: []string{
+		"a.py",
+		filepath.Join(target, "pkg", "c.py"),
+		filepath.Join(root, "outside.py"),
+		"../outside.py",
+	}}
+
+	got := baseNames(scan(t, newTestScanner(t, target, testRules, config)))
+	if want := []string{"a.py", "c.py"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("scanned %v, want %v", got, want)
+	}
+}
+
+func TestModifiedSince(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"old.py":    "password = 'x'\n",
+		"cutoff.py": "password = 'x'\n",
+		"new.py":    "password = 'x'\n",
+	})
+	cutoff := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for name, mtime := range map[string]time.Time{
+		"old.py":    cutoff.Add(-time.Second),
+		"cutoff.py": cutoff,
+		"new.py":    cutoff.Add(time.Hour),
+	} {
+		if err := os.Chtimes(filepath.Join(root, name), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := baseNames(scan(t, newTestScanner(t, root, testRules, Config{ModifiedSince: cutoff})))
+	if want := []string{"cutoff.py", "new.py"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("scanned %v, want %v", got, want)
+	}
+
+	got = baseNames(scan(t, newTestScanner(t, root, testRules, Config{})))
+	if len(got) != 3 {
+		t.Errorf("scanned %v without a cutoff, want every file", got)
+	}
+}
+
+func TestScanTargets(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"app.py":     "password = 'x'\n",
+		"lib/mod.py": "password = 'x'\nresult = eval(data)\n",
+	})
+
+	single := scan(t, newTestScanner(t, filepath.Join(dir, "lib", "mod.py"), testRules, Config{}))
+	if got := ruleIDs(single); !reflect.DeepEqual(got, []string{"EVAL", "PASSWORD"}) {
+		t.Errorf("single file: rules %v", got)
+	}
+
+	all := scan(t, newTestScanner(t, dir, testRules, Config{}))
+	if got := baseNames(all); !reflect.DeepEqual(got, []string{"app.py", "mod.py", "mod.py"}) {
+		t.Errorf("directory: files %v", got)
+	}
+
+	_, err := newTestScanner(t, filepath.Join(dir, "missing"), testRules, Config{}).Scan()
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing path: err = %v, want os.ErrNotExist", err)
+	}
+}
+
+// contextRange returns the first and last context line of a finding and
+// the line marked as the match
+func contextRange(finding models.Finding) (first, last, match int) {
+	if len(finding.Context) == 0 {
+		return 0, 0, 0
+	}
+	for _, line := range finding.Context {
+		if line.Match {
+			match = line.Line
+		}
+	}
+	return finding.Context[0].Line, finding.Context[len(finding.Context)-1].Line, match
+}
+
+func TestSnippetContext(t *testing.T) {
+	// Seven lines, with matches on the first, middle and last
+	content := "password = 1\nb\nc\npassword = 4\ne\nf\npassword = 7\n"
+	tests := []struct {
+		name         string
+		contextLines int
+		want         map[int][2]int
+	}{
+		{"default", 0, map[int][2]int{1: {1, 3}, 4: {2, 6}, 7: {5, 7}}},
+		{"wider than file", 10, map[int][2]int{1: {1, 7}, 4: {1, 7}, 7: {1, 7}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeTree(t, map[string]string{"app.py": content})
+			findings := scan(t, newTestScanner(t, dir, testRules, Config{SnippetContextLines: tt.contextLines}))
+			if len(findings) != 3 {
+				t.Fatalf("got %d findings, want 3", len(findings))
+			}
+			for _, finding := range findings {
+				first, last, match := contextRange(finding)
+				want := tt.want[finding.Line]
+				if first != want[0] || last != want[1] || match != finding.Line {
+					t.Errorf("line %d: context %d-%d matching %d, want %d-%d matching %d",
+						finding.Line, first, last, match, want[0], want[1], finding.Line)
+				}
+			}
+		})
+	}
+
+	dir := writeTree(t, map[string]string{"app.py": content})
+	for _, finding := range scan(t, newTestScanner(t, dir, testRules, Config{SnippetContextLines: -1})) {
+		if finding.Context != nil {
+			t.Errorf("line %d has context with context disabled", finding.Line)
+		}
+	}
+}
+
+func TestSnippetContextText(t *testing.T) {
+	dir := writeTree(t, map[string]string{"app.py": "first\r\npassword = 'x'\r\nlast\r\n"})
+	finding := scan(t, newTestScanner(t, dir, testRules, Config{SnippetContextLines: 1}))[0]
+
+	want := []models.ContextLine{{Line: 1, Text: "first"}, {Line: 2, Text: "password = 'x'", Match: true}, {Line: 3, Text: "last"}}
+	if !reflect.DeepEqual(finding.Context, want) {
+		t.Errorf("context %+v, want %+v", finding.Context, want)
+	}
+}
+
+func TestFindingsCarryLanguage(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"app.py":    "password = 'x'\n",
+		"notes.cfg": "password = 'x'\n",
+	})
+	findings := scan(t, newTestScanner(t, root, testRules, Config{}))
+	if len(findings) != 2 {
+		t.Fatalf("got %d findings, want one per file", len(findings))
+	}
+	for _, finding := range findings {
+		want := map[string]string{"app.py": "python", "notes.cfg": ""}[filepath.Base(finding.Location)]
+		if finding.Language != want {
+			t.Errorf("%s language %q, want %q", finding.Location, finding.Language, want)
+		}
+	}
+}
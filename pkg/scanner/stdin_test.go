@@ -0,0 +1,59 @@
+package scanner
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestScanReader(t *testing.T) {
+	s := newTestScanner(t, t.TempDir(), testRules, Config{})
+	findings, err := s.ScanReader("snippet.py", strings.NewReader("import os\npassword = 'x'\nresult = eval(data)\n"))
+	if err != nil {
+		t.Fatalf("ScanReader: %v", err)
+	}
+
+	if got, want := ruleIDs(findings), []string{"EVAL", "PASSWORD"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("rules %v, want %v", got, want)
+	}
+	for _, finding := range findings {
+		if finding.Location != "snippet.py" {
+			t.Errorf("%s located at %q, want the given name", finding.RuleID, finding.Location)
+		}
+	}
+	if line := byRule(findings, "EVAL")[0].Line; line != 3 {
+		t.Errorf("EVAL on line %d, want 3", line)
+	}
+}
+
+func TestScanReaderSelectsAnalyzersByName(t *testing.T) {
+	dockerfile := "FROM alpine:latest\nUSER root\n"
+
+	s := newTestScanner(t, t.TempDir(), testRules, Config{})
+	asDockerfile, err := s.ScanReader("Dockerfile", strings.NewReader(dockerfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	asText, err := s.ScanReader("notes.txt", strings.NewReader(dockerfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(asDockerfile) == 0 {
+		t.Error("content named Dockerfile was not analyzed as a Dockerfile")
+	}
+	if len(asText) != 0 {
+		t.Errorf("content named notes.txt gave findings %v", ruleIDs(asText))
+	}
+}
+
+func TestScanReaderError(t *testing.T) {
+	s := newTestScanner(t, t.TempDir(), testRules, Config{})
+	readErr := errors.New("broken pipe")
+	_, err := s.ScanReader("stdin", iotest.ErrReader(readErr))
+	if err == nil || !strings.Contains(err.Error(), "reading stdin: broken pipe") {
+		t.Errorf("ScanReader error %v, want the read error", err)
+	}
+}
@@ -0,0 +1,58 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+func TestScanStreamCancel(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	files := make(map[string]string)
+	for i := 0; i < 50; i++ {
+		files[fmt.Sprintf("app%d.py", i)] = "password = 'x'\nresult = eval(data)\n"
+	}
+	s := newTestScanner(t, writeTree(t, files), testRules, Config{Workers: 4})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out, errc := s.ScanStream(ctx)
+
+	if _, ok := <-out; !ok {
+		t.Fatal("stream closed before its first finding")
+	}
+	cancel()
+
+	received := 1
+	for range out {
+		received++
+	}
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("stream error %v, want context.Canceled", err)
+	}
+	if received == 100 {
+		t.Error("stream delivered every finding despite cancellation")
+	}
+}
+
+func TestScanStreamComplete(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	dir := writeTree(t, map[string]string{"a.py": "password = 'x'\n", "b.py": "eval(data)\n"})
+	out, errc := newTestScanner(t, dir, testRules, Config{}).ScanStream(context.Background())
+
+	received := 0
+	for range out {
+		received++
+	}
+	if err := <-errc; err != nil {
+		t.Errorf("stream error %v", err)
+	}
+	if received != 2 {
+		t.Errorf("received %d findings, want 2", received)
+	}
+}
@@ -0,0 +1,80 @@
+package scanner
+
+import (
+	"strings"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// suppressionMarker introduces an inline suppression comment, followed by a
+// rule ID (or * for all rules) and an optional reason
+const suppressionMarker = "devsec:ignore"
+
+// parseSuppressions maps 1-based line numbers to the rule IDs suppressed there
+func parseSuppressions(lines []string) map[int][]string {
+	suppressions := make(map[int][]string)
+
+	for i, line := range lines {
+		idx := strings.Index(line, suppressionMarker)
+		if idx < 0 {
+			continue
+		}
+
+		fields := strings.Fields(line[idx+len(suppressionMarker):])
+		if len(fields) == 0 {
+			continue
+		}
+		suppressions[i+1] = append(suppressions[i+1], fields[0])
+	}
+
+	return suppressions
+}
+
+// isSuppressed reports whether a suppression on the finding's line or the
+// line above covers its rule
+func isSuppressed(finding models.Finding, suppressions map[int][]string) bool {
+	if finding.Line == 0 {
+		return false
+	}
+
+	for _, line := range []int{finding.Line, finding.Line - 1} {
+		for _, ruleID := range suppressions[line] {
+			if ruleID == "*" || ruleID == finding.RuleID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// applySuppressions splits findings into those kept and those covered by a
+// suppression comment
+func applySuppressions(findings []models.Finding, lines []string) ([]models.Finding, []models.Finding) {
+	suppressions := parseSuppressions(lines)
+	if len(suppressions) == 0 {
+		return findings, nil
+	}
+
+	var kept, suppressed []models.Finding
+	for _, finding := range findings {
+		if isSuppressed(finding, suppressions) {
+			suppressed = append(suppressed, finding)
+		} else {
+			kept = append(kept, finding)
+		}
+	}
+
+	return kept, suppressed
+}
+
+// recordSuppressed records suppressed findings on the scanner
+func (s *Scanner) recordSuppressed(suppressed []models.Finding) {
+	if len(suppressed) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	s.suppressed = append(s.suppressed, suppressed...)
+	s.mu.Unlock()
+}
@@ -0,0 +1,55 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInlineSuppressions(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		kept       []string
+		suppressed []string
+	}{
+		{
+			name:       "same line",
+			content:    "password = 'x'  # devsec:ignore PASSWORD test fixture\nresult = eval(data)\n",
+			kept:       []string{"EVAL"},
+			suppressed: []string{"PASSWORD"},
+		},
+		{
+			name:       "previous line",
+			content:    "# devsec:ignore EVAL trusted input\nresult = eval(data)\npassword = 'x'\n",
+			kept:       []string{"PASSWORD"},
+			suppressed: []string{"EVAL"},
+		},
+		{
+			name:       "wildcard",
+			content:    "# devsec:ignore *\npassword = eval(data)\n",
+			suppressed: []string{"EVAL", "PASSWORD"},
+		},
+		{
+			name:    "other rule",
+			content: "password = 'x'  # devsec:ignore EVAL\n",
+			kept:    []string{"PASSWORD"},
+		},
+		{
+			name:    "two lines above",
+			content: "# devsec:ignore PASSWORD\n\npassword = 'x'\n",
+			kept:    []string{"PASSWORD"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeTree(t, map[string]string{"app.py": tt.content})
+			s := newTestScanner(t, dir, testRules, Config{})
+			if got := ruleIDs(scan(t, s)); !reflect.DeepEqual(got, tt.kept) {
+				t.Errorf("kept %v, want %v", got, tt.kept)
+			}
+			if got := ruleIDs(s.Suppressed()); !reflect.DeepEqual(got, tt.suppressed) {
+				t.Errorf("suppressed %v, want %v", got, tt.suppressed)
+			}
+		})
+	}
+}
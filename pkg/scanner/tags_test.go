@@ -0,0 +1,51 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+// taggedRules tags PASSWORD for PCI and EVAL for the OWASP top ten
+const taggedRules = `[
+	{"id": "PASSWORD", "name": "Password", "pattern": "password\\s*=", "severity": "high", "category": "Secrets", "description": "d", "tags": ["pci", "secrets"]},
+	{"id": "EVAL", "name": "Eval", "pattern": "eval\\(", "severity": "medium", "category": "Injection", "description": "d", "tags": ["owasp-a03"]},
+	{"id": "TODO", "name": "Todo", "pattern": "TODO", "severity": "info", "category": "Hygiene", "description": "d"}
+]`
+
+func TestTagsSelectRules(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"app.py": "password = 'x'\nresult = eval(data)\n# TODO\n",
+	})
+
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{"no tags", nil, []string{"EVAL", "PASSWORD", "TODO"}},
+		{"one tag", []string{"pci"}, []string{"PASSWORD"}},
+		{"case-insensitive", []string{" OWASP-A03 "}, []string{"EVAL"}},
+		{"any of several", []string{"secrets", "owasp-a03"}, []string{"EVAL", "PASSWORD"}},
+		{"unknown tag", []string{"hipaa"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := scan(t, newTestScanner(t, dir, taggedRules, Config{Tags: tt.tags}))
+			if got := ruleIDs(findings); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rules %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindingsCarryRuleTags(t *testing.T) {
+	dir := writeTree(t, map[string]string{"app.py": "password = 'x'\n# TODO\n"})
+	findings := scan(t, newTestScanner(t, dir, taggedRules, Config{}))
+
+	if got := byRule(findings, "PASSWORD")[0].Tags; !reflect.DeepEqual(got, []string{"pci", "secrets"}) {
+		t.Errorf("PASSWORD tags %v, want [pci secrets]", got)
+	}
+	if got := byRule(findings, "TODO")[0].Tags; len(got) != 0 {
+		t.Errorf("TODO tags %v, want none", got)
+	}
+}
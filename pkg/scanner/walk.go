@@ -0,0 +1,101 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// walkFiles calls fn for each regular file below root, or for root itself
+// when it is a regular file. Symlinked directories
+// are skipped unless FollowSymlinks is set, in which case every directory is
+// tracked by identity so symlink cycles cannot be walked more than once.
+func (s *Scanner) walkFiles(root string, fn func(path string, info os.FileInfo) error) error {
+	info, err := os.Stat(root)
+	if err != nil {
+		return fmt.Errorf("accessing target path: %w", err)
+	}
+
+	// A single file target is analyzed directly
+	if info.Mode().IsRegular() {
+		return fn(root, info)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("target path %s is neither a regular file nor a directory", root)
+	}
+
+	return s.walkDir(root, info, make(map[string]bool), fn)
+}
+
+// walkDir walks a single directory, recursing into subdirectories
+func (s *Scanner) walkDir(dir string, info os.FileInfo, visited map[string]bool, fn func(path string, info os.FileInfo) error) error {
+	if s.config.FollowSymlinks {
+		key, err := dirKey(dir, info)
+		if err != nil {
+			return err
+		}
+		if visited[key] {
+			s.skip(dir, "directory already visited through a symlink")
+			return nil
+		}
+		visited[key] = true
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Stat(path)
+			if err != nil {
+				s.skip(path, "broken symlink")
+				continue
+			}
+			if target.IsDir() && !s.config.FollowSymlinks {
+				s.skip(path, "symlinked directory")
+				continue
+			}
+			info = target
+		}
+
+		if info.IsDir() {
+			if s.excludedDir(path) {
+				continue
+			}
+			if err := s.walkDir(path, info, visited, fn); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Devices, sockets and pipes cannot be analyzed meaningfully
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		if err := fn(path, info); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pathKey identifies a directory by its resolved absolute path, for
+// platforms without inode information
+func pathKey(dir string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(resolved)
+}
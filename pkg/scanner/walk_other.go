@@ -0,0 +1,11 @@
+//go:build !unix
+
+package scanner
+
+import "os"
+
+// dirKey identifies a directory by its resolved path, as inode information
+// is not available on this platform
+func dirKey(dir string, info os.FileInfo) (string, error) {
+	return pathKey(dir)
+}
@@ -0,0 +1,64 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// symlink creates a symbolic link, skipping the test where that is not
+// permitted
+func symlink(t *testing.T, target, link string) {
+	t.Helper()
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("creating symlink: %v", err)
+	}
+}
+
+func TestSymlinkCycleTerminates(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"a/app.py":   "password = 'x'\n",
+		"a/b/lib.py": "password = 'x'\n",
+	})
+	// a/b/loop points back at a, and self at the root
+	symlink(t, filepath.Join(dir, "a"), filepath.Join(dir, "a", "b", "loop"))
+	symlink(t, ".", filepath.Join(dir, "self"))
+
+	for _, follow := range []bool{false, true} {
+		s := newTestScanner(t, dir, testRules, Config{FollowSymlinks: follow})
+
+		done := make(chan []string)
+		go func() {
+			findings, err := s.Scan()
+			if err != nil {
+				t.Errorf("Scan: %v", err)
+			}
+			done <- baseNames(findings)
+		}()
+
+		select {
+		case got := <-done:
+			if want := []string{"app.py", "lib.py"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("FollowSymlinks=%v: scanned %v, want %v", follow, got, want)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("FollowSymlinks=%v: scan did not terminate", follow)
+		}
+	}
+}
+
+func TestBrokenSymlinkSkipped(t *testing.T) {
+	dir := writeTree(t, map[string]string{"app.py": "password = 'x'\n"})
+	symlink(t, filepath.Join(dir, "missing.py"), filepath.Join(dir, "dangling.py"))
+
+	s := newTestScanner(t, dir, testRules, Config{})
+	if got := baseNames(scan(t, s)); !reflect.DeepEqual(got, []string{"app.py"}) {
+		t.Errorf("scanned %v", got)
+	}
+	skipped := s.Skipped()
+	if len(skipped) != 1 || skipped[0].Reason != "broken symlink" {
+		t.Errorf("skipped %+v, want the dangling link", skipped)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// dirKey identifies a directory by device and inode so that paths reached
+// through different symlinks compare equal
+func dirKey(dir string, info os.FileInfo) (string, error) {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return fmt.Sprintf("%d:%d", stat.Dev, stat.Ino), nil
+	}
+	return pathKey(dir)
+}
@@ -0,0 +1,55 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// File is a sink appending findings to a file as JSON Lines, one finding
+// per line. It is safe for concurrent use.
+type File struct {
+	mu      sync.Mutex
+	file    *os.File
+	w       *bufio.Writer
+	encoder *json.Encoder
+}
+
+// NewFile opens path for appending, creating it if needed
+func NewFile(path string) (*File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sink file: %v", err)
+	}
+
+	w := bufio.NewWriter(file)
+	return &File{file: file, w: w, encoder: json.NewEncoder(w)}, nil
+}
+
+// Write appends the finding as one line
+func (f *File) Write(ctx context.Context, finding models.Finding) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.encoder.Encode(finding); err != nil {
+		return fmt.Errorf("failed to encode finding: %v", err)
+	}
+	return nil
+}
+
+// Close flushes buffered findings and closes the file
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.w.Flush(); err != nil {
+		f.file.Close()
+		return fmt.Errorf("failed to write sink file: %v", err)
+	}
+	return f.file.Close()
+}
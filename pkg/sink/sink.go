@@ -0,0 +1,93 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// Sink receives findings as the scan pipeline produces them, e.g. to store
+// them in a database alongside the report files
+type Sink interface {
+	// Write stores a single finding
+	Write(ctx context.Context, finding models.Finding) error
+	// Close flushes pending writes and releases the sink
+	Close() error
+}
+
+// Nop is a sink that discards every finding
+type Nop struct{}
+
+// Write discards the finding
+func (Nop) Write(ctx context.Context, finding models.Finding) error { return nil }
+
+// Close does nothing
+func (Nop) Close() error { return nil }
+
+// Fanout writes each finding to several sinks. A failing sink does not stop
+// the others or later writes: failures are counted per sink and reported,
+// together with close errors, by Close. It is safe for concurrent use.
+type Fanout struct {
+	sinks []Sink
+
+	mu       sync.Mutex
+	writes   int
+	failures []int
+	first    []error
+}
+
+// NewFanout returns a sink writing to every given sink
+func NewFanout(sinks ...Sink) *Fanout {
+	return &Fanout{
+		sinks:    sinks,
+		failures: make([]int, len(sinks)),
+		first:    make([]error, len(sinks)),
+	}
+}
+
+// Write sends the finding to every sink, returning the errors of the sinks
+// that failed to store it
+func (f *Fanout) Write(ctx context.Context, finding models.Finding) error {
+	errs := make([]error, len(f.sinks))
+	for i, s := range f.sinks {
+		errs[i] = s.Write(ctx, finding)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.writes++
+	var failed []error
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		f.failures[i]++
+		if f.first[i] == nil {
+			f.first[i] = err
+		}
+		failed = append(failed, fmt.Errorf("sink %d: %w", i+1, err))
+	}
+	return errors.Join(failed...)
+}
+
+// Close closes every sink and returns one error per sink that failed a
+// write or failed to close, or nil when all findings were stored
+func (f *Fanout) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var errs []error
+	for i, s := range f.sinks {
+		if f.failures[i] > 0 {
+			errs = append(errs, fmt.Errorf("sink %d: %d of %d writes failed, first: %w", i+1, f.failures[i], f.writes, f.first[i]))
+		}
+		if err := s.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("sink %d: close: %w", i+1, err))
+		}
+	}
+	return errors.Join(errs...)
+}
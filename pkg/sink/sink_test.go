@@ -0,0 +1,90 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/SofNam/devsecops-ai/pkg/models"
+)
+
+// memory is a sink keeping findings in memory, failing writes of findings
+// whose ID is in fail
+type memory struct {
+	mu       sync.Mutex
+	findings []models.Finding
+	fail     map[string]bool
+	closed   bool
+	closeErr error
+}
+
+func (m *memory) Write(ctx context.Context, finding models.Finding) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.fail[finding.ID] {
+		return errors.New("rejected " + finding.ID)
+	}
+	m.findings = append(m.findings, finding)
+	return nil
+}
+
+func (m *memory) Close() error {
+	m.closed = true
+	return m.closeErr
+}
+
+// ids returns the IDs of the stored findings
+func (m *memory) ids() string {
+	var ids []string
+	for _, finding := range m.findings {
+		ids = append(ids, finding.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestFanoutWritesEverySink(t *testing.T) {
+	first, second := &memory{}, &memory{}
+	f := NewFanout(first, second)
+	for _, id := range []string{"A", "B"} {
+		if err := f.Write(context.Background(), models.Finding{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, m := range []*memory{first, second} {
+		if got := m.ids(); got != "A,B" {
+			t.Errorf("sink %d stored %q, want A,B", i+1, got)
+		}
+		if !m.closed {
+			t.Errorf("sink %d not closed", i+1)
+		}
+	}
+}
+
+func TestFanoutReportsFailures(t *testing.T) {
+	failing := &memory{fail: map[string]bool{"B": true, "C": true}}
+	healthy := &memory{closeErr: errors.New("disk full")}
+	f := NewFanout(failing, healthy)
+
+	for _, id := range []string{"A", "B", "C"} {
+		err := f.Write(context.Background(), models.Finding{ID: id})
+		if failed := failing.fail[id]; (err != nil) != failed {
+			t.Errorf("Write(%s) error %v, want failure %v", id, err, failed)
+		}
+	}
+	if got := healthy.ids(); got != "A,B,C" {
+		t.Errorf("healthy sink stored %q, want every finding", got)
+	}
+
+	err := f.Close()
+	for _, want := range []string{"sink 1: 2 of 3 writes failed, first: rejected B", "sink 2: close: disk full"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Close error %v, want %q", err, want)
+		}
+	}
+}
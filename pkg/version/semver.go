@@ -0,0 +1,108 @@
+package version
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Compare compares two semantic versions, returning -1, 0 or 1.
+// A leading "v", build metadata and the "+incompatible" suffix are ignored.
+// The "dev" sentinel of unreleased builds is older than any release.
+func Compare(a, b string) int {
+	switch {
+	case a == "dev" && b == "dev":
+		return 0
+	case a == "dev":
+		return -1
+	case b == "dev":
+		return 1
+	}
+
+	coreA, preA := splitSemver(a)
+	coreB, preB := splitSemver(b)
+
+	for i := 0; i < 3; i++ {
+		if c := compareNumeric(coreA[i], coreB[i]); c != 0 {
+			return c
+		}
+	}
+
+	// A version without a pre-release has higher precedence
+	switch {
+	case preA == "" && preB == "":
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	}
+
+	idsA := strings.Split(preA, ".")
+	idsB := strings.Split(preB, ".")
+	for i := 0; i < len(idsA) && i < len(idsB); i++ {
+		if c := comparePrerelease(idsA[i], idsB[i]); c != 0 {
+			return c
+		}
+	}
+
+	return compareInts(len(idsA), len(idsB))
+}
+
+// splitSemver returns the major, minor and patch components and the pre-release
+func splitSemver(v string) ([3]string, string) {
+	v = strings.TrimPrefix(v, "v")
+	if idx := strings.Index(v, "+"); idx >= 0 {
+		v = v[:idx]
+	}
+
+	pre := ""
+	if idx := strings.Index(v, "-"); idx >= 0 {
+		v, pre = v[:idx], v[idx+1:]
+	}
+
+	core := [3]string{"0", "0", "0"}
+	for i, part := range strings.SplitN(v, ".", 3) {
+		core[i] = part
+	}
+
+	return core, pre
+}
+
+// comparePrerelease compares pre-release identifiers, numeric identifiers
+// sort before alphanumeric ones
+func comparePrerelease(a, b string) int {
+	_, errA := strconv.Atoi(a)
+	_, errB := strconv.Atoi(b)
+
+	switch {
+	case errA == nil && errB == nil:
+		return compareNumeric(a, b)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// compareNumeric compares decimal strings of arbitrary length
+func compareNumeric(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if c := compareInts(len(a), len(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
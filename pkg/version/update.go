@@ -0,0 +1,77 @@
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// UpdateInfo describes the result of an update check
+type UpdateInfo struct {
+	Current   string `json:"current"`
+	Latest    string `json:"latest"`
+	Available bool   `json:"available"`
+}
+
+// CheckLatest fetches the latest released version from url and reports
+// whether it is newer than the running version. The response may be a plain
+// version string or a JSON object with a "version" or "tag_name" field.
+func CheckLatest(ctx context.Context, url string) (UpdateInfo, error) {
+	info := UpdateInfo{Current: Version}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return info, fmt.Errorf("creating request: %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return info, fmt.Errorf("fetching latest version: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return info, fmt.Errorf("fetching latest version: unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return info, fmt.Errorf("reading latest version: %v", err)
+	}
+
+	latest, err := parseLatest(body)
+	if err != nil {
+		return info, err
+	}
+
+	info.Latest = latest
+	info.Available = Compare(info.Current, latest) < 0
+	return info, nil
+}
+
+// parseLatest extracts the version from an update check response
+func parseLatest(body []byte) (string, error) {
+	text := strings.TrimSpace(string(body))
+
+	if strings.HasPrefix(text, "{") {
+		var release struct {
+			Version string `json:"version"`
+			TagName string `json:"tag_name"`
+		}
+		if err := json.Unmarshal([]byte(text), &release); err != nil {
+			return "", fmt.Errorf("parsing latest version: %v", err)
+		}
+		text = release.Version
+		if text == "" {
+			text = release.TagName
+		}
+	}
+
+	if text == "" {
+		return "", fmt.Errorf("parsing latest version: empty response")
+	}
+	return text, nil
+}
@@ -0,0 +1,102 @@
+package version
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// withVersion sets the running version for the rest of the test
+func withVersion(t *testing.T, v string) {
+	t.Helper()
+	previous := Version
+	Version = v
+	t.Cleanup(func() { Version = previous })
+}
+
+func TestCheckLatest(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   string
+		body      string
+		latest    string
+		available bool
+	}{
+		{"plain text newer", "v1.2.0", "v1.3.0\n", "v1.3.0", true},
+		{"json version same", "1.3.0", `{"version": "v1.3.0"}`, "v1.3.0", false},
+		{"github tag older", "v2.0.0", `{"tag_name": "v1.9.9"}`, "v1.9.9", false},
+		{"dev build", "dev", "v0.1.0", "v0.1.0", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withVersion(t, tt.current)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			info, err := CheckLatest(context.Background(), server.URL)
+			if err != nil {
+				t.Fatalf("CheckLatest: %v", err)
+			}
+			if info.Current != tt.current || info.Latest != tt.latest || info.Available != tt.available {
+				t.Errorf("got %+v, want latest %s available %v", info, tt.latest, tt.available)
+			}
+		})
+	}
+}
+
+func TestCheckLatestErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"server error", http.StatusInternalServerError, "v9.9.9"},
+		{"empty body", http.StatusOK, "  \n"},
+		{"invalid json", http.StatusOK, `{"version": `},
+		{"json without version", http.StatusOK, `{"name": "release"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			if info, err := CheckLatest(context.Background(), server.URL); err == nil {
+				t.Errorf("got %+v, want an error", info)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "1.2.3", 0},
+		{"v1.2.3", "v1.2.4", -1},
+		{"v1.10.0", "v1.9.0", 1},
+		{"v2.0.0", "v10.0.0", -1},
+		{"v1.0.0-rc.1", "v1.0.0", -1},
+		{"v1.0.0-alpha", "v1.0.0-alpha.1", -1},
+		{"v1.0.0-alpha.2", "v1.0.0-alpha.10", -1},
+		{"v1.0.0-beta", "v1.0.0-alpha.1", 1},
+		{"v1.0.0+build.5", "v1.0.0", 0},
+		{"v2.0.0+incompatible", "v2.0.0", 0},
+		{"dev", "v0.0.1", -1},
+		{"dev", "dev", 0},
+	}
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Compare(tt.b, tt.a); got != -tt.want {
+			t.Errorf("Compare(%s, %s) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}